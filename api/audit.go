@@ -0,0 +1,416 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// systemActor is recorded as the actor of writes made without an actor in their context.
+const systemActor = "system"
+
+// Actions recorded in the audit log.
+const (
+	actionRoomCreate      = "room.create"
+	actionRoomUpdate      = "room.update"
+	actionRoomDelete      = "room.delete"
+	actionNightCreate     = "availability.create"
+	actionNightUpdate     = "availability.update"
+	actionCalendarClear   = "availability.clear"
+	actionCalendarArchive = "availability.archive"
+	actionCalendarExtend  = "availability.extend"
+	actionCalendarImport  = "availability.import"
+	actionCalendarDelete  = "availability.delete"
+	actionAPIKeyCreate    = "api_key.create"
+	actionWebhookCreate   = "webhook.create"
+	actionWebhookDelete   = "webhook.delete"
+)
+
+// actorKey is the context key under which the actor of writes is stored.
+type actorKey struct{}
+
+// WithActor returns a context whose writes are recorded in the audit log as made by actor,
+// e.g. the name of the API key authenticating a request or the name of a background job.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// actorFromContext returns the actor of the writes made with ctx.
+func actorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return systemActor
+}
+
+// auditedQueries records an audit event for every write, in the same transaction as the write, and writes
+// the events of the writes to rooms and calendars to the outbox. Reads are passed through to the wrapped queries.
+type auditedQueries struct {
+	db.Querier
+	// touched collects the rooms written by the transaction, whose cached metrics are dropped once it commits.
+	touched *touchedRooms
+}
+
+// touchedRooms is the set of rooms written by a transaction.
+type touchedRooms struct {
+	roomIDs map[int32]bool
+	all     bool // Set by writes to the calendars of every room.
+}
+
+// add adds a room to the set.
+func (touched *touchedRooms) add(roomID int32) {
+	if touched.roomIDs == nil {
+		touched.roomIDs = map[int32]bool{}
+	}
+	touched.roomIDs[roomID] = true
+}
+
+// record appends an event about a room, or one of its nights if date is valid, to the audit log.
+func (q auditedQueries) record(ctx context.Context, action string, roomID int32, date pgtype.Date, before, after any) error {
+	q.touched.add(roomID)
+	return q.recordEvent(ctx, db.CreateAuditEventParams{
+		Action: action,
+		RoomID: pgtype.Int4{Int32: roomID, Valid: true},
+		Date:   date,
+	}, before, after)
+}
+
+// recordEvent appends an event to the audit log. before and after are the states of the changed row,
+// reduced to the fields that differ; nil stands for a row that does not exist.
+// The actor and the request ID are taken from ctx.
+func (q auditedQueries) recordEvent(ctx context.Context, event db.CreateAuditEventParams, before, after any) error {
+	var err error
+	event.Before, event.After, err = auditDiff(before, after)
+	if err != nil {
+		return err
+	}
+
+	requestID := requestIDFromContext(ctx)
+	event.Actor = actorFromContext(ctx)
+	event.RequestID = pgtype.Text{String: requestID, Valid: requestID != ""}
+	_, err = q.Querier.CreateAuditEvent(ctx, event)
+	return err
+}
+
+// auditDiff returns the JSON objects recorded as the before and after states of a row.
+// Fields with the same value in both states are left out, as are updated_at and booked_at
+// which the event timestamp already records.
+func auditDiff(before, after any) ([]byte, []byte, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if beforeFields != nil && afterFields != nil {
+		for _, field := range slices.Collect(maps.Keys(beforeFields)) {
+			if value, ok := afterFields[field]; ok && reflect.DeepEqual(value, beforeFields[field]) {
+				delete(beforeFields, field)
+				delete(afterFields, field)
+			}
+		}
+	}
+
+	beforeJSON, err := marshalAuditFields(beforeFields)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterJSON, err := marshalAuditFields(afterFields)
+	if err != nil {
+		return nil, nil, err
+	}
+	return beforeJSON, afterJSON, nil
+}
+
+// auditFields converts a row to its JSON fields, or nil for a missing row.
+func auditFields(row any) (map[string]any, error) {
+	if row == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(row)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode audit state: %w", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("cannot encode audit state: %w", err)
+	}
+	delete(fields, "updated_at")
+	delete(fields, "booked_at")
+	return fields, nil
+}
+
+// marshalAuditFields encodes the fields of a state, or returns nil (SQL NULL) for a missing row.
+func marshalAuditFields(fields map[string]any) ([]byte, error) {
+	if fields == nil {
+		return nil, nil
+	}
+	return json.Marshal(fields)
+}
+
+// nightCount is the state recorded for writes to many nights of a calendar at once.
+type nightCount struct {
+	Nights int64 `json:"nights"`
+}
+
+// getRoom returns the current state of a room, or nil if it does not exist.
+func (q auditedQueries) getRoom(ctx context.Context, roomID int32) (any, error) {
+	room, err := q.Querier.GetRoom(ctx, roomID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return room, err
+}
+
+// getNight returns the current state of a night, or nil if it does not exist.
+func (q auditedQueries) getNight(ctx context.Context, roomID int32, date pgtype.Date) (any, error) {
+	night, err := q.Querier.GetRoomAvailabilityByDate(ctx, db.GetRoomAvailabilityByDateParams{RoomID: roomID, Date: date})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return night, err
+}
+
+// updateRoom runs an update of a room and records its before and after states.
+func (q auditedQueries) updateRoom(ctx context.Context, roomID int32, update func() (db.Room, error)) (db.Room, error) {
+	before, err := q.getRoom(ctx, roomID)
+	if err != nil {
+		return db.Room{}, err
+	}
+	room, err := update()
+	if err != nil {
+		return room, err
+	}
+	if existing, ok := before.(db.Room); ok && existing.Version == room.Version {
+		return room, nil // Nothing changed.
+	}
+	action := actionRoomUpdate
+	if before == nil {
+		action = actionRoomCreate
+	}
+	if err := q.record(ctx, action, roomID, pgtype.Date{}, before, room); err != nil {
+		return room, err
+	}
+	return room, q.emitRoom(ctx, roomID, before, room)
+}
+
+// updateNight runs an update of a night and records its before and after states.
+func (q auditedQueries) updateNight(ctx context.Context, roomID int32, date pgtype.Date, update func() (db.RoomAvailability, error)) (db.RoomAvailability, error) {
+	before, err := q.getNight(ctx, roomID, date)
+	if err != nil {
+		return db.RoomAvailability{}, err
+	}
+	night, err := update()
+	if err != nil {
+		return night, err
+	}
+	action := actionNightUpdate
+	if before == nil {
+		action = actionNightCreate
+	}
+	if err := q.record(ctx, action, roomID, date, before, night); err != nil {
+		return night, err
+	}
+	return night, q.emitNight(ctx, before, night)
+}
+
+func (q auditedQueries) CreateRoom(ctx context.Context, arg db.CreateRoomParams) (db.Room, error) {
+	room, err := q.Querier.CreateRoom(ctx, arg)
+	if err != nil {
+		return room, err
+	}
+	if err := q.record(ctx, actionRoomCreate, arg.RoomID, pgtype.Date{}, nil, room); err != nil {
+		return room, err
+	}
+	return room, q.emitRoom(ctx, arg.RoomID, nil, room)
+}
+
+func (q auditedQueries) UpsertRoom(ctx context.Context, arg db.UpsertRoomParams) (db.Room, error) {
+	return q.updateRoom(ctx, arg.RoomID, func() (db.Room, error) { return q.Querier.UpsertRoom(ctx, arg) })
+}
+
+func (q auditedQueries) UpdateRoom(ctx context.Context, arg db.UpdateRoomParams) (db.Room, error) {
+	return q.updateRoom(ctx, arg.RoomID, func() (db.Room, error) { return q.Querier.UpdateRoom(ctx, arg) })
+}
+
+func (q auditedQueries) UpdateMaxGuests(ctx context.Context, arg db.UpdateMaxGuestsParams) (db.Room, error) {
+	return q.updateRoom(ctx, arg.RoomID, func() (db.Room, error) { return q.Querier.UpdateMaxGuests(ctx, arg) })
+}
+
+func (q auditedQueries) UpdateRoomConsole(ctx context.Context, arg db.UpdateRoomConsoleParams) (db.Room, error) {
+	return q.updateRoom(ctx, arg.RoomID, func() (db.Room, error) { return q.Querier.UpdateRoomConsole(ctx, arg) })
+}
+
+func (q auditedQueries) UpdateRoomFridge(ctx context.Context, arg db.UpdateRoomFridgeParams) (db.Room, error) {
+	return q.updateRoom(ctx, arg.RoomID, func() (db.Room, error) { return q.Querier.UpdateRoomFridge(ctx, arg) })
+}
+
+func (q auditedQueries) DeleteRoom(ctx context.Context, roomID int32) error {
+	before, err := q.getRoom(ctx, roomID)
+	if err != nil {
+		return err
+	}
+	if err := q.Querier.DeleteRoom(ctx, roomID); err != nil || before == nil {
+		return err
+	}
+	if err := q.record(ctx, actionRoomDelete, roomID, pgtype.Date{}, before, nil); err != nil {
+		return err
+	}
+	return q.emitRoom(ctx, roomID, before, nil)
+}
+
+func (q auditedQueries) CreateRoomAvailability(ctx context.Context, arg db.CreateRoomAvailabilityParams) (db.RoomAvailability, error) {
+	night, err := q.Querier.CreateRoomAvailability(ctx, arg)
+	if err != nil {
+		return night, err
+	}
+	if err := q.record(ctx, actionNightCreate, arg.RoomID, arg.Date, nil, night); err != nil {
+		return night, err
+	}
+	return night, q.emitNight(ctx, nil, night)
+}
+
+func (q auditedQueries) UpdateRoomAvailability(ctx context.Context, arg db.UpdateRoomAvailabilityParams) (db.RoomAvailability, error) {
+	return q.updateNight(ctx, arg.RoomID, arg.Date, func() (db.RoomAvailability, error) { return q.Querier.UpdateRoomAvailability(ctx, arg) })
+}
+
+func (q auditedQueries) UpsertRoomAvailability(ctx context.Context, arg db.UpsertRoomAvailabilityParams) (db.RoomAvailability, error) {
+	return q.updateNight(ctx, arg.RoomID, arg.Date, func() (db.RoomAvailability, error) { return q.Querier.UpsertRoomAvailability(ctx, arg) })
+}
+
+func (q auditedQueries) DeleteAllAvailabilityForRoom(ctx context.Context, roomID int32) ([]pgtype.Date, error) {
+	deleted, err := q.Querier.DeleteAllAvailabilityForRoom(ctx, roomID)
+	if err != nil || len(deleted) == 0 {
+		return deleted, err
+	}
+	if err := q.record(ctx, actionCalendarClear, roomID, pgtype.Date{}, nightCount{int64(len(deleted))}, nil); err != nil {
+		return deleted, err
+	}
+	return deleted, q.emitCalendar(ctx, actionCalendarClear, roomID, deleted)
+}
+
+func (q auditedQueries) ArchiveOldRoomAvailabilityData(ctx context.Context) error {
+	q.touched.all = true
+	// Recorded per room, so that the event shows up in the history of every room.
+	roomIDs, err := q.Querier.ListAllRoomIDs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, roomID := range roomIDs {
+		if _, err := q.ArchiveRoomPastAvailability(ctx, roomID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (q auditedQueries) ArchiveRoomPastAvailability(ctx context.Context, roomID int32) ([]pgtype.Date, error) {
+	archived, err := q.Querier.ArchiveRoomPastAvailability(ctx, roomID)
+	if err != nil || len(archived) == 0 {
+		return archived, err
+	}
+	if err := q.record(ctx, actionCalendarArchive, roomID, pgtype.Date{}, nightCount{int64(len(archived))}, nil); err != nil {
+		return archived, err
+	}
+	return archived, q.emitCalendar(ctx, actionCalendarArchive, roomID, archived)
+}
+
+func (q auditedQueries) ExtendRoomAvailability(ctx context.Context, arg db.ExtendRoomAvailabilityParams) ([]pgtype.Date, error) {
+	added, err := q.Querier.ExtendRoomAvailability(ctx, arg)
+	if err != nil || len(added) == 0 {
+		return added, err
+	}
+	if err := q.record(ctx, actionCalendarExtend, arg.RoomID, pgtype.Date{}, nil, nightCount{int64(len(added))}); err != nil {
+		return added, err
+	}
+	return added, q.emitCalendar(ctx, actionCalendarExtend, arg.RoomID, added)
+}
+
+func (q auditedQueries) CopyRoomAvailability(ctx context.Context, arg []db.CopyRoomAvailabilityParams) (int64, error) {
+	copied, err := q.Querier.CopyRoomAvailability(ctx, arg)
+	if err != nil {
+		return copied, err
+	}
+
+	// Bulk loads are recorded as one event per room rather than one per night.
+	dates := map[int32][]pgtype.Date{}
+	for _, row := range arg {
+		dates[row.RoomID] = append(dates[row.RoomID], row.Date)
+	}
+	for _, roomID := range slices.Sorted(maps.Keys(dates)) {
+		if err := q.record(ctx, actionCalendarImport, roomID, pgtype.Date{}, nil, nightCount{int64(len(dates[roomID]))}); err != nil {
+			return copied, err
+		}
+		if err := q.emitCalendar(ctx, actionCalendarImport, roomID, dates[roomID]); err != nil {
+			return copied, err
+		}
+	}
+	return copied, nil
+}
+
+func (q auditedQueries) UpsertRoomAvailabilityNights(ctx context.Context, arg db.UpsertRoomAvailabilityNightsParams) ([]db.UpsertRoomAvailabilityNightsRow, error) {
+	written, err := q.Querier.UpsertRoomAvailabilityNights(ctx, arg)
+	if err != nil {
+		return written, err
+	}
+
+	// Recorded like bulk loads, whether the nights were inserted or updated.
+	dates := map[int32][]pgtype.Date{}
+	for _, night := range written {
+		dates[night.RoomID] = append(dates[night.RoomID], night.Date)
+	}
+	for _, roomID := range slices.Sorted(maps.Keys(dates)) {
+		if err := q.record(ctx, actionCalendarImport, roomID, pgtype.Date{}, nil, nightCount{int64(len(dates[roomID]))}); err != nil {
+			return written, err
+		}
+		if err := q.emitCalendar(ctx, actionCalendarImport, roomID, dates[roomID]); err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+func (q auditedQueries) DeleteRoomAvailabilityNights(ctx context.Context, arg db.DeleteRoomAvailabilityNightsParams) ([]db.DeleteRoomAvailabilityNightsRow, error) {
+	deleted, err := q.Querier.DeleteRoomAvailabilityNights(ctx, arg)
+	if err != nil {
+		return deleted, err
+	}
+
+	dates := map[int32][]pgtype.Date{}
+	for _, night := range deleted {
+		dates[night.RoomID] = append(dates[night.RoomID], night.Date)
+	}
+	for _, roomID := range slices.Sorted(maps.Keys(dates)) {
+		if err := q.record(ctx, actionCalendarDelete, roomID, pgtype.Date{}, nightCount{int64(len(dates[roomID]))}, nil); err != nil {
+			return deleted, err
+		}
+		if err := q.emitCalendar(ctx, actionCalendarDelete, roomID, dates[roomID]); err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
+
+func (q auditedQueries) CreateAPIKey(ctx context.Context, arg db.CreateAPIKeyParams) (db.ApiKey, error) {
+	key, err := q.Querier.CreateAPIKey(ctx, arg)
+	if err != nil {
+		return key, err
+	}
+	// The hash is left out, as the audit log is not meant to hold secrets.
+	state := struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}{key.ID, key.Name}
+	return key, q.recordEvent(ctx, db.CreateAuditEventParams{Action: actionAPIKeyCreate}, nil, state)
+}
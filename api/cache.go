@@ -0,0 +1,195 @@
+package api
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults for the room metrics cache.
+const (
+	metricsCacheSize = 1024
+	metricsCacheTTL  = 5 * time.Minute
+)
+
+// metricsWindowDays is the number of days covered by the rate statistics of a room.
+const metricsWindowDays = 30
+
+// metricsKey identifies a cached metrics computation.
+type metricsKey struct {
+	RoomID int32
+	Window int
+}
+
+// metricsEntry is a serialized metrics response along with its entity tag.
+type metricsEntry struct {
+	key       metricsKey
+	body      []byte
+	etag      string
+	day       string
+	expiresAt time.Time
+}
+
+// metricsGeneration counts the invalidations of the entries of a room. Metrics computed before an
+// invalidation may be stale, so they are only stored while the generation they were computed at is current.
+type metricsGeneration struct {
+	purges uint64 // Invalidations of every room.
+	writes uint64 // Invalidations of the room since the last purge.
+}
+
+// metricsCache is a size bounded, least recently used cache of serialized room metrics.
+// Entries expire after a TTL and at midnight, since the metrics are relative to the current date.
+type metricsCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	now     func() time.Time
+	order   *list.List
+	entries map[metricsKey]*list.Element
+	purges  uint64
+	writes  map[int32]uint64
+}
+
+// newMetricsCache creates a cache holding at most size entries for at most ttl.
+func newMetricsCache(size int, ttl time.Duration) *metricsCache {
+	return &metricsCache{
+		size:    size,
+		ttl:     ttl,
+		now:     time.Now,
+		order:   list.New(),
+		entries: make(map[metricsKey]*list.Element),
+		writes:  make(map[int32]uint64),
+	}
+}
+
+// generation returns the current generation of the entries of a room, to be read before computing them.
+func (cache *metricsCache) generation(roomID int32) metricsGeneration {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	return metricsGeneration{purges: cache.purges, writes: cache.writes[roomID]}
+}
+
+// get returns the live entry stored under key, if any.
+func (cache *metricsCache) get(key metricsKey) (*metricsEntry, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	element, ok := cache.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*metricsEntry)
+	now := cache.now()
+	if now.After(entry.expiresAt) || now.Format(dateLayout) != entry.day {
+		cache.order.Remove(element)
+		delete(cache.entries, key)
+		return nil, false
+	}
+
+	cache.order.MoveToFront(element)
+	return entry, true
+}
+
+// put stores the serialized metrics computed at the given generation under key, evicting the least recently
+// used entry when full. The entry is returned without being stored if the room was invalidated since.
+func (cache *metricsCache) put(key metricsKey, body []byte, generation metricsGeneration) *metricsEntry {
+	now := cache.now()
+	entry := &metricsEntry{
+		key:       key,
+		body:      body,
+		etag:      computeETag(body),
+		day:       now.Format(dateLayout),
+		expiresAt: now.Add(cache.ttl),
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if generation != (metricsGeneration{purges: cache.purges, writes: cache.writes[key.RoomID]}) {
+		return entry
+	}
+	if element, ok := cache.entries[key]; ok {
+		element.Value = entry
+		cache.order.MoveToFront(element)
+		return entry
+	}
+
+	cache.entries[key] = cache.order.PushFront(entry)
+	for cache.order.Len() > cache.size {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*metricsEntry).key)
+	}
+	return entry
+}
+
+// invalidate drops every cached entry of the given room.
+func (cache *metricsCache) invalidate(roomID int32) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.writes[roomID]++
+	for key, element := range cache.entries {
+		if key.RoomID == roomID {
+			cache.order.Remove(element)
+			delete(cache.entries, key)
+		}
+	}
+}
+
+// invalidateRooms drops every cached entry of the rooms written by a transaction.
+func (cache *metricsCache) invalidateRooms(touched touchedRooms) {
+	if touched.all {
+		cache.purge()
+		return
+	}
+	if len(touched.roomIDs) == 0 {
+		return
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	for roomID := range touched.roomIDs {
+		cache.writes[roomID]++
+	}
+	for key, element := range cache.entries {
+		if touched.roomIDs[key.RoomID] {
+			cache.order.Remove(element)
+			delete(cache.entries, key)
+		}
+	}
+}
+
+// purge drops every cached entry.
+func (cache *metricsCache) purge() {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.order.Init()
+	cache.entries = make(map[metricsKey]*list.Element)
+	cache.purges++
+	cache.writes = make(map[int32]uint64)
+}
+
+// computeETag returns a strong entity tag for the given response body.
+func computeETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches reports whether an `If-None-Match` header value matches the given entity tag.
+// Weak comparison is used, as mandated by RFC 9110 for `If-None-Match`.
+func etagMatches(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
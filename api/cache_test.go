@@ -0,0 +1,107 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsCacheEviction(t *testing.T) {
+	cache := newMetricsCache(2, time.Minute)
+
+	cache.put(metricsKey{RoomID: 1, Window: 30}, []byte(`{"room_id":1}`), cache.generation(1))
+	cache.put(metricsKey{RoomID: 2, Window: 30}, []byte(`{"room_id":2}`), cache.generation(2))
+
+	// Touch room 1 so that room 2 becomes the least recently used entry.
+	_, ok := cache.get(metricsKey{RoomID: 1, Window: 30})
+	require.True(t, ok)
+
+	cache.put(metricsKey{RoomID: 3, Window: 30}, []byte(`{"room_id":3}`), cache.generation(3))
+
+	_, ok = cache.get(metricsKey{RoomID: 2, Window: 30})
+	require.False(t, ok)
+	_, ok = cache.get(metricsKey{RoomID: 1, Window: 30})
+	require.True(t, ok)
+	_, ok = cache.get(metricsKey{RoomID: 3, Window: 30})
+	require.True(t, ok)
+}
+
+func TestMetricsCacheInvalidation(t *testing.T) {
+	cache := newMetricsCache(10, time.Minute)
+
+	cache.put(metricsKey{RoomID: 1, Window: 30}, []byte(`{}`), cache.generation(1))
+	cache.put(metricsKey{RoomID: 1, Window: 7}, []byte(`{}`), cache.generation(1))
+	cache.put(metricsKey{RoomID: 2, Window: 30}, []byte(`{}`), cache.generation(2))
+
+	cache.invalidate(1)
+
+	_, ok := cache.get(metricsKey{RoomID: 1, Window: 30})
+	require.False(t, ok)
+	_, ok = cache.get(metricsKey{RoomID: 1, Window: 7})
+	require.False(t, ok)
+	_, ok = cache.get(metricsKey{RoomID: 2, Window: 30})
+	require.True(t, ok)
+
+	cache.purge()
+	_, ok = cache.get(metricsKey{RoomID: 2, Window: 30})
+	require.False(t, ok)
+}
+
+func TestMetricsCacheGeneration(t *testing.T) {
+	cache := newMetricsCache(10, time.Minute)
+	key := metricsKey{RoomID: 1, Window: 30}
+
+	// Metrics computed before an invalidation of their room are not stored.
+	generation := cache.generation(1)
+	cache.invalidate(1)
+	entry := cache.put(key, []byte(`{}`), generation)
+	require.NotEmpty(t, entry.etag)
+	_, ok := cache.get(key)
+	require.False(t, ok)
+
+	// Invalidating another room keeps them.
+	generation = cache.generation(1)
+	cache.invalidateRooms(touchedRooms{roomIDs: map[int32]bool{2: true}})
+	cache.put(key, []byte(`{}`), generation)
+	_, ok = cache.get(key)
+	require.True(t, ok)
+
+	generation = cache.generation(1)
+	cache.purge()
+	cache.put(key, []byte(`{}`), generation)
+	_, ok = cache.get(key)
+	require.False(t, ok)
+}
+
+func TestMetricsCacheExpiry(t *testing.T) {
+	now := time.Date(2024, 12, 12, 23, 58, 0, 0, time.UTC)
+	cache := newMetricsCache(10, 5*time.Minute)
+	cache.now = func() time.Time { return now }
+
+	key := metricsKey{RoomID: 1, Window: 30}
+	cache.put(key, []byte(`{}`), cache.generation(key.RoomID))
+
+	// Entries do not survive midnight, even within the TTL.
+	now = now.Add(3 * time.Minute)
+	_, ok := cache.get(key)
+	require.False(t, ok)
+
+	cache.put(key, []byte(`{}`), cache.generation(key.RoomID))
+	now = now.Add(6 * time.Minute)
+	_, ok = cache.get(key)
+	require.False(t, ok)
+}
+
+func TestETagMatches(t *testing.T) {
+	etag := computeETag([]byte(`{"room_id":1}`))
+	require.Equal(t, etag, computeETag([]byte(`{"room_id":1}`)))
+	require.NotEqual(t, etag, computeETag([]byte(`{"room_id":2}`)))
+
+	require.True(t, etagMatches(etag, etag))
+	require.True(t, etagMatches(`"other", `+etag, etag))
+	require.True(t, etagMatches("W/"+etag, etag))
+	require.True(t, etagMatches("*", etag))
+	require.False(t, etagMatches(`"other"`, etag))
+	require.False(t, etagMatches("", etag))
+}
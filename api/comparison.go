@@ -0,0 +1,196 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// maxComparisonDays bounds the length of a compared period.
+const maxComparisonDays = 366
+
+// comparisonRequest defines the query parameters of a year-over-year comparison.
+type comparisonRequest struct {
+	From string `form:"from" binding:"required,datetime=2006-01-02"`
+	To   string `form:"to" binding:"required,datetime=2006-01-02"`
+}
+
+// period is a range of nights, both ends included.
+type period struct {
+	from, to time.Time
+}
+
+// bindPeriod binds and validates the compared period.
+func bindPeriod(ctx *gin.Context) (period, error) {
+	var req comparisonRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		return period{}, newError(ErrValidation, "invalid_request", err.Error(), err)
+	}
+
+	from, _ := time.Parse(dateLayout, req.From) // Validated when binding.
+	to, _ := time.Parse(dateLayout, req.To)
+	if to.Before(from) {
+		return period{}, newError(ErrValidation, "invalid_request", "`to` must not be before `from`.", nil)
+	}
+	if to.Sub(from) >= maxComparisonDays*24*time.Hour {
+		return period{}, newError(ErrValidation, "invalid_request", fmt.Sprintf("The period must not exceed %d nights.", maxComparisonDays), nil)
+	}
+	return period{from: from, to: to}, nil
+}
+
+// previousYear returns the same period one year earlier. February 29 maps to February 28.
+func (p period) previousYear() period {
+	return period{from: sameDayLastYear(p.from), to: sameDayLastYear(p.to)}
+}
+
+// sameDayLastYear returns the same day of the same month one year earlier, or the last day
+// of the month if it has fewer days.
+func sameDayLastYear(date time.Time) time.Time {
+	lastYear := date.AddDate(-1, 0, 0)
+	if lastYear.Month() != date.Month() {
+		lastYear = lastYear.AddDate(0, 0, -lastYear.Day())
+	}
+	return lastYear
+}
+
+// dates returns both ends of the period as DATE arguments.
+func (p period) dates() (from, to pgtype.Date) {
+	return pgtype.Date{Time: p.from, Valid: true}, pgtype.Date{Time: p.to, Valid: true}
+}
+
+// newPeriodStats converts aggregated statistics into their API representation.
+func newPeriodStats(p period, nights, bookedNights, revenue int64, averageRate float64, lowestRate, highestRate int32) PeriodStats {
+	stats := PeriodStats{
+		From:         p.from.Format(dateLayout),
+		To:           p.to.Format(dateLayout),
+		Nights:       nights,
+		BookedNights: bookedNights,
+		Revenue:      revenue,
+		AverageRate:  round2(averageRate),
+		LowestRate:   lowestRate,
+		HighestRate:  highestRate,
+	}
+	if nights > 0 {
+		stats.OccupancyPercentage = round2(float64(bookedNights) * 100 / float64(nights))
+	}
+	return stats
+}
+
+// portfolioPeriodHeader names the columns of an exported portfolio comparison, one row per period.
+var portfolioPeriodHeader = []string{
+	"period", "from", "to", "rooms", "nights", "booked_nights", "occupancy_percentage",
+	"revenue", "average_rate", "lowest_rate", "highest_rate",
+}
+
+// row flattens the statistics of a period into a row of an exported comparison.
+func (stats PortfolioPeriodStats) row(name string) []any {
+	return []any{
+		name, stats.From, stats.To, stats.Rooms, stats.Nights, stats.BookedNights, stats.OccupancyPercentage,
+		stats.Revenue, stats.AverageRate, stats.LowestRate, stats.HighestRate,
+	}
+}
+
+// comparePeriods computes the change from the previous year to the current period.
+func comparePeriods(current, previous PeriodStats) PeriodChange {
+	var change PeriodChange
+	if previous.Nights > 0 && current.Nights > 0 {
+		occupancy := round2(current.OccupancyPercentage - previous.OccupancyPercentage)
+		change.OccupancyPoints = &occupancy
+		if previous.AverageRate > 0 {
+			averageRate := round2((current.AverageRate - previous.AverageRate) * 100 / previous.AverageRate)
+			change.AverageRatePercentage = &averageRate
+		}
+	}
+	if previous.Revenue > 0 {
+		revenue := round2(float64(current.Revenue-previous.Revenue) * 100 / float64(previous.Revenue))
+		change.RevenuePercentage = &revenue
+	}
+	return change
+}
+
+// round2 rounds a value to two decimals.
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// getRoomComparison compares the occupancy and rates of a room over a period with the same period last year.
+func (server *Server) getRoomComparison(ctx *gin.Context) {
+	var req getRoomRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, newError(ErrValidation, "invalid_request", err.Error(), err))
+		return
+	}
+
+	current, err := bindPeriod(ctx)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	if _, err := server.store.GetRoom(ctx, req.RoomID); err != nil {
+		abortWithError(ctx, roomError(err, req.RoomID))
+		return
+	}
+
+	comparison := RoomComparison{RoomID: req.RoomID}
+	for _, target := range []struct {
+		period period
+		stats  *PeriodStats
+	}{
+		{current, &comparison.Current},
+		{current.previousYear(), &comparison.PreviousYear},
+	} {
+		from, to := target.period.dates()
+		row, err := server.store.GetRoomPeriodStats(ctx, db.GetRoomPeriodStatsParams{RoomID: req.RoomID, FromDate: from, ToDate: to})
+		if err != nil {
+			abortWithError(ctx, err)
+			return
+		}
+		*target.stats = newPeriodStats(target.period, row.Nights, row.BookedNights, row.Revenue, row.AverageRate, row.LowestRate, row.HighestRate)
+	}
+	comparison.Change = comparePeriods(comparison.Current, comparison.PreviousYear)
+
+	ctx.JSON(200, comparison)
+}
+
+// getPortfolioComparison compares the occupancy and rates of all rooms over a period with the same period last year.
+// Spreadsheets hold a row per period, leaving out the change.
+func (server *Server) getPortfolioComparison(ctx *gin.Context) {
+	current, err := bindPeriod(ctx)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	var comparison PortfolioComparison
+	for _, target := range []struct {
+		period period
+		stats  *PortfolioPeriodStats
+	}{
+		{current, &comparison.Current},
+		{current.previousYear(), &comparison.PreviousYear},
+	} {
+		from, to := target.period.dates()
+		row, err := server.store.GetPortfolioPeriodStats(ctx, db.GetPortfolioPeriodStatsParams{FromDate: from, ToDate: to})
+		if err != nil {
+			abortWithError(ctx, err)
+			return
+		}
+		*target.stats = PortfolioPeriodStats{
+			Rooms:       row.Rooms,
+			PeriodStats: newPeriodStats(target.period, row.Nights, row.BookedNights, row.Revenue, row.AverageRate, row.LowestRate, row.HighestRate),
+		}
+	}
+	comparison.Change = comparePeriods(comparison.Current.PeriodStats, comparison.PreviousYear.PeriodStats)
+
+	if format := negotiateFormat(ctx); format != gin.MIMEJSON {
+		rows := [][]any{comparison.Current.row("current"), comparison.PreviousYear.row("previous_year")}
+		writeTable(ctx, format, "portfolio-comparison", portfolioPeriodHeader, rows)
+		return
+	}
+	ctx.JSON(200, comparison)
+}
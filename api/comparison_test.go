@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// createTestNights adds nights starting on the given date, booked when the rate is negative.
+func createTestNights(t *testing.T, store *Store, roomID int32, start string, rates ...int32) {
+	t.Helper()
+	date, err := time.Parse(dateLayout, start)
+	require.NoError(t, err)
+	for i, rate := range rates {
+		_, err := store.CreateRoomAvailability(context.Background(), db.CreateRoomAvailabilityParams{
+			RoomID:      roomID,
+			Date:        pgtype.Date{Time: date.AddDate(0, 0, i), Valid: true},
+			IsAvailable: rate > 0,
+			NightRate:   max(rate, -rate),
+		})
+		require.NoError(t, err)
+	}
+}
+
+func TestRoomComparison(t *testing.T) {
+	server, store := newTestServer(t)
+	createTestRoom(t, store, 1)
+	createTestNights(t, store, 1, "2023-07-01", -5000, 5000, 5000)
+	createTestNights(t, store, 1, "2024-07-01", -6000, -6000, 6000)
+
+	// Last year's nights are archived rather than deleted.
+	require.NoError(t, store.ArchiveOldRoomAvailabilityData(context.Background()))
+
+	recorder := serve(server, http.MethodGet, "/v1/rooms/1/comparison?from=2024-07-01&to=2024-07-03", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var comparison RoomComparison
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &comparison))
+	require.Equal(t, PeriodStats{
+		From: "2024-07-01", To: "2024-07-03",
+		Nights: 3, BookedNights: 2, OccupancyPercentage: 66.67, Revenue: 12000,
+		AverageRate: 6000, LowestRate: 6000, HighestRate: 6000,
+	}, comparison.Current)
+	require.Equal(t, PeriodStats{
+		From: "2023-07-01", To: "2023-07-03",
+		Nights: 3, BookedNights: 1, OccupancyPercentage: 33.33, Revenue: 5000,
+		AverageRate: 5000, LowestRate: 5000, HighestRate: 5000,
+	}, comparison.PreviousYear)
+	require.InDelta(t, 33.34, *comparison.Change.OccupancyPoints, 0.001)
+	require.InDelta(t, 20, *comparison.Change.AverageRatePercentage, 0.001)
+	require.InDelta(t, 140, *comparison.Change.RevenuePercentage, 0.001)
+
+	// Without nights last year, there is nothing to compare to.
+	createTestNights(t, store, 1, "2024-07-10", -6000)
+	recorder = serve(server, http.MethodGet, "/v1/rooms/1/comparison?from=2024-07-10&to=2024-07-10", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	comparison = RoomComparison{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &comparison))
+	require.Equal(t, int64(1), comparison.Current.Nights)
+	require.Nil(t, comparison.Change.OccupancyPoints)
+	require.Nil(t, comparison.Change.RevenuePercentage)
+
+	requireProblem(t, serve(server, http.MethodGet, "/v1/rooms/2/comparison?from=2024-07-01&to=2024-07-03", nil), http.StatusNotFound, "room_not_found")
+	requireProblem(t, serve(server, http.MethodGet, "/v1/rooms/1/comparison?from=2024-07-01", nil), http.StatusBadRequest, "invalid_request")
+	requireProblem(t, serve(server, http.MethodGet, "/v1/rooms/1/comparison?from=2024-07-03&to=2024-07-01", nil), http.StatusBadRequest, "invalid_request")
+	requireProblem(t, serve(server, http.MethodGet, "/v1/rooms/1/comparison?from=2024-01-01&to=2025-01-01", nil), http.StatusBadRequest, "invalid_request")
+}
+
+func TestPortfolioComparison(t *testing.T) {
+	server, store := newTestServer(t)
+	createTestRoom(t, store, 1)
+	createTestRoom(t, store, 2)
+	createTestNights(t, store, 1, "2023-07-01", -5000)
+	createTestNights(t, store, 1, "2024-07-01", -6000)
+	createTestNights(t, store, 2, "2024-07-01", 4000)
+	require.NoError(t, store.ArchiveOldRoomAvailabilityData(context.Background()))
+
+	recorder := serve(server, http.MethodGet, "/v1/portfolio/comparison?from=2024-07-01&to=2024-07-31", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var comparison PortfolioComparison
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &comparison))
+	require.Equal(t, int64(2), comparison.Current.Rooms)
+	require.Equal(t, int64(2), comparison.Current.Nights)
+	require.Equal(t, 50.0, comparison.Current.OccupancyPercentage)
+	require.Equal(t, int64(1), comparison.PreviousYear.Rooms)
+	require.Equal(t, 100.0, comparison.PreviousYear.OccupancyPercentage)
+	require.Equal(t, -50.0, *comparison.Change.OccupancyPoints)
+	require.Equal(t, 20.0, *comparison.Change.RevenuePercentage)
+}
+
+func TestSameDayLastYear(t *testing.T) {
+	leapDay := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2023, 2, 28, 0, 0, 0, 0, time.UTC), sameDayLastYear(leapDay))
+
+	day := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC), sameDayLastYear(day))
+}
@@ -0,0 +1,180 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Error kinds. Every Error wraps exactly one of these, so callers can test the kind with errors.Is.
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrConflict    = errors.New("conflict")
+	ErrValidation  = errors.New("validation failed")
+	ErrUnavailable = errors.New("service unavailable")
+	ErrInternal    = errors.New("internal error")
+
+	ErrMethodNotAllowed = errors.New("method not allowed")
+	ErrUnauthorized     = errors.New("unauthorized")
+
+	ErrPreconditionFailed   = errors.New("precondition failed")
+	ErrPreconditionRequired = errors.New("precondition required")
+)
+
+// Error is a domain error carrying a stable code that is exposed to API clients.
+type Error struct {
+	Kind    error  // One of the error kinds above.
+	Code    string // Stable, machine readable error code.
+	Message string // Human readable description, safe to return to clients.
+	Err     error  // Underlying cause, never returned to clients.
+}
+
+// newError creates a domain error of the given kind.
+func newError(kind error, code string, message string, cause error) *Error {
+	return &Error{Kind: kind, Code: code, Message: message, Err: cause}
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Message, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Unwrap exposes both the error kind and the underlying cause to errors.Is and errors.As.
+func (e *Error) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Kind}
+}
+
+// PostgreSQL error codes translated into domain errors.
+// See https://www.postgresql.org/docs/current/errcodes-appendix.html.
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgCheckViolation      = "23514"
+	pgNotNullViolation    = "23502"
+	pgInvalidTextRepr     = "22P02"
+	pgDataOutOfRange      = "22003"
+)
+
+// translateError converts errors returned by pgx into domain errors.
+// Errors that are already domain errors are returned unchanged.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return err
+	}
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return newError(ErrNotFound, "not_found", "The requested resource does not exist.", err)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgUniqueViolation:
+			return newError(ErrConflict, "already_exists", "The resource already exists.", err)
+		case pgForeignKeyViolation:
+			return newError(ErrConflict, "reference_violation", "The resource references, or is referenced by, another resource.", err)
+		case pgCheckViolation, pgNotNullViolation, pgInvalidTextRepr, pgDataOutOfRange:
+			return newError(ErrValidation, "invalid_value", "A value is not valid for this resource.", err)
+		case pgSerializationFailure, pgDeadlockDetected:
+			return newError(ErrConflict, "transaction_conflict", "The request conflicted with a concurrent request; retry it.", err)
+		}
+
+		// Connection exceptions (08), insufficient resources (53) and operator intervention (57).
+		switch pgErr.Code[:2] {
+		case "08", "53", "57":
+			return newError(ErrUnavailable, "database_unavailable", "The database is temporarily unavailable.", err)
+		}
+	}
+
+	var connectErr *pgconn.ConnectError
+	var netErr net.Error
+	if errors.As(err, &connectErr) || errors.As(err, &netErr) || pgconn.Timeout(err) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return newError(ErrUnavailable, "database_unavailable", "The database is temporarily unavailable.", err)
+	}
+
+	return newError(ErrInternal, "internal_error", "An unexpected error occurred.", err)
+}
+
+// problemContentType is the media type of RFC 7807 error responses.
+const problemContentType = "application/problem+json"
+
+// problemTypePrefix is prepended to the error code to form the problem `type` URI.
+const problemTypePrefix = "urn:airbnb-api:problem:"
+
+// problem is an RFC 7807 problem details object.
+type problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	Code      string `json:"code"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// statusFor maps an error kind to its HTTP status code.
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrMethodNotAllowed):
+		return http.StatusMethodNotAllowed
+	case errors.Is(err, ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, ErrPreconditionRequired):
+		return http.StatusPreconditionRequired
+	case errors.Is(err, ErrUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// abortWithError translates err into a domain error and writes it as an `application/problem+json` response.
+func abortWithError(ctx *gin.Context, err error) {
+	var domainErr *Error
+	if !errors.As(translateError(err), &domainErr) {
+		return
+	}
+
+	status := statusFor(domainErr)
+	if status >= http.StatusInternalServerError {
+		loggerFromContext(ctx).Error("request failed", slog.String("code", domainErr.Code), slog.Any("error", err))
+	}
+
+	ctx.Header("Content-Type", problemContentType)
+	ctx.AbortWithStatusJSON(status, problem{
+		Type:      problemTypePrefix + domainErr.Code,
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    domainErr.Message,
+		Instance:  ctx.Request.URL.Path,
+		Code:      domainErr.Code,
+		RequestID: requestID(ctx),
+	})
+}
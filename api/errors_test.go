@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslateError(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		kind error
+		code string
+	}{
+		{"NoRows", pgx.ErrNoRows, ErrNotFound, "not_found"},
+		{"WrappedNoRows", fmt.Errorf("get room: %w", pgx.ErrNoRows), ErrNotFound, "not_found"},
+		{"UniqueViolation", &pgconn.PgError{Code: "23505"}, ErrConflict, "already_exists"},
+		{"ForeignKeyViolation", &pgconn.PgError{Code: "23503"}, ErrConflict, "reference_violation"},
+		{"CheckViolation", &pgconn.PgError{Code: "23514"}, ErrValidation, "invalid_value"},
+		{"AdminShutdown", &pgconn.PgError{Code: "57P01"}, ErrUnavailable, "database_unavailable"},
+		{"Deadline", context.DeadlineExceeded, ErrUnavailable, "database_unavailable"},
+		{"Unknown", errors.New("boom"), ErrInternal, "internal_error"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := translateError(tc.err)
+			require.ErrorIs(t, err, tc.kind)
+			require.ErrorIs(t, err, tc.err)
+
+			var domainErr *Error
+			require.ErrorAs(t, err, &domainErr)
+			require.Equal(t, tc.code, domainErr.Code)
+		})
+	}
+
+	// Domain errors pass through unchanged.
+	err := newError(ErrNotFound, "room_not_found", "Room 1 does not exist.", pgx.ErrNoRows)
+	require.Same(t, err, translateError(err))
+}
+
+func TestRoomErrorStatus(t *testing.T) {
+	require.Equal(t, http.StatusNotFound, statusFor(roomError(pgx.ErrNoRows, 1)))
+	require.Equal(t, http.StatusServiceUnavailable, statusFor(roomError(&pgconn.ConnectError{}, 1)))
+	require.Equal(t, http.StatusInternalServerError, statusFor(roomError(errors.New("boom"), 1)))
+}
+
+func TestProblemResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := NewServer(Store{})
+
+	testCases := []struct {
+		method string
+		path   string
+		status int
+		code   string
+	}{
+		{http.MethodGet, "/abc", http.StatusBadRequest, "invalid_request"},
+		{http.MethodGet, "/1/unknown", http.StatusNotFound, "route_not_found"},
+		{http.MethodPost, "/1", http.StatusMethodNotAllowed, "method_not_allowed"},
+	}
+
+	for _, tc := range testCases {
+		recorder := httptest.NewRecorder()
+		server.Router().ServeHTTP(recorder, httptest.NewRequest(tc.method, tc.path, nil))
+
+		require.Equal(t, tc.status, recorder.Code)
+		require.Equal(t, problemContentType, recorder.Header().Get("Content-Type"))
+
+		var body problem
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+		require.Equal(t, tc.status, body.Status)
+		require.Equal(t, tc.code, body.Code)
+		require.Equal(t, problemTypePrefix+tc.code, body.Type)
+		require.Equal(t, tc.path, body.Instance)
+		require.NotEmpty(t, body.RequestID)
+	}
+}
@@ -0,0 +1,349 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// outboxChannel is the PostgreSQL notification channel on which the position of every published event is sent.
+const outboxChannel = "outbox_published"
+
+// eventBufferSize is the number of events buffered for a stream. A stream falling further behind is closed,
+// and its client resumes from its last event.
+const eventBufferSize = 256
+
+// eventHeartbeatInterval is the time between two comments sent on an idle stream, so that proxies keep it open.
+const eventHeartbeatInterval = 15 * time.Second
+
+// eventHub fans the published events of the outbox out to the event streams served by this replica.
+// Every replica runs its own hub, woken by the notifications sent when the dispatcher publishes an event.
+type eventHub struct {
+	mu          sync.Mutex
+	position    int64 // Position of the last event fanned out.
+	subscribers map[*eventSubscriber]struct{}
+	wake        chan struct{}
+	ready       chan struct{} // Closed once the position of the hub is read.
+}
+
+// eventSubscriber receives the events of a room, or of every room for the portfolio stream.
+type eventSubscriber struct {
+	roomID pgtype.Int4
+	events chan db.Outbox // Closed when the subscriber falls behind or the hub stops.
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{
+		subscribers: map[*eventSubscriber]struct{}{},
+		wake:        make(chan struct{}, 1),
+		ready:       make(chan struct{}),
+	}
+}
+
+// subscribe registers a subscriber and returns it along with the position of the last event fanned out,
+// after which it receives every event. It waits for the hub to start.
+func (hub *eventHub) subscribe(ctx context.Context, roomID pgtype.Int4) (*eventSubscriber, int64, error) {
+	select {
+	case <-hub.ready:
+	case <-ctx.Done():
+		return nil, 0, ctx.Err()
+	}
+	subscriber := &eventSubscriber{roomID: roomID, events: make(chan db.Outbox, eventBufferSize)}
+
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	hub.subscribers[subscriber] = struct{}{}
+	eventStreams.Inc()
+	return subscriber, hub.position, nil
+}
+
+// unsubscribe removes a subscriber, unless it was already dropped.
+func (hub *eventHub) unsubscribe(subscriber *eventSubscriber) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	if _, ok := hub.subscribers[subscriber]; ok {
+		hub.drop(subscriber)
+	}
+}
+
+// drop removes a subscriber and closes its channel. The caller holds the lock.
+func (hub *eventHub) drop(subscriber *eventSubscriber) {
+	delete(hub.subscribers, subscriber)
+	close(subscriber.events)
+	eventStreams.Dec()
+}
+
+// signal wakes the hub up to fan out the events published since its last run.
+func (hub *eventHub) signal() {
+	if hub == nil {
+		return
+	}
+	select {
+	case hub.wake <- struct{}{}:
+	default:
+	}
+}
+
+// fanOut sends the events published after the position of the hub to their subscribers, and evicts the cached
+// metrics of their rooms, which may have been written by another process.
+func (hub *eventHub) fanOut(ctx context.Context, queries db.Querier, cache *metricsCache) error {
+	for {
+		hub.mu.Lock()
+		position := hub.position
+		hub.mu.Unlock()
+
+		events, err := queries.ListPublishedOutboxEvents(ctx, db.ListPublishedOutboxEventsParams{
+			AfterPosition: position,
+			RowLimit:      outboxBatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		hub.mu.Lock()
+		for _, event := range events {
+			cache.invalidate(event.RoomID)
+			for subscriber := range hub.subscribers {
+				if subscriber.roomID.Valid && subscriber.roomID.Int32 != event.RoomID {
+					continue
+				}
+				select {
+				case subscriber.events <- event:
+				default:
+					hub.drop(subscriber)
+				}
+			}
+			hub.position = event.Position.Int64
+		}
+		hub.mu.Unlock()
+
+		if len(events) < outboxBatchSize {
+			return nil
+		}
+	}
+}
+
+// RunEventHub fans the published events out to the event streams of this replica until the context is canceled.
+// With PostgreSQL, it listens to the notifications of published events on a dedicated connection, so that
+// streams get the events published by any replica right away. It also polls at every interval, in case
+// a notification is missed while reconnecting. The published events also evict the cached metrics of their rooms,
+// so that writes made by other replicas or the command line show up once published.
+func (store *Store) RunEventHub(ctx context.Context, pollInterval time.Duration) {
+	hub := store.events
+	defer func() {
+		hub.mu.Lock()
+		defer hub.mu.Unlock()
+		for subscriber := range hub.subscribers {
+			hub.drop(subscriber)
+		}
+	}()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	// Streams start with the events published from now on.
+	for {
+		position, err := store.GetOutboxPosition(ctx)
+		if err == nil {
+			hub.mu.Lock()
+			hub.position = position
+			hub.mu.Unlock()
+			close(hub.ready)
+			break
+		}
+		slog.Error("cannot read the outbox position", slog.Any("error", err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+
+	if store.conn != nil {
+		go store.listenOutbox(ctx, pollInterval)
+	}
+
+	for {
+		if err := hub.fanOut(ctx, store, store.cache); err != nil && ctx.Err() == nil {
+			slog.Error("cannot fan out events", slog.Any("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-hub.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// listenOutbox signals the hub on every notification of a published event, reconnecting after failures.
+func (store *Store) listenOutbox(ctx context.Context, retryDelay time.Duration) {
+	for {
+		err := store.waitForNotifications(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Error("lost the outbox notifications, reconnecting", slog.Any("error", err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryDelay):
+		}
+	}
+}
+
+// waitForNotifications listens to the outbox channel on a connection of the pool until it fails.
+func (store *Store) waitForNotifications(ctx context.Context) error {
+	conn, err := store.conn.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// A listening connection must not go back to the pool; closing it stops the listening.
+		conn.Conn().Close(context.Background())
+		conn.Release()
+	}()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+outboxChannel); err != nil {
+		return err
+	}
+	// Events published while not listening are fanned out now.
+	store.events.signal()
+
+	for {
+		if _, err := conn.Conn().WaitForNotification(ctx); err != nil {
+			return err
+		}
+		store.events.signal()
+	}
+}
+
+// streamRoomEvents streams the changes to a room and its calendar as server-sent events.
+func (server *Server) streamRoomEvents(ctx *gin.Context) {
+	var req getRoomRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, newError(ErrValidation, "invalid_request", err.Error(), err))
+		return
+	}
+
+	if _, err := server.store.GetRoom(ctx, req.RoomID); err != nil {
+		abortWithError(ctx, roomError(err, req.RoomID))
+		return
+	}
+	server.streamEvents(ctx, pgtype.Int4{Int32: req.RoomID, Valid: true})
+}
+
+// streamPortfolioEvents streams the changes to every room and calendar as server-sent events.
+func (server *Server) streamPortfolioEvents(ctx *gin.Context) {
+	server.streamEvents(ctx, pgtype.Int4{})
+}
+
+// streamEvents streams the events published from now on, or after the position in the `Last-Event-ID` header,
+// which is the ID of the last event a client received. Each event is sent with its position as ID, its type
+// as event name and its payload as data. The stream ends when the client disconnects or falls behind.
+func (server *Server) streamEvents(ctx *gin.Context, roomID pgtype.Int4) {
+	var after int64
+	lastEventID := ctx.GetHeader("Last-Event-ID")
+	if lastEventID != "" {
+		position, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || position < 0 {
+			abortWithError(ctx, newError(ErrValidation, "invalid_request", "Last-Event-ID must be the ID of an event.", err))
+			return
+		}
+		after = position
+	}
+
+	subscriber, position, err := server.store.events.subscribe(ctx.Request.Context(), roomID)
+	if err != nil {
+		return // The client disconnected.
+	}
+	defer server.store.events.unsubscribe(subscriber)
+	if lastEventID == "" {
+		after = position
+	}
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no") // Keeps reverse proxies from buffering the stream.
+	ctx.Status(200)
+	ctx.Writer.Flush()
+
+	send := func(event db.Outbox) error {
+		if err := writeEvent(ctx.Writer, event); err != nil {
+			return err
+		}
+		after = event.Position.Int64
+		return nil
+	}
+
+	// Catch up from the outbox with the events published before the subscription.
+	for lastEventID != "" && after < position {
+		events, err := server.store.ListPublishedOutboxEvents(ctx, db.ListPublishedOutboxEventsParams{
+			AfterPosition: after,
+			RoomID:        roomID,
+			RowLimit:      outboxBatchSize,
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "cannot read the events to resume from", slog.Any("error", err))
+			return
+		}
+		for _, event := range events {
+			if err := send(event); err != nil {
+				return
+			}
+		}
+		if len(events) < outboxBatchSize {
+			break
+		}
+	}
+
+	heartbeat := time.NewTicker(eventHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case event, ok := <-subscriber.events:
+			if !ok {
+				return
+			}
+			// Events already sent while catching up are skipped.
+			if event.Position.Int64 <= after {
+				continue
+			}
+			if err := send(event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(ctx.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			ctx.Writer.Flush()
+		}
+	}
+}
+
+// writeEvent writes an event of the outbox as a server-sent event and flushes it.
+func writeEvent(w gin.ResponseWriter, event db.Outbox) error {
+	var data bytes.Buffer
+	if err := json.Compact(&data, event.Payload); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Position.Int64, event.EventType, data.Bytes()); err != nil {
+		return err
+	}
+	w.Flush()
+	return nil
+}
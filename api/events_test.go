@@ -0,0 +1,215 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// streamedEvent is an event read from a server-sent event stream.
+type streamedEvent struct {
+	id, name, data string
+}
+
+// newEventTestServer starts the event hub of a test server and serves it over HTTP, as streams need a real connection.
+func newEventTestServer(t *testing.T) (*httptest.Server, *Store) {
+	t.Helper()
+	server, store := newTestServer(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		store.RunEventHub(ctx, time.Hour)
+		close(done)
+	}()
+
+	httpServer := httptest.NewServer(server.Router())
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		httpServer.Close()
+	})
+	return httpServer, store
+}
+
+// openTestStream opens an event stream, resuming after lastEventID unless it is empty.
+func openTestStream(t *testing.T, server *httptest.Server, path, lastEventID string) <-chan streamedEvent {
+	t.Helper()
+	request, err := http.NewRequest("GET", server.URL+path, nil)
+	require.NoError(t, err)
+	if lastEventID != "" {
+		request.Header.Set("Last-Event-ID", lastEventID)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	response, err := http.DefaultClient.Do(request.WithContext(ctx))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+
+	events := make(chan streamedEvent, 16)
+	go func() {
+		defer response.Body.Close()
+		defer close(events)
+
+		var event streamedEvent
+		scanner := bufio.NewScanner(response.Body)
+		for scanner.Scan() {
+			field, value, _ := strings.Cut(scanner.Text(), ": ")
+			switch field {
+			case "id":
+				event.id = value
+			case "event":
+				event.name = value
+			case "data":
+				event.data = value
+			case "":
+				events <- event
+				event = streamedEvent{}
+			}
+		}
+	}()
+	return events
+}
+
+// nextTestEvents reads the given number of events from a stream.
+func nextTestEvents(t *testing.T, events <-chan streamedEvent, count int) []streamedEvent {
+	t.Helper()
+	var read []streamedEvent
+	for len(read) < count {
+		select {
+		case event, ok := <-events:
+			require.True(t, ok, "stream closed")
+			read = append(read, event)
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "timed out waiting for events", "read %d of %d", len(read), count)
+		}
+	}
+	return read
+}
+
+// writeTestNight writes a night and publishes its events.
+func writeTestNight(t *testing.T, store *Store, roomID int32, day int, available bool, rate int32) {
+	t.Helper()
+	ctx := context.Background()
+	_, err := store.UpsertRoomAvailability(ctx, db.UpsertRoomAvailabilityParams{
+		RoomID:      roomID,
+		Date:        testNight(day),
+		IsAvailable: available,
+		NightRate:   rate,
+	})
+	require.NoError(t, err)
+	_, err = store.DispatchOutbox(ctx, nil)
+	require.NoError(t, err)
+}
+
+func TestRoomEventStream(t *testing.T) {
+	server, store := newEventTestServer(t)
+	createTestRoom(t, store, 1)
+	createTestRoom(t, store, 2)
+	_, err := store.DispatchOutbox(context.Background(), nil) // The creation of the rooms is not streamed.
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		store.events.mu.Lock()
+		defer store.events.mu.Unlock()
+		return store.events.position == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	stream := openTestStream(t, server, "/v1/rooms/1/events", "")
+	writeTestNight(t, store, 2, 0, true, 5000)
+	writeTestNight(t, store, 1, 0, true, 5000)
+	writeTestNight(t, store, 1, 0, true, 6000)
+
+	events := nextTestEvents(t, stream, 2)
+	require.Equal(t, eventAvailabilityChanged, events[0].name)
+	require.Equal(t, eventRateChanged, events[1].name)
+
+	var changed NightEvent
+	require.NoError(t, json.Unmarshal([]byte(events[1].data), &changed))
+	require.Equal(t, int32(1), changed.RoomID)
+	require.Equal(t, int32(5000), changed.Before.NightRate)
+	require.Equal(t, int32(6000), changed.After.NightRate)
+
+	// Events published while disconnected are sent on resumption, followed by the live ones.
+	writeTestNight(t, store, 1, 0, false, 6000)
+	resumed := openTestStream(t, server, "/v1/rooms/1/events", events[1].id)
+	writeTestNight(t, store, 1, 1, true, 5000)
+
+	events = nextTestEvents(t, resumed, 3)
+	require.Equal(t, eventAvailabilityChanged, events[0].name)
+	require.Equal(t, eventReservationCreated, events[1].name)
+	require.Equal(t, eventAvailabilityChanged, events[2].name)
+	require.Contains(t, events[2].data, `"date":"2024-06-29"`)
+}
+
+func TestPortfolioEventStream(t *testing.T) {
+	server, store := newEventTestServer(t)
+	createTestRoom(t, store, 1)
+
+	stream := openTestStream(t, server, "/v1/portfolio/events", "")
+	createTestRoom(t, store, 2)
+	writeTestNight(t, store, 1, 0, true, 5000)
+	writeTestNight(t, store, 2, 0, true, 5000)
+
+	// The creation of room 1 was published after the stream opened.
+	events := nextTestEvents(t, stream, 4)
+	names := []string{}
+	for _, event := range events {
+		names = append(names, event.name)
+	}
+	require.Equal(t, []string{eventRoomCreated, eventRoomCreated, eventAvailabilityChanged, eventAvailabilityChanged}, names)
+	require.Equal(t, "1", events[0].id)
+	require.Equal(t, "4", events[3].id)
+
+	// Resuming sends the events after the last one received, of every room.
+	resumed := openTestStream(t, server, "/v1/portfolio/events", "1")
+	events = nextTestEvents(t, resumed, 3)
+	require.Equal(t, "2", events[0].id)
+	require.Equal(t, "4", events[2].id)
+}
+
+func TestEventHubInvalidatesCache(t *testing.T) {
+	_, store := newTestServer(t)
+	ctx := context.Background()
+	createTestRoom(t, store, 1, 5000)
+	_, err := store.DispatchOutbox(ctx, nil)
+	require.NoError(t, err)
+	require.NoError(t, store.events.fanOut(ctx, store, store.cache)) // Fans out the creation of the room.
+	_, err = store.roomMetrics(ctx, 1, false)
+	require.NoError(t, err)
+
+	// A write made by another process only evicts the metrics of this one once it is published.
+	other := *store
+	other.cache = newMetricsCache(metricsCacheSize, metricsCacheTTL)
+	_, err = other.UpsertRoomAvailability(ctx, db.UpsertRoomAvailabilityParams{RoomID: 1, Date: testNight(0), IsAvailable: true, NightRate: 6000})
+	require.NoError(t, err)
+	key := metricsKey{RoomID: 1, Window: metricsWindowDays}
+	_, ok := store.cache.get(key)
+	require.True(t, ok)
+
+	_, err = store.DispatchOutbox(ctx, nil)
+	require.NoError(t, err)
+	require.NoError(t, store.events.fanOut(ctx, store, store.cache))
+	_, ok = store.cache.get(key)
+	require.False(t, ok)
+}
+
+func TestEventStreamErrors(t *testing.T) {
+	server, store := newTestServer(t)
+	createTestRoom(t, store, 1)
+
+	requireProblem(t, serve(server, "GET", "/v1/rooms/2/events", nil), http.StatusNotFound, "room_not_found")
+	requireProblem(t, serve(server, "GET", "/v1/rooms/0/events", nil), http.StatusBadRequest, "invalid_request")
+
+	header := http.Header{"Last-Event-Id": {"last"}}
+	requireProblem(t, serve(server, "GET", "/v1/portfolio/events", header), http.StatusBadRequest, "invalid_request")
+}
@@ -0,0 +1,371 @@
+package api
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// Media types of the spreadsheet representations negotiated with the `Accept` header.
+const (
+	csvMediaType  = "text/csv"
+	xlsxMediaType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// negotiateFormat returns the media type of the representation the client accepts: JSON, CSV or XLSX.
+// Requests without a matching `Accept` header get JSON, as before spreadsheets were supported.
+func negotiateFormat(ctx *gin.Context) string {
+	ctx.Header("Vary", "Accept")
+	format := ctx.NegotiateFormat(gin.MIMEJSON, csvMediaType, xlsxMediaType)
+	if format == "" {
+		return gin.MIMEJSON
+	}
+	return format
+}
+
+// tableWriter encodes rows of values under a header. Values are strings, booleans, integers,
+// floats or nil for empty cells.
+type tableWriter interface {
+	// start writes the beginning of the document, including the header.
+	start(header []string) error
+	writeRow(values []any) error
+	// flush sends the rows written so far to the client.
+	flush() error
+	// close completes the document. A document that is not closed is truncated.
+	close() error
+}
+
+// newTableWriter starts a response in the given format with a header row.
+// Spreadsheets are sent as attachments named after the exported resource.
+func newTableWriter(ctx *gin.Context, format, name string, header []string) (tableWriter, error) {
+	var table tableWriter
+	switch format {
+	case csvMediaType:
+		ctx.Header("Content-Type", csvMediaType+"; charset=utf-8")
+		ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+".csv"))
+		table = &csvTable{ctx: ctx, writer: csv.NewWriter(ctx.Writer)}
+	case xlsxMediaType:
+		ctx.Header("Content-Type", xlsxMediaType)
+		ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+".xlsx"))
+		table = &xlsxTable{ctx: ctx, archive: zip.NewWriter(ctx.Writer)}
+	default:
+		ctx.Header("Content-Type", gin.MIMEJSON+"; charset=utf-8")
+		table = &jsonTable{ctx: ctx}
+	}
+	ctx.Status(200)
+	return table, table.start(header)
+}
+
+// headerRow converts a header to a row of values.
+func headerRow(header []string) []any {
+	values := make([]any, len(header))
+	for i, column := range header {
+		values[i] = column
+	}
+	return values
+}
+
+// writeTable responds with rows as a whole table.
+func writeTable(ctx *gin.Context, format, name string, header []string, rows [][]any) {
+	table, err := newTableWriter(ctx, format, name, header)
+	for _, row := range rows {
+		if err != nil {
+			break
+		}
+		err = table.writeRow(row)
+	}
+	if err == nil {
+		err = table.close()
+	}
+	if err != nil {
+		loggerFromContext(ctx).Warn("failed to write table", slog.Any("error", err))
+	}
+}
+
+// formatValue formats a value for a text cell.
+func formatValue(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// csvTable writes rows as comma-separated values.
+type csvTable struct {
+	ctx    *gin.Context
+	writer *csv.Writer
+}
+
+func (table *csvTable) start(header []string) error {
+	return table.writeRow(headerRow(header))
+}
+
+func (table *csvTable) writeRow(values []any) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = formatValue(value)
+	}
+	return table.writer.Write(record)
+}
+
+func (table *csvTable) flush() error {
+	table.writer.Flush()
+	if err := table.writer.Error(); err != nil {
+		return err
+	}
+	table.ctx.Writer.Flush()
+	return nil
+}
+
+func (table *csvTable) close() error {
+	return table.flush()
+}
+
+// jsonTable writes rows as an array of objects keyed by the header.
+type jsonTable struct {
+	ctx    *gin.Context
+	header []string
+	rows   int
+}
+
+func (table *jsonTable) start(header []string) error {
+	table.header = header
+	_, err := io.WriteString(table.ctx.Writer, "[")
+	return err
+}
+
+func (table *jsonTable) writeRow(values []any) error {
+	object := []byte("{")
+	if table.rows > 0 {
+		object = []byte(",{")
+	}
+	for i, value := range values {
+		if i > 0 {
+			object = append(object, ',')
+		}
+		key, _ := json.Marshal(table.header[i])
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		object = append(append(append(object, key...), ':'), encoded...)
+	}
+	table.rows++
+	_, err := table.ctx.Writer.Write(append(object, '}'))
+	return err
+}
+
+func (table *jsonTable) flush() error {
+	table.ctx.Writer.Flush()
+	return nil
+}
+
+func (table *jsonTable) close() error {
+	_, err := io.WriteString(table.ctx.Writer, "]")
+	return err
+}
+
+// The parts of a workbook with a single worksheet. The worksheet itself is streamed row by row,
+// with inline strings so that no shared string table has to be held in memory.
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+	xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+// xlsxTable writes rows as an Office Open XML workbook.
+type xlsxTable struct {
+	ctx     *gin.Context
+	archive *zip.Writer
+	sheet   io.Writer
+}
+
+func (table *xlsxTable) start(header []string) error {
+	for _, part := range []struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	} {
+		writer, err := table.archive.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(writer, part.content); err != nil {
+			return err
+		}
+	}
+
+	sheet, err := table.archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	table.sheet = sheet
+	if _, err := io.WriteString(sheet, xlsxSheetStart); err != nil {
+		return err
+	}
+	return table.writeRow(headerRow(header))
+}
+
+func (table *xlsxTable) writeRow(values []any) error {
+	if _, err := io.WriteString(table.sheet, "<row>"); err != nil {
+		return err
+	}
+	for _, value := range values {
+		var err error
+		switch value := value.(type) {
+		case nil:
+			_, err = io.WriteString(table.sheet, "<c/>")
+		case bool:
+			cell := `<c t="b"><v>0</v></c>`
+			if value {
+				cell = `<c t="b"><v>1</v></c>`
+			}
+			_, err = io.WriteString(table.sheet, cell)
+		case int32, int64, float64:
+			_, err = fmt.Fprintf(table.sheet, "<c><v>%s</v></c>", formatValue(value))
+		default:
+			if _, err = io.WriteString(table.sheet, `<c t="inlineStr"><is><t>`); err == nil {
+				if err = xml.EscapeText(table.sheet, []byte(formatValue(value))); err == nil {
+					_, err = io.WriteString(table.sheet, "</t></is></c>")
+				}
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(table.sheet, "</row>")
+	return err
+}
+
+func (table *xlsxTable) flush() error {
+	if err := table.archive.Flush(); err != nil {
+		return err
+	}
+	table.ctx.Writer.Flush()
+	return nil
+}
+
+func (table *xlsxTable) close() error {
+	if _, err := io.WriteString(table.sheet, xlsxSheetEnd); err != nil {
+		return err
+	}
+	return table.archive.Close()
+}
+
+// exportPageSize is the number of rooms fetched at a time when exporting the metrics of all rooms.
+const exportPageSize = 100
+
+// metricsHeader names the columns of exported room metrics. Every night of the next 30 days is a row, by date,
+// along with the availability percentage of its month.
+var metricsHeader = []string{"room_id", "date", "is_available", "night_rate", "availability_percentage"}
+
+// metricsRows flattens the nightly rates and monthly availability of a room into rows.
+func metricsRows(data RoomData) [][]any {
+	percentages := make(map[string]float64, len(data.OccupancyPercentage))
+	for _, row := range data.OccupancyPercentage {
+		year, yearErr := row.Year.Int64Value()
+		month, monthErr := row.Month.Int64Value()
+		percentage, err := row.AvailabilityPercentage.Float64Value()
+		if yearErr != nil || monthErr != nil || err != nil {
+			continue
+		}
+		percentages[fmt.Sprintf("%04d-%02d", year.Int64, month.Int64)] = percentage.Float64
+	}
+
+	rows := make([][]any, 0, len(data.RatePerNight))
+	for _, night := range data.RatePerNight {
+		var percentage any // Empty when the month has no statistics.
+		if value, ok := percentages[night.Date.Time.Format(monthLayout)]; ok {
+			percentage = value
+		}
+		rows = append(rows, []any{data.RoomID, night.Date.Time.Format(dateLayout), night.IsAvailable, night.NightRate, percentage})
+	}
+	return rows
+}
+
+// nightMetricsRow converts a night of the next 30 days of a room into a row of exported metrics.
+func nightMetricsRow(night db.ListUpcomingNightMetricsRow) []any {
+	var percentage any // Empty when the month has no statistics.
+	if value, err := night.AvailabilityPercentage.Float64Value(); err == nil && value.Valid {
+		percentage = value.Float64
+	}
+	return []any{night.RoomID, night.Date.Time.Format(dateLayout), night.IsAvailable, night.NightRate, percentage}
+}
+
+// getPortfolioMetrics exports the nightly rates and monthly availability of every room as JSON, CSV or XLSX.
+// Rooms are fetched a page at a time and each page is sent once written, so the export is never held in memory.
+func (server *Server) getPortfolioMetrics(ctx *gin.Context) {
+	format := negotiateFormat(ctx)
+
+	// Fetch the first page before responding, so that an unavailable database is still reported as a problem.
+	rooms, err := server.store.ListRooms(ctx, db.ListRoomsParams{Limit: exportPageSize})
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	table, err := newTableWriter(ctx, format, "portfolio-metrics", metricsHeader)
+	if err == nil {
+		err = server.store.writePortfolioMetrics(ctx, table, rooms)
+	}
+	if err != nil {
+		// The status has been sent already: the document is left truncated.
+		loggerFromContext(ctx).Warn("failed to export portfolio metrics", slog.Any("error", err))
+	}
+}
+
+// writePortfolioMetrics writes the metrics of the rooms, starting with the given first page, and completes the table.
+func (store *Store) writePortfolioMetrics(ctx context.Context, table tableWriter, rooms []db.Room) error {
+	for offset := int32(0); ; offset += exportPageSize {
+		// The nights of a page of rooms are fetched at once.
+		roomIDs := make([]int32, len(rooms))
+		for i, room := range rooms {
+			roomIDs[i] = room.RoomID
+		}
+		nights, err := store.ListUpcomingNightMetrics(ctx, roomIDs)
+		if err != nil {
+			return err
+		}
+		for _, night := range nights {
+			if err := table.writeRow(nightMetricsRow(night)); err != nil {
+				return err
+			}
+		}
+		if err := table.flush(); err != nil {
+			return err
+		}
+		if len(rooms) < exportPageSize {
+			return table.close()
+		}
+
+		rooms, err = store.ListRooms(ctx, db.ListRoomsParams{Limit: exportPageSize, Offset: offset + exportPageSize})
+		if err != nil {
+			return err
+		}
+	}
+}
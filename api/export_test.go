@@ -0,0 +1,203 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// acceptHeader returns a header accepting the given media type.
+func acceptHeader(mediaType string) http.Header {
+	return http.Header{"Accept": {mediaType}}
+}
+
+// readCSV parses a CSV response.
+func readCSV(t *testing.T, recorder *httptest.ResponseRecorder) [][]string {
+	t.Helper()
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "text/csv; charset=utf-8", recorder.Header().Get("Content-Type"))
+
+	records, err := csv.NewReader(recorder.Body).ReadAll()
+	require.NoError(t, err)
+	return records
+}
+
+// readXLSX parses the worksheet of an XLSX response into rows of cell texts.
+func readXLSX(t *testing.T, recorder *httptest.ResponseRecorder) [][]string {
+	t.Helper()
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, xlsxMediaType, recorder.Header().Get("Content-Type"))
+
+	archive, err := zip.NewReader(bytes.NewReader(recorder.Body.Bytes()), int64(recorder.Body.Len()))
+	require.NoError(t, err)
+	names := []string{}
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+	}
+	require.Contains(t, names, "[Content_Types].xml")
+	require.Contains(t, names, "xl/workbook.xml")
+
+	file, err := archive.Open("xl/worksheets/sheet1.xml")
+	require.NoError(t, err)
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	require.NoError(t, err)
+
+	var sheet struct {
+		Rows []struct {
+			Cells []struct {
+				Type   string `xml:"t,attr"`
+				Value  string `xml:"v"`
+				Inline string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	require.NoError(t, xml.Unmarshal(content, &sheet))
+
+	rows := [][]string{}
+	for _, row := range sheet.Rows {
+		cells := []string{}
+		for _, cell := range row.Cells {
+			if cell.Type == "inlineStr" {
+				cells = append(cells, cell.Inline)
+			} else {
+				cells = append(cells, cell.Value)
+			}
+		}
+		rows = append(rows, cells)
+	}
+	return rows
+}
+
+func TestGetRoomDataCSV(t *testing.T) {
+	server, store := newTestServer(t)
+	createTestRoom(t, store, 101, 5000, 6000, 7000, 8000)
+
+	recorder := serve(server, http.MethodGet, "/101", acceptHeader("text/csv"))
+	require.Equal(t, `attachment; filename="room-101-metrics.csv"`, recorder.Header().Get("Content-Disposition"))
+	require.Equal(t, "Accept", recorder.Header().Get("Vary"))
+	require.Empty(t, recorder.Header().Get("ETag"))
+
+	// June has 2 of 3 nights available, July 1 is booked.
+	require.Equal(t, [][]string{
+		{"room_id", "date", "is_available", "night_rate", "availability_percentage"},
+		{"101", "2024-06-28", "true", "5000", "66.67"},
+		{"101", "2024-06-29", "false", "6000", "66.67"},
+		{"101", "2024-06-30", "true", "7000", "66.67"},
+		{"101", "2024-07-01", "false", "8000", "0"},
+	}, readCSV(t, recorder))
+
+	// JSON stays the default representation.
+	recorder = serve(server, http.MethodGet, "/101", acceptHeader("*/*"))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
+	require.NotEmpty(t, recorder.Header().Get("ETag"))
+
+	recorder = serve(server, http.MethodGet, "/102", acceptHeader("text/csv"))
+	requireProblem(t, recorder, http.StatusNotFound, "room_not_found")
+}
+
+func TestGetRoomDataXLSX(t *testing.T) {
+	server, store := newTestServer(t)
+	createTestRoom(t, store, 101, 5000, 6000)
+
+	recorder := serve(server, http.MethodGet, "/101", acceptHeader(xlsxMediaType))
+	require.Equal(t, `attachment; filename="room-101-metrics.xlsx"`, recorder.Header().Get("Content-Disposition"))
+	require.Equal(t, [][]string{
+		{"room_id", "date", "is_available", "night_rate", "availability_percentage"},
+		{"101", "2024-06-28", "1", "5000", "50"},
+		{"101", "2024-06-29", "0", "6000", "50"},
+	}, readXLSX(t, recorder))
+}
+
+func TestPortfolioMetrics(t *testing.T) {
+	server, store := newTestServer(t)
+
+	// More rooms than fit in a page, only the first and last having nights.
+	createTestRoom(t, store, 1, 5000)
+	for roomID := int32(2); roomID <= exportPageSize; roomID++ {
+		createTestRoom(t, store, roomID)
+	}
+	createTestRoom(t, store, exportPageSize+1, 4000, 4500)
+
+	recorder := serve(server, http.MethodGet, "/v1/portfolio/metrics", acceptHeader("text/csv"))
+	require.Equal(t, `attachment; filename="portfolio-metrics.csv"`, recorder.Header().Get("Content-Disposition"))
+	require.Equal(t, [][]string{
+		{"room_id", "date", "is_available", "night_rate", "availability_percentage"},
+		{"1", "2024-06-28", "true", "5000", "100"},
+		{"101", "2024-06-28", "true", "4000", "50"},
+		{"101", "2024-06-29", "false", "4500", "50"},
+	}, readCSV(t, recorder))
+
+	rows := readXLSX(t, serve(server, http.MethodGet, "/v1/portfolio/metrics", acceptHeader(xlsxMediaType)))
+	require.Len(t, rows, 4)
+	require.Equal(t, []string{"101", "2024-06-29", "0", "4500", "50"}, rows[3])
+
+	// JSON holds the same rows as objects.
+	recorder = serve(server, http.MethodGet, "/v1/portfolio/metrics", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var nights []map[string]any
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &nights))
+	require.Len(t, nights, 3)
+	require.Equal(t, map[string]any{
+		"room_id": 1.0, "date": "2024-06-28", "is_available": true, "night_rate": 5000.0, "availability_percentage": 100.0,
+	}, nights[0])
+}
+
+func TestPortfolioMetricsWindow(t *testing.T) {
+	server, store := newTestServer(t)
+	createTestRoom(t, store, 1)
+	// Nights are created out of order, around the next 30 days.
+	createTestNights(t, store, 1, "2024-07-28", 7000)
+	createTestNights(t, store, 1, "2024-06-30", -6000)
+	createTestNights(t, store, 1, "2024-06-27", 4000, 5000)
+
+	expected := [][]string{
+		{"room_id", "date", "is_available", "night_rate", "availability_percentage"},
+		{"1", "2024-06-28", "true", "5000", "66.67"},
+		{"1", "2024-06-30", "false", "6000", "66.67"},
+	}
+	require.Equal(t, expected, readCSV(t, serve(server, http.MethodGet, "/v1/portfolio/metrics", acceptHeader("text/csv"))))
+	require.Equal(t, expected, readCSV(t, serve(server, http.MethodGet, "/1", acceptHeader("text/csv"))))
+}
+
+func TestPortfolioMetricsEmpty(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	recorder := serve(server, http.MethodGet, "/v1/portfolio/metrics", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.JSONEq(t, `[]`, recorder.Body.String())
+
+	require.Equal(t, [][]string{
+		{"room_id", "date", "is_available", "night_rate", "availability_percentage"},
+	}, readCSV(t, serve(server, http.MethodGet, "/v1/portfolio/metrics", acceptHeader("text/csv"))))
+}
+
+func TestPortfolioExports(t *testing.T) {
+	server, store := newTestServer(t)
+	createTestRoom(t, store, 1)
+	createTestNights(t, store, 1, "2024-07-01", -6000, 4000)
+
+	records := readCSV(t, serve(server, http.MethodGet, "/v1/portfolio/comparison?from=2024-07-01&to=2024-07-31", acceptHeader("text/csv")))
+	require.Equal(t, [][]string{
+		{"period", "from", "to", "rooms", "nights", "booked_nights", "occupancy_percentage", "revenue", "average_rate", "lowest_rate", "highest_rate"},
+		{"current", "2024-07-01", "2024-07-31", "1", "2", "1", "50", "6000", "5000", "4000", "6000"},
+		{"previous_year", "2023-07-01", "2023-07-31", "0", "0", "0", "0", "0", "0", "0", "0"},
+	}, records)
+
+	records = readCSV(t, serve(server, http.MethodGet, "/v1/portfolio/forecast?month=2024-07", acceptHeader("text/csv")))
+	require.Equal(t, []string{"month", "nights", "booked_nights", "occupancy_percentage", "expected_pickup", "forecast_occupancy_percentage", "history_nights"}, records[0])
+	require.Equal(t, []string{"2024-07", "2", "1", "50"}, records[1][:4])
+
+	// Invalid requests are still reported as problems.
+	recorder := serve(server, http.MethodGet, "/v1/portfolio/comparison?from=2024-07-31&to=2024-07-01", acceptHeader("text/csv"))
+	requireProblem(t, recorder, http.StatusBadRequest, "invalid_request")
+}
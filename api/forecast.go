@@ -0,0 +1,181 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// forecastLookbackDays is the number of past nights the booking pace is learned from.
+const forecastLookbackDays = 365
+
+// monthLayout formats the month of a forecast.
+const monthLayout = "2006-01"
+
+// forecastRequest defines the query parameters of an occupancy forecast.
+type forecastRequest struct {
+	Month string `form:"month" binding:"omitempty,datetime=2006-01"`
+}
+
+// bindForecastMonth binds the forecast month, which defaults to the month after the current one.
+func bindForecastMonth(ctx *gin.Context) (time.Time, error) {
+	var req forecastRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		return time.Time{}, newError(ErrValidation, "invalid_request", err.Error(), err)
+	}
+	if req.Month == "" {
+		now := time.Now().UTC()
+		return time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC), nil
+	}
+	month, _ := time.Parse(monthLayout, req.Month) // Validated when binding.
+	return month, nil
+}
+
+// bookingPace counts the past nights by how long before arrival they were booked.
+type bookingPace struct {
+	unbooked int64   // Nights never booked.
+	booked   []int64 // Nights booked the given number of days before arrival.
+}
+
+// newBookingPace builds the pace from its lead time histogram.
+func newBookingPace(rows []db.GetBookingPaceRow) bookingPace {
+	var pace bookingPace
+	for _, row := range rows {
+		if !row.LeadDays.Valid {
+			pace.unbooked += row.Nights
+			continue
+		}
+		for len(pace.booked) <= int(row.LeadDays.Int32) {
+			pace.booked = append(pace.booked, 0)
+		}
+		pace.booked[row.LeadDays.Int32] += row.Nights
+	}
+	return pace
+}
+
+// nights returns the number of past nights the pace was learned from.
+func (pace bookingPace) nights() int64 {
+	nights := pace.unbooked
+	for _, booked := range pace.booked {
+		nights += booked
+	}
+	return nights
+}
+
+// pickup returns the probability that a night still available daysAhead days before arrival gets booked.
+// It is the share of booked nights among the past nights that were still available at that point,
+// i.e. that were never booked or were booked at most daysAhead days before arrival.
+func (pace bookingPace) pickup(daysAhead int32) float64 {
+	var later int64
+	for leadDays := 0; leadDays <= int(daysAhead) && leadDays < len(pace.booked); leadDays++ {
+		later += pace.booked[leadDays]
+	}
+	if later == 0 {
+		return 0
+	}
+	return float64(later) / float64(pace.unbooked+later)
+}
+
+// forecastOccupancy projects the occupancy of the nights of a month: booked nights stay booked, and
+// each available night is expected to be booked with the pickup probability of its distance from today.
+// Past nights keep their final state.
+func forecastOccupancy(month time.Time, nights []db.GetForecastNightsRow, pace bookingPace) OccupancyForecast {
+	forecast := OccupancyForecast{Month: month.Format(monthLayout), HistoryNights: pace.nights()}
+	var pickup float64
+	for _, night := range nights {
+		forecast.Nights += night.Nights
+		forecast.BookedNights += night.BookedNights
+		if night.DaysAhead >= 0 {
+			pickup += float64(night.Nights-night.BookedNights) * pace.pickup(night.DaysAhead)
+		}
+	}
+
+	forecast.ExpectedPickup = round2(pickup)
+	if forecast.Nights > 0 {
+		forecast.OccupancyPercentage = round2(float64(forecast.BookedNights) * 100 / float64(forecast.Nights))
+		forecast.ForecastOccupancyPercentage = round2((float64(forecast.BookedNights) + pickup) * 100 / float64(forecast.Nights))
+	}
+	return forecast
+}
+
+// forecastHeader names the columns of an exported forecast.
+var forecastHeader = []string{
+	"month", "nights", "booked_nights", "occupancy_percentage", "expected_pickup", "forecast_occupancy_percentage", "history_nights",
+}
+
+// row flattens a forecast into a row of an export.
+func (forecast OccupancyForecast) row() []any {
+	return []any{
+		forecast.Month, forecast.Nights, forecast.BookedNights, forecast.OccupancyPercentage,
+		forecast.ExpectedPickup, forecast.ForecastOccupancyPercentage, forecast.HistoryNights,
+	}
+}
+
+// forecast projects the occupancy of a room, or of all rooms if roomID is NULL, at the end of a month.
+func (store *Store) forecast(ctx context.Context, roomID pgtype.Int4, month time.Time) (OccupancyForecast, error) {
+	rows, err := store.GetBookingPace(ctx, db.GetBookingPaceParams{LookbackDays: forecastLookbackDays, RoomID: roomID})
+	if err != nil {
+		return OccupancyForecast{}, err
+	}
+
+	nights, err := store.GetForecastNights(ctx, db.GetForecastNightsParams{
+		FromDate: pgtype.Date{Time: month, Valid: true},
+		ToDate:   pgtype.Date{Time: month.AddDate(0, 1, -1), Valid: true},
+		RoomID:   roomID,
+	})
+	if err != nil {
+		return OccupancyForecast{}, err
+	}
+	return forecastOccupancy(month, nights, newBookingPace(rows)), nil
+}
+
+// getRoomForecast projects the occupancy of a room at the end of a month from its booking pace.
+func (server *Server) getRoomForecast(ctx *gin.Context) {
+	var req getRoomRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, newError(ErrValidation, "invalid_request", err.Error(), err))
+		return
+	}
+
+	month, err := bindForecastMonth(ctx)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	if _, err := server.store.GetRoom(ctx, req.RoomID); err != nil {
+		abortWithError(ctx, roomError(err, req.RoomID))
+		return
+	}
+
+	forecast, err := server.store.forecast(ctx, pgtype.Int4{Int32: req.RoomID, Valid: true}, month)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+	ctx.JSON(200, RoomForecast{RoomID: req.RoomID, OccupancyForecast: forecast})
+}
+
+// getPortfolioForecast projects the occupancy of all rooms at the end of a month from their booking pace.
+func (server *Server) getPortfolioForecast(ctx *gin.Context) {
+	month, err := bindForecastMonth(ctx)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	forecast, err := server.store.forecast(ctx, pgtype.Int4{}, month)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	if format := negotiateFormat(ctx); format != gin.MIMEJSON {
+		writeTable(ctx, format, "portfolio-forecast", forecastHeader, [][]any{forecast.row()})
+		return
+	}
+	ctx.JSON(200, forecast)
+}
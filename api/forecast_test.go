@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"github.com/vivek-344/airbnb-api/db/memdb"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+func TestBookingPace(t *testing.T) {
+	pace := newBookingPace([]db.GetBookingPaceRow{
+		{Nights: 6},
+		{LeadDays: pgtype.Int4{Int32: 0, Valid: true}, Nights: 1},
+		{LeadDays: pgtype.Int4{Int32: 3, Valid: true}, Nights: 2},
+		{LeadDays: pgtype.Int4{Int32: 10, Valid: true}, Nights: 4},
+	})
+	require.Equal(t, int64(13), pace.nights())
+
+	require.Zero(t, pace.pickup(-1))
+	require.InDelta(t, 1.0/7, pace.pickup(0), 1e-9)
+	require.InDelta(t, 1.0/7, pace.pickup(2), 1e-9)
+	require.InDelta(t, 3.0/9, pace.pickup(3), 1e-9)
+	require.InDelta(t, 7.0/13, pace.pickup(60), 1e-9)
+
+	// Without history, nothing is expected to be booked.
+	require.Zero(t, bookingPace{}.pickup(30))
+}
+
+func TestForecastOccupancy(t *testing.T) {
+	pace := newBookingPace([]db.GetBookingPaceRow{{Nights: 1}, {LeadDays: pgtype.Int4{Int32: 5, Valid: true}, Nights: 1}})
+	month := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	forecast := forecastOccupancy(month, []db.GetForecastNightsRow{
+		{DaysAhead: -1, Nights: 2, BookedNights: 1}, // Past nights keep their state.
+		{DaysAhead: 2, Nights: 2, BookedNights: 0},  // Too close to arrival for the pace to pick them up.
+		{DaysAhead: 9, Nights: 2, BookedNights: 1},
+	}, pace)
+	require.Equal(t, OccupancyForecast{
+		Month:                       "2024-07",
+		Nights:                      6,
+		BookedNights:                2,
+		OccupancyPercentage:         33.33,
+		ExpectedPickup:              0.5,
+		ForecastOccupancyPercentage: 41.67,
+		HistoryNights:               2,
+	}, forecast)
+
+	require.Equal(t, OccupancyForecast{Month: "2024-07", HistoryNights: 2}, forecastOccupancy(month, nil, pace))
+}
+
+func TestGetForecast(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := newMemoryStore(memdb.NewWithClock(func() time.Time { return now }))
+	server := NewServer(*store)
+	createTestRoom(t, store, 1)
+	createTestRoom(t, store, 2)
+
+	// In May, the night of the 10th was booked the day before and that of the 11th 10 days before;
+	// the 12th and 13th were never booked.
+	may := func(day int) pgtype.Date {
+		return pgtype.Date{Time: time.Date(2024, 5, day, 0, 0, 0, 0, time.UTC), Valid: true}
+	}
+	// The night of April 30th predates the first booking time, May 1st, so it is left out like the nights
+	// booked before booking times were recorded.
+	_, err := store.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{RoomID: 1, Date: may(0), IsAvailable: true, NightRate: 5000})
+	require.NoError(t, err)
+	for day := 10; day <= 13; day++ {
+		_, err := store.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{RoomID: 1, Date: may(day), IsAvailable: true, NightRate: 5000})
+		require.NoError(t, err)
+	}
+	for _, booking := range []struct{ bookedOn, night int }{{1, 11}, {9, 10}} {
+		now = time.Date(2024, 5, booking.bookedOn, 12, 0, 0, 0, time.UTC)
+		_, err := store.UpdateRoomAvailability(ctx, db.UpdateRoomAvailabilityParams{RoomID: 1, Date: may(booking.night), NightRate: 5000, Version: 1})
+		require.NoError(t, err)
+	}
+
+	// On June 28, July 1st, 2nd and 21st are available and the 20th is booked.
+	now = testToday.Add(12 * time.Hour)
+	for _, day := range []int{3, 4, 22, 23} {
+		_, err := store.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{RoomID: 1, Date: testNight(day), IsAvailable: day != 22, NightRate: 5000})
+		require.NoError(t, err)
+	}
+
+	// 3 and 4 days ahead, 1 of 3 comparable nights was booked; 23 days ahead, 2 of 4.
+	expected := OccupancyForecast{
+		Month:                       "2024-07",
+		Nights:                      4,
+		BookedNights:                1,
+		OccupancyPercentage:         25,
+		ExpectedPickup:              1.17,
+		ForecastOccupancyPercentage: 54.17,
+		HistoryNights:               4,
+	}
+
+	recorder := serve(server, http.MethodGet, "/v1/rooms/1/forecast?month=2024-07", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var forecast RoomForecast
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &forecast))
+	require.Equal(t, RoomForecast{RoomID: 1, OccupancyForecast: expected}, forecast)
+
+	// Room 2 has no nights, so the portfolio forecast is that of room 1.
+	recorder = serve(server, http.MethodGet, "/v1/portfolio/forecast?month=2024-07", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var portfolio OccupancyForecast
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &portfolio))
+	require.Equal(t, expected, portfolio)
+
+	recorder = serve(server, http.MethodGet, "/v1/rooms/2/forecast?month=2024-07", nil)
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &forecast))
+	require.Equal(t, RoomForecast{RoomID: 2, OccupancyForecast: OccupancyForecast{Month: "2024-07"}}, forecast)
+
+	requireProblem(t, serve(server, http.MethodGet, "/v1/rooms/3/forecast", nil), http.StatusNotFound, "room_not_found")
+	requireProblem(t, serve(server, http.MethodGet, "/v1/rooms/1/forecast?month=2024-13", nil), http.StatusBadRequest, "invalid_request")
+	requireProblem(t, serve(server, http.MethodGet, "/v1/portfolio/forecast?month=July", nil), http.StatusBadRequest, "invalid_request")
+}
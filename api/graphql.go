@@ -0,0 +1,214 @@
+package api
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graph-gophers/dataloader/v7"
+	"github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// graphqlSchema is the schema served on `/graphql`.
+//
+//go:embed schema.graphql
+var graphqlSchema string
+
+const (
+	// graphqlMaxRooms is the largest page of rooms. Fields of the rooms of a page are resolved concurrently,
+	// so that the loaders get the keys of the whole page in a single batch.
+	graphqlMaxRooms = 500
+	// graphqlMaxDepth bounds the nesting of queries, which the schema keeps shallow.
+	graphqlMaxDepth = 8
+	// graphqlBatchWait is the time a loader waits for more keys before running its batch.
+	graphqlBatchWait = 5 * time.Millisecond
+)
+
+// graphqlRequest is a GraphQL query, sent as JSON in the body of a POST or as parameters of a GET.
+type graphqlRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// newGraphQLSchema parses the schema served on `/graphql`, resolved from the store. It panics if the schema
+// and the resolvers do not match, which the tests catch.
+func newGraphQLSchema(store *Store) *graphql.Schema {
+	return graphql.MustParseSchema(graphqlSchema, &queryResolver{store: store},
+		graphql.UseStringDescriptions(),
+		graphql.UseFieldResolvers(),
+		graphql.MaxDepth(graphqlMaxDepth),
+		graphql.MaxParallelism(graphqlMaxRooms),
+		graphql.Logger(graphqlPanics{}),
+		graphql.PanicHandler(graphqlPanics{}),
+	)
+}
+
+// serveGraphQL executes a GraphQL query. Failures of the query are reported in the `errors` of the response,
+// with their error code as `code` extension, while malformed requests are reported as problem details.
+func (server *Server) serveGraphQL(ctx *gin.Context) {
+	var req graphqlRequest
+	if ctx.Request.Method == http.MethodGet {
+		req.Query = ctx.Query("query")
+		req.OperationName = ctx.Query("operationName")
+		if variables := ctx.Query("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				abortWithError(ctx, newError(ErrValidation, "invalid_request", "variables must be a JSON object.", err))
+				return
+			}
+		}
+	} else if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, newError(ErrValidation, "invalid_request", err.Error(), err))
+		return
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		abortWithError(ctx, newError(ErrValidation, "invalid_request", "query is required.", nil))
+		return
+	}
+
+	// Loaders cache their results for the duration of the request only.
+	requestCtx := withGraphQLLoaders(ctx.Request.Context(), &server.store)
+	ctx.JSON(200, server.graphql.Exec(requestCtx, req.Query, req.OperationName, req.Variables))
+}
+
+// graphqlError is an error reported in the `errors` of a GraphQL response.
+type graphqlError struct {
+	code    string
+	message string
+}
+
+// Error implements the error interface with the message returned to clients.
+func (e *graphqlError) Error() string {
+	return e.message
+}
+
+// Extensions exposes the error code in the `extensions` of the error.
+func (e *graphqlError) Extensions() map[string]any {
+	return map[string]any{"code": e.code}
+}
+
+// newGraphQLError translates err into a domain error and returns its code and message only,
+// as the message of the error is returned to clients.
+func newGraphQLError(ctx context.Context, err error) error {
+	var domainErr *Error
+	if !errors.As(translateError(err), &domainErr) {
+		domainErr = newError(ErrInternal, "internal_error", "An unexpected error occurred.", err)
+	}
+	if errors.Is(domainErr, ErrInternal) || errors.Is(domainErr, ErrUnavailable) {
+		loggerFromContext(ctx).Error("request failed", slog.String("code", domainErr.Code), slog.Any("error", err))
+	}
+	return &graphqlError{code: domainErr.Code, message: domainErr.Message}
+}
+
+// graphqlPanics logs the panics of resolvers and reports them as internal errors.
+type graphqlPanics struct{}
+
+// LogPanic logs a panic with the logger of the request.
+func (graphqlPanics) LogPanic(ctx context.Context, value any) {
+	loggerFromContext(ctx).Error("request failed", slog.String("code", "internal_error"), slog.Any("panic", value))
+}
+
+// MakePanicError reports a panic without exposing its value.
+func (graphqlPanics) MakePanicError(ctx context.Context, value any) *gqlerrors.QueryError {
+	return &gqlerrors.QueryError{
+		Message:    "An unexpected error occurred.",
+		Extensions: map[string]any{"code": "internal_error"},
+	}
+}
+
+// graphqlLoadersKey is the context key under which the loaders of a GraphQL request are stored.
+type graphqlLoadersKey struct{}
+
+// graphqlLoaders batch the queries of the resolvers of a GraphQL request, so that listing rooms runs one query
+// per field rather than one per room.
+type graphqlLoaders struct {
+	rooms  *dataloader.Loader[int32, *db.Room]
+	nights *dataloader.Loader[nightRange, []db.RoomAvailability]
+	rates  *dataloader.Loader[int32, *db.ListUpcomingRatesRow]
+	months *dataloader.Loader[int32, []db.RoomMonthStat]
+}
+
+// nightRange identifies the nights of a room from a date to the day before another.
+type nightRange struct {
+	roomID   int32
+	from, to pgtype.Date
+}
+
+// withGraphQLLoaders returns a context carrying new loaders running their queries against the store.
+func withGraphQLLoaders(ctx context.Context, store *Store) context.Context {
+	loaders := &graphqlLoaders{
+		rooms: newGraphQLLoader(func(ctx context.Context, roomIDs []int32) (map[int32]*db.Room, error) {
+			rooms, err := store.ListRoomsByIDs(ctx, roomIDs)
+			byID := map[int32]*db.Room{}
+			for i := range rooms {
+				byID[rooms[i].RoomID] = &rooms[i]
+			}
+			return byID, err
+		}),
+		nights: newGraphQLLoader(func(ctx context.Context, keys []nightRange) (map[nightRange][]db.RoomAvailability, error) {
+			// Rooms are grouped by range, which is usually the same for every room of a page.
+			roomIDs := map[nightRange][]int32{}
+			for _, key := range keys {
+				dates := nightRange{from: key.from, to: key.to}
+				roomIDs[dates] = append(roomIDs[dates], key.roomID)
+			}
+
+			byKey := map[nightRange][]db.RoomAvailability{}
+			for dates, ids := range roomIDs {
+				nights, err := store.ListNightsForRooms(ctx, db.ListNightsForRoomsParams{RoomIds: ids, StartDate: dates.from, EndDate: dates.to})
+				if err != nil {
+					return nil, err
+				}
+				for _, night := range nights {
+					key := nightRange{roomID: night.RoomID, from: dates.from, to: dates.to}
+					byKey[key] = append(byKey[key], night)
+				}
+			}
+			return byKey, nil
+		}),
+		rates: newGraphQLLoader(func(ctx context.Context, roomIDs []int32) (map[int32]*db.ListUpcomingRatesRow, error) {
+			rates, err := store.ListUpcomingRates(ctx, roomIDs)
+			byID := map[int32]*db.ListUpcomingRatesRow{}
+			for i := range rates {
+				byID[rates[i].RoomID] = &rates[i]
+			}
+			return byID, err
+		}),
+		months: newGraphQLLoader(func(ctx context.Context, roomIDs []int32) (map[int32][]db.RoomMonthStat, error) {
+			stats, err := store.ListMonthStatsForRooms(ctx, roomIDs)
+			byID := map[int32][]db.RoomMonthStat{}
+			for _, month := range stats {
+				byID[month.RoomID] = append(byID[month.RoomID], month)
+			}
+			return byID, err
+		}),
+	}
+	return context.WithValue(ctx, graphqlLoadersKey{}, loaders)
+}
+
+// graphqlLoadersFrom returns the loaders of the GraphQL request of the context.
+func graphqlLoadersFrom(ctx context.Context) *graphqlLoaders {
+	return ctx.Value(graphqlLoadersKey{}).(*graphqlLoaders)
+}
+
+// newGraphQLLoader creates a loader running load once for the keys requested within graphqlBatchWait.
+// load returns the values found by key; the other keys get the zero value.
+func newGraphQLLoader[K comparable, V any](load func(ctx context.Context, keys []K) (map[K]V, error)) *dataloader.Loader[K, V] {
+	return dataloader.NewBatchedLoader(func(ctx context.Context, keys []K) []*dataloader.Result[V] {
+		values, err := load(ctx, keys)
+		results := make([]*dataloader.Result[V], len(keys))
+		for i, key := range keys {
+			results[i] = &dataloader.Result[V]{Data: values[key], Error: err}
+		}
+		return results
+	}, dataloader.WithWait[K, V](graphqlBatchWait), dataloader.WithBatchCapacity[K, V](graphqlMaxRooms))
+}
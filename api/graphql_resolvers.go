@@ -0,0 +1,232 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/graph-gophers/graphql-go"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// queryResolver resolves the root fields of the GraphQL schema.
+type queryResolver struct {
+	store *Store
+}
+
+// Room returns a room, or nil if it does not exist.
+func (r *queryResolver) Room(ctx context.Context, args struct{ ID int32 }) (*roomResolver, error) {
+	room, err := graphqlLoadersFrom(ctx).rooms.Load(ctx, args.ID)()
+	if err != nil {
+		return nil, newGraphQLError(ctx, err)
+	}
+	if room == nil {
+		return nil, nil
+	}
+	return &roomResolver{room: *room}, nil
+}
+
+// Rooms returns a page of rooms by ID. The schema defaults to the first 50 rooms.
+func (r *queryResolver) Rooms(ctx context.Context, args struct{ First, Offset int32 }) ([]*roomResolver, error) {
+	if args.First < 1 || args.First > graphqlMaxRooms || args.Offset < 0 {
+		return nil, newGraphQLError(ctx, newError(ErrValidation, "invalid_request",
+			fmt.Sprintf("first must be between 1 and %d, and offset must not be negative.", graphqlMaxRooms), nil))
+	}
+
+	rooms, err := r.store.ListRooms(ctx, db.ListRoomsParams{Limit: args.First, Offset: args.Offset})
+	if err != nil {
+		return nil, newGraphQLError(ctx, err)
+	}
+	resolvers := make([]*roomResolver, len(rooms))
+	for i, room := range rooms {
+		resolvers[i] = &roomResolver{room: room}
+	}
+	return resolvers, nil
+}
+
+// roomResolver resolves the fields of a room. Fields beyond the row of the room are loaded in batches
+// across the rooms of the response.
+type roomResolver struct {
+	room db.Room
+}
+
+func (r *roomResolver) ID() int32           { return r.room.RoomID }
+func (r *roomResolver) MaxGuests() int32    { return r.room.MaxGuests }
+func (r *roomResolver) Balcony() bool       { return r.room.Balcony }
+func (r *roomResolver) Fridge() bool        { return r.room.Fridge }
+func (r *roomResolver) IndoorPool() bool    { return r.room.IndoorPool }
+func (r *roomResolver) GamingConsole() bool { return r.room.GamingConsole }
+func (r *roomResolver) DefaultRate() int32  { return r.room.DefaultRate }
+func (r *roomResolver) Version() int32      { return r.room.Version }
+func (r *roomResolver) UpdatedAt() graphql.Time {
+	return graphql.Time{Time: r.room.UpdatedAt}
+}
+
+// nightRangeArgs are the arguments of the fields returning the nights of a range.
+type nightRangeArgs struct {
+	From, To string
+}
+
+// nights loads the nights of the room in a range.
+func (r *roomResolver) nights(ctx context.Context, args nightRangeArgs) ([]db.RoomAvailability, error) {
+	from, to, err := parseNightRange(args.From, args.To, "from", "to")
+	if err != nil {
+		return nil, err
+	}
+	return graphqlLoadersFrom(ctx).nights.Load(ctx, nightRange{roomID: r.room.RoomID, from: from, to: to})()
+}
+
+// Availability returns the nights of the calendar in a range.
+func (r *roomResolver) Availability(ctx context.Context, args nightRangeArgs) ([]graphqlNight, error) {
+	nights, err := r.nights(ctx, args)
+	if err != nil {
+		return nil, newGraphQLError(ctx, err)
+	}
+	availability := make([]graphqlNight, len(nights))
+	for i, night := range nights {
+		availability[i] = graphqlNight{
+			Date:        night.Date.Time.Format(dateLayout),
+			IsAvailable: night.IsAvailable,
+			NightRate:   night.NightRate,
+			Version:     night.Version,
+		}
+		if night.BookedAt.Valid {
+			availability[i].BookedAt = &graphql.Time{Time: night.BookedAt.Time}
+		}
+	}
+	return availability, nil
+}
+
+// Metrics returns the occupancy and rates of the room.
+func (r *roomResolver) Metrics() *metricsResolver {
+	return &metricsResolver{roomID: r.room.RoomID}
+}
+
+// Reservations returns the runs of consecutive booked nights in a range.
+func (r *roomResolver) Reservations(ctx context.Context, args nightRangeArgs) ([]*graphqlReservation, error) {
+	nights, err := r.nights(ctx, args)
+	if err != nil {
+		return nil, newGraphQLError(ctx, err)
+	}
+
+	reservations := []*graphqlReservation{}
+	var current *graphqlReservation
+	for i, night := range nights {
+		if night.IsAvailable {
+			current = nil
+			continue
+		}
+		// A night missing from the calendar ends the reservation as well.
+		if current == nil || !night.Date.Time.Equal(nights[i-1].Date.Time.AddDate(0, 0, 1)) {
+			current = &graphqlReservation{CheckIn: night.Date.Time.Format(dateLayout)}
+			if night.BookedAt.Valid {
+				current.BookedAt = &graphql.Time{Time: night.BookedAt.Time}
+			}
+			reservations = append(reservations, current)
+		}
+		current.CheckOut = night.Date.Time.AddDate(0, 0, 1).Format(dateLayout)
+		current.Nights++
+		current.Total += float64(night.NightRate)
+	}
+	return reservations, nil
+}
+
+// graphqlNight is a night in the calendar of a room.
+type graphqlNight struct {
+	Date        string
+	IsAvailable bool
+	NightRate   int32
+	Version     int32
+	BookedAt    *graphql.Time
+}
+
+// graphqlReservation is a run of consecutive booked nights of a room.
+type graphqlReservation struct {
+	CheckIn  string
+	CheckOut string
+	Nights   int32
+	Total    float64
+	BookedAt *graphql.Time
+}
+
+// metricsResolver resolves the metrics of a room. The rates of the next 30 days and the monthly statistics
+// are loaded by separate batches, so that clients only pay for the fields they select.
+type metricsResolver struct {
+	roomID int32
+}
+
+// rates loads the rates of the next 30 days of the room, which are nil without nights.
+func (r *metricsResolver) rates(ctx context.Context) (*db.ListUpcomingRatesRow, error) {
+	rates, err := graphqlLoadersFrom(ctx).rates.Load(ctx, r.roomID)()
+	if err != nil {
+		return nil, newGraphQLError(ctx, err)
+	}
+	return rates, nil
+}
+
+// AverageRate returns the average rate of the next 30 days.
+func (r *metricsResolver) AverageRate(ctx context.Context) (*float64, error) {
+	rates, err := r.rates(ctx)
+	if rates == nil {
+		return nil, err
+	}
+	averageRate := round2(rates.AverageRate)
+	return &averageRate, nil
+}
+
+// HighestRate returns the highest rate of the next 30 days.
+func (r *metricsResolver) HighestRate(ctx context.Context) (*int32, error) {
+	rates, err := r.rates(ctx)
+	if rates == nil {
+		return nil, err
+	}
+	return &rates.HighestRate, nil
+}
+
+// LowestRate returns the lowest rate of the next 30 days.
+func (r *metricsResolver) LowestRate(ctx context.Context) (*int32, error) {
+	rates, err := r.rates(ctx)
+	if rates == nil {
+		return nil, err
+	}
+	return &rates.LowestRate, nil
+}
+
+// Months returns the statistics of every month of the calendar.
+func (r *metricsResolver) Months(ctx context.Context) ([]graphqlMonth, error) {
+	stats, err := graphqlLoadersFrom(ctx).months.Load(ctx, r.roomID)()
+	if err != nil {
+		return nil, newGraphQLError(ctx, err)
+	}
+
+	months := make([]graphqlMonth, len(stats))
+	for i, month := range stats {
+		monthStats := newMonthStats(month)
+		months[i] = graphqlMonth{
+			Month:               monthStats.Month,
+			Nights:              monthStats.Nights,
+			BookedNights:        monthStats.BookedNights,
+			OccupancyPercentage: monthStats.OccupancyPercentage,
+			Revenue:             float64(monthStats.Revenue),
+			AverageRate:         monthStats.AverageRate,
+			LowestRate:          monthStats.LowestRate,
+			HighestRate:         monthStats.HighestRate,
+		}
+		if month.Nights > 0 {
+			months[i].AvailabilityPercentage = round2(float64(month.Nights-month.BookedNights) * 100 / float64(month.Nights))
+		}
+	}
+	return months, nil
+}
+
+// graphqlMonth holds the statistics of the nights of a room in a month of its calendar.
+type graphqlMonth struct {
+	Month                  string
+	Nights                 int32
+	BookedNights           int32
+	OccupancyPercentage    float64
+	AvailabilityPercentage float64
+	Revenue                float64
+	AverageRate            float64
+	LowestRate             int32
+	HighestRate            int32
+}
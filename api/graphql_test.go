@@ -0,0 +1,308 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vivek-344/airbnb-api/db/memdb"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// graphqlResponse is the response to a GraphQL query.
+type graphqlResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Path       []any          `json:"path"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+// serveGraphQLQuery posts a GraphQL query to the server and decodes its data into data.
+func serveGraphQLQuery(t *testing.T, server *Server, query string, variables map[string]any, data any) graphqlResponse {
+	t.Helper()
+	body, err := json.Marshal(graphqlRequest{Query: query, Variables: variables})
+	require.NoError(t, err)
+
+	recorder := serveJSON(server, http.MethodPost, "/graphql", nil, string(body))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response graphqlResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	if data != nil && len(response.Data) > 0 {
+		require.NoError(t, json.Unmarshal(response.Data, data))
+	}
+	return response
+}
+
+func TestGraphQLSchema(t *testing.T) {
+	require.NotPanics(t, func() { newGraphQLSchema(&Store{}) })
+}
+
+func TestGraphQLRoom(t *testing.T) {
+	server, store := newTestServer(t)
+	createTestRoom(t, store, 101, 5000, 6000, 7000, 8000, 9000, 10000)
+
+	var data struct {
+		Room struct {
+			ID            int32  `json:"id"`
+			MaxGuests     int32  `json:"maxGuests"`
+			Balcony       bool   `json:"balcony"`
+			Fridge        bool   `json:"fridge"`
+			GamingConsole bool   `json:"gamingConsole"`
+			DefaultRate   int32  `json:"defaultRate"`
+			UpdatedAt     string `json:"updatedAt"`
+			Availability  []struct {
+				Date        string  `json:"date"`
+				IsAvailable bool    `json:"isAvailable"`
+				NightRate   int32   `json:"nightRate"`
+				BookedAt    *string `json:"bookedAt"`
+			} `json:"availability"`
+			Metrics struct {
+				AverageRate *float64 `json:"averageRate"`
+				HighestRate *int32   `json:"highestRate"`
+				LowestRate  *int32   `json:"lowestRate"`
+				Months      []struct {
+					Month                  string  `json:"month"`
+					Nights                 int32   `json:"nights"`
+					BookedNights           int32   `json:"bookedNights"`
+					OccupancyPercentage    float64 `json:"occupancyPercentage"`
+					AvailabilityPercentage float64 `json:"availabilityPercentage"`
+					Revenue                float64 `json:"revenue"`
+				} `json:"months"`
+			} `json:"metrics"`
+		} `json:"room"`
+	}
+	response := serveGraphQLQuery(t, server, `query ($id: Int!) {
+		room(id: $id) {
+			id maxGuests balcony fridge gamingConsole defaultRate updatedAt
+			availability(from: "2024-06-28", to: "2024-07-01") { date isAvailable nightRate bookedAt }
+			metrics { averageRate highestRate lowestRate months { month nights bookedNights occupancyPercentage availabilityPercentage revenue } }
+		}
+	}`, map[string]any{"id": 101}, &data)
+	require.Empty(t, response.Errors)
+
+	room := data.Room
+	require.Equal(t, int32(101), room.ID)
+	require.Equal(t, int32(4), room.MaxGuests)
+	require.True(t, room.Balcony)
+	require.False(t, room.Fridge)
+	require.True(t, room.GamingConsole)
+	require.Equal(t, int32(5000), room.DefaultRate)
+	_, err := time.Parse(time.RFC3339, room.UpdatedAt)
+	require.NoError(t, err)
+
+	require.Len(t, room.Availability, 3)
+	require.Equal(t, "2024-06-28", room.Availability[0].Date)
+	require.True(t, room.Availability[0].IsAvailable)
+	require.Nil(t, room.Availability[0].BookedAt)
+	require.Equal(t, "2024-06-29", room.Availability[1].Date)
+	require.False(t, room.Availability[1].IsAvailable)
+	require.Equal(t, int32(6000), room.Availability[1].NightRate)
+	require.NotNil(t, room.Availability[1].BookedAt)
+
+	require.Equal(t, 7500.0, *room.Metrics.AverageRate)
+	require.Equal(t, int32(10000), *room.Metrics.HighestRate)
+	require.Equal(t, int32(5000), *room.Metrics.LowestRate)
+	require.Len(t, room.Metrics.Months, 2)
+	june := room.Metrics.Months[0]
+	require.Equal(t, "2024-06", june.Month)
+	require.Equal(t, int32(3), june.Nights)
+	require.Equal(t, int32(1), june.BookedNights)
+	require.Equal(t, 33.33, june.OccupancyPercentage)
+	require.Equal(t, 66.67, june.AvailabilityPercentage)
+	require.Equal(t, 6000.0, june.Revenue)
+	require.Equal(t, "2024-07", room.Metrics.Months[1].Month)
+
+	// Unknown rooms are null.
+	var missing struct {
+		Room *struct{ ID int32 } `json:"room"`
+	}
+	response = serveGraphQLQuery(t, server, `{ room(id: 404) { id } }`, nil, &missing)
+	require.Empty(t, response.Errors)
+	require.Nil(t, missing.Room)
+}
+
+func TestGraphQLReservations(t *testing.T) {
+	server, store := newTestServer(t)
+	ctx := context.Background()
+	_, err := store.CreateRoom(ctx, db.CreateRoomParams{RoomID: 101, MaxGuests: 2, DefaultRate: 5000})
+	require.NoError(t, err)
+	// Nights 1 to 3, 5, 6, 8 and 9 are booked, and night 7 is missing from the calendar.
+	for day := 0; day < 10; day++ {
+		if day == 7 {
+			continue
+		}
+		_, err := store.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{
+			RoomID:      101,
+			Date:        testNight(day),
+			IsAvailable: day == 0 || day == 4,
+			NightRate:   int32(5000 + 100*day),
+		})
+		require.NoError(t, err)
+	}
+
+	var data struct {
+		Room struct {
+			Reservations []struct {
+				CheckIn  string  `json:"checkIn"`
+				CheckOut string  `json:"checkOut"`
+				Nights   int32   `json:"nights"`
+				Total    float64 `json:"total"`
+				BookedAt *string `json:"bookedAt"`
+			} `json:"reservations"`
+		} `json:"room"`
+	}
+	response := serveGraphQLQuery(t, server, `{
+		room(id: 101) { reservations(from: "2024-06-29", to: "2024-07-07") { checkIn checkOut nights total bookedAt } }
+	}`, nil, &data)
+	require.Empty(t, response.Errors)
+
+	reservations := data.Room.Reservations
+	require.Len(t, reservations, 3)
+	require.Equal(t, "2024-06-29", reservations[0].CheckIn)
+	require.Equal(t, "2024-07-02", reservations[0].CheckOut)
+	require.Equal(t, int32(3), reservations[0].Nights)
+	require.Equal(t, 15600.0, reservations[0].Total)
+	require.NotNil(t, reservations[0].BookedAt)
+	// The missing night splits the nights around it, and the last reservation is cut to the range.
+	require.Equal(t, "2024-07-03", reservations[1].CheckIn)
+	require.Equal(t, "2024-07-05", reservations[1].CheckOut)
+	require.Equal(t, int32(2), reservations[1].Nights)
+	require.Equal(t, "2024-07-06", reservations[2].CheckIn)
+	require.Equal(t, "2024-07-07", reservations[2].CheckOut)
+	require.Equal(t, int32(1), reservations[2].Nights)
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	server, store := newTestServer(t)
+	createTestRoom(t, store, 101, 5000, 6000)
+
+	testCases := []struct {
+		name  string
+		query string
+		code  string
+	}{
+		{name: "PageTooLarge", query: `{ rooms(first: 501) { id } }`, code: "invalid_request"},
+		{name: "NegativeOffset", query: `{ rooms(offset: -1) { id } }`, code: "invalid_request"},
+		{name: "InvalidDate", query: `{ room(id: 101) { availability(from: "tomorrow", to: "2024-07-01") { date } } }`, code: "invalid_request"},
+		{name: "EmptyRange", query: `{ room(id: 101) { reservations(from: "2024-07-01", to: "2024-07-01") { checkIn } } }`, code: "invalid_request"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response := serveGraphQLQuery(t, server, tc.query, nil, nil)
+			require.Len(t, response.Errors, 1)
+			require.Equal(t, tc.code, response.Errors[0].Extensions["code"])
+			require.NotEmpty(t, response.Errors[0].Message)
+		})
+	}
+
+	// Queries not matching the schema are reported without data.
+	response := serveGraphQLQuery(t, server, `{ room(id: 101) { unknown } }`, nil, nil)
+	require.NotEmpty(t, response.Errors)
+	require.Empty(t, response.Data)
+
+	// Malformed requests are problems.
+	requireProblem(t, serveJSON(server, http.MethodPost, "/graphql", nil, `{"query": ""}`), http.StatusBadRequest, "invalid_request")
+	requireProblem(t, serveJSON(server, http.MethodPost, "/graphql", nil, `not json`), http.StatusBadRequest, "invalid_request")
+	requireProblem(t, serve(server, http.MethodGet, "/graphql?query=%7B%7D&variables=%5B", nil), http.StatusBadRequest, "invalid_request")
+}
+
+func TestGraphQLGet(t *testing.T) {
+	server, store := newTestServer(t)
+	createTestRoom(t, store, 101, 5000)
+
+	params := url.Values{
+		"query":     {`query ($id: Int!) { room(id: $id) { id defaultRate } }`},
+		"variables": {`{"id": 101}`},
+	}
+	recorder := serve(server, http.MethodGet, "/graphql?"+params.Encode(), nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.JSONEq(t, `{"data": {"room": {"id": 101, "defaultRate": 5000}}}`, recorder.Body.String())
+}
+
+// countingQuerier counts the queries run by the GraphQL loaders.
+type countingQuerier struct {
+	*memdb.Queries
+	roomsByIDs, nights, rates, months atomic.Int32
+}
+
+func (q *countingQuerier) ListRoomsByIDs(ctx context.Context, roomIds []int32) ([]db.Room, error) {
+	q.roomsByIDs.Add(1)
+	return q.Queries.ListRoomsByIDs(ctx, roomIds)
+}
+
+func (q *countingQuerier) ListNightsForRooms(ctx context.Context, arg db.ListNightsForRoomsParams) ([]db.RoomAvailability, error) {
+	q.nights.Add(1)
+	return q.Queries.ListNightsForRooms(ctx, arg)
+}
+
+func (q *countingQuerier) ListUpcomingRates(ctx context.Context, roomIds []int32) ([]db.ListUpcomingRatesRow, error) {
+	q.rates.Add(1)
+	return q.Queries.ListUpcomingRates(ctx, roomIds)
+}
+
+func (q *countingQuerier) ListMonthStatsForRooms(ctx context.Context, roomIds []int32) ([]db.RoomMonthStat, error) {
+	q.months.Add(1)
+	return q.Queries.ListMonthStatsForRooms(ctx, roomIds)
+}
+
+func TestGraphQLBatching(t *testing.T) {
+	queries := memdb.NewWithClock(func() time.Time { return testToday.Add(12 * time.Hour) })
+	store := newMemoryStore(queries)
+	counter := &countingQuerier{Queries: queries}
+	store.Querier = counter
+	server := NewServer(*store)
+	for roomID := int32(1); roomID <= 20; roomID++ {
+		createTestRoom(t, store, roomID, 5000, 6000, 7000)
+	}
+
+	var data struct {
+		Rooms []struct {
+			ID           int32 `json:"id"`
+			Availability []struct {
+				Date string `json:"date"`
+			} `json:"availability"`
+			Reservations []struct {
+				CheckIn string `json:"checkIn"`
+			} `json:"reservations"`
+			Metrics struct {
+				AverageRate float64 `json:"averageRate"`
+				Months      []struct {
+					Month string `json:"month"`
+				} `json:"months"`
+			} `json:"metrics"`
+		} `json:"rooms"`
+	}
+	response := serveGraphQLQuery(t, server, `{
+		rooms(first: 15, offset: 5) {
+			id
+			availability(from: "2024-06-28", to: "2024-07-01") { date }
+			reservations(from: "2024-06-28", to: "2024-07-01") { checkIn }
+			metrics { averageRate months { month } }
+		}
+	}`, nil, &data)
+	require.Empty(t, response.Errors)
+
+	require.Len(t, data.Rooms, 15)
+	for i, room := range data.Rooms {
+		require.Equal(t, int32(i+6), room.ID)
+		require.Len(t, room.Availability, 3)
+		require.Len(t, room.Reservations, 1)
+		require.Equal(t, 6000.0, room.Metrics.AverageRate)
+		require.Len(t, room.Metrics.Months, 1)
+	}
+
+	// Fields are loaded for the rooms requested within the wait of the loaders, which is usually the whole page,
+	// rather than room by room. Both fields of nights share their loads.
+	require.Equal(t, int32(0), counter.roomsByIDs.Load())
+	require.Less(t, counter.nights.Load(), int32(len(data.Rooms)/2))
+	require.Less(t, counter.rates.Load(), int32(len(data.Rooms)/2))
+	require.Less(t, counter.months.Load(), int32(len(data.Rooms)/2))
+}
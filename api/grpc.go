@@ -0,0 +1,178 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vivek-344/airbnb-api/pb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// grpcErrorDomain is the domain of the `ErrorInfo` details of gRPC errors, whose reason is the error code.
+const grpcErrorDomain = "airbnb-api"
+
+// grpcWriteMethods are the methods requiring an API key, like the writes of the REST API.
+var grpcWriteMethods = map[string]bool{
+	pb.RoomService_CreateRoom_FullMethodName:  true,
+	pb.RoomService_UpdateRoom_FullMethodName:  true,
+	pb.RoomService_DeleteRoom_FullMethodName:  true,
+	pb.RoomService_UpdateNight_FullMethodName: true,
+}
+
+// GRPCServer serves the room service over gRPC, along with the health service and server reflection.
+type GRPCServer struct {
+	pb.UnimplementedRoomServiceServer
+	store  Store
+	server *grpc.Server
+	health *health.Server
+}
+
+// NewGRPCServer creates a gRPC server backed by the same store as the HTTP server.
+func NewGRPCServer(store Store) *GRPCServer {
+	server := &GRPCServer{store: store, health: health.NewServer()}
+	server.server = grpc.NewServer(grpc.ChainUnaryInterceptor(grpcLoggingInterceptor, server.authInterceptor))
+
+	pb.RegisterRoomServiceServer(server.server, server)
+	healthpb.RegisterHealthServer(server.server, server.health)
+	server.health.SetServingStatus(pb.RoomService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	reflection.Register(server.server)
+	return server
+}
+
+// Serve accepts connections on the listener until Stop is called.
+func (server *GRPCServer) Serve(listener net.Listener) error {
+	return server.server.Serve(listener)
+}
+
+// Stop reports the services as not serving and waits for pending requests to complete.
+func (server *GRPCServer) Stop() {
+	server.health.Shutdown()
+	server.server.GracefulStop()
+}
+
+// grpcLoggingInterceptor propagates the incoming `x-request-id` metadata or generates a new one, echoes it
+// in the response headers, writes one structured access log line per call, and turns panics into errors.
+func grpcLoggingInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	start := time.Now()
+
+	var requestID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDHeader); len(values) > 0 {
+			requestID = values[0]
+		}
+	}
+	if !validRequestID(requestID) {
+		requestID = uuid.NewString()
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(requestIDHeader, requestID))
+
+	logger := slog.Default().With(slog.String("request_id", requestID))
+	ctx = context.WithValue(context.WithValue(ctx, loggerKey{}, logger), requestIDContextKey{}, requestID)
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = grpcError(ctx, fmt.Errorf("panic: %v", recovered))
+		}
+
+		code := status.Code(err)
+		level := slog.LevelInfo
+		switch code {
+		case codes.OK:
+		case codes.Internal, codes.Unknown, codes.Unavailable, codes.DataLoss:
+			level = slog.LevelError
+		default:
+			level = slog.LevelWarn
+		}
+		logger.LogAttrs(ctx, level, "request completed",
+			slog.String("method", info.FullMethod),
+			slog.String("code", code.String()),
+			slog.Duration("latency", time.Since(start)),
+		)
+	}()
+
+	return handler(ctx, req)
+}
+
+// authInterceptor authenticates the calls to write methods with an API key sent as `authorization: Bearer <key>`
+// metadata. Writes made by the call are audited as made by the owner of the key.
+func (server *GRPCServer) authInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if !grpcWriteMethods[info.FullMethod] {
+		return handler(ctx, req)
+	}
+
+	var key string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			key, _ = strings.CutPrefix(values[0], "Bearer ")
+		}
+	}
+	if strings.TrimSpace(key) == "" {
+		return nil, grpcError(ctx, newError(ErrUnauthorized, "unauthorized", "An API key is required: send it as `authorization: Bearer <key>` metadata.", nil))
+	}
+
+	apiKey, err := server.store.authenticate(ctx, key)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return handler(WithActor(ctx, apiKey.Name), req)
+}
+
+// grpcError translates err into a domain error and returns it as a gRPC status, whose `ErrorInfo` details
+// carry the error code of the REST API.
+func grpcError(ctx context.Context, err error) error {
+	var domainErr *Error
+	if !errors.As(translateError(err), &domainErr) {
+		return status.Error(codes.Internal, "An unexpected error occurred.")
+	}
+
+	code := grpcCodeFor(domainErr)
+	if code == codes.Internal || code == codes.Unavailable {
+		loggerFromContext(ctx).Error("request failed", slog.String("code", domainErr.Code), slog.Any("error", err))
+	}
+
+	st, detailsErr := status.New(code, domainErr.Message).WithDetails(&errdetails.ErrorInfo{Reason: domainErr.Code, Domain: grpcErrorDomain})
+	if detailsErr != nil {
+		return status.Error(code, domainErr.Message)
+	}
+	return st.Err()
+}
+
+// grpcCodeFor maps a domain error to its gRPC status code.
+func grpcCodeFor(err *Error) codes.Code {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return codes.NotFound
+	case errors.Is(err, ErrConflict) && err.Code == "already_exists":
+		return codes.AlreadyExists
+	case errors.Is(err, ErrConflict) && err.Code == "transaction_conflict":
+		return codes.Aborted
+	case errors.Is(err, ErrConflict):
+		return codes.FailedPrecondition
+	case errors.Is(err, ErrValidation):
+		return codes.InvalidArgument
+	case errors.Is(err, ErrUnauthorized):
+		return codes.Unauthenticated
+	case errors.Is(err, ErrPreconditionFailed):
+		// The version sent with the write is stale: the client should read the resource again and retry.
+		return codes.Aborted
+	case errors.Is(err, ErrPreconditionRequired):
+		return codes.FailedPrecondition
+	case errors.Is(err, ErrUnavailable):
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
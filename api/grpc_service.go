@@ -0,0 +1,388 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+	"github.com/vivek-344/airbnb-api/pb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Default and maximum number of rooms listed or found by a single call.
+const (
+	grpcDefaultLimit = 50
+	grpcMaxLimit     = 500
+)
+
+// GetRoomMetrics returns the occupancy and rates of a room from the metrics cache, like `GET /:room_id`.
+func (server *GRPCServer) GetRoomMetrics(ctx context.Context, req *pb.GetRoomMetricsRequest) (*pb.RoomMetrics, error) {
+	if err := validateRoomID(req.GetRoomId()); err != nil {
+		return nil, grpcError(ctx, err)
+	}
+
+	entry, err := server.store.roomMetrics(ctx, req.GetRoomId(), false)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	var roomData RoomData
+	if err := json.Unmarshal(entry.body, &roomData); err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return newPBRoomMetrics(roomData), nil
+}
+
+// CreateRoom creates a room with an empty calendar.
+func (server *GRPCServer) CreateRoom(ctx context.Context, req *pb.CreateRoomRequest) (*pb.Room, error) {
+	if err := validateRoomID(req.GetRoomId()); err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	if req.GetMaxGuests() < 1 || req.GetDefaultRate() < 1 {
+		return nil, grpcError(ctx, newError(ErrValidation, "invalid_request", "max_guests and default_rate must be at least 1.", nil))
+	}
+
+	room, err := server.store.CreateRoom(ctx, db.CreateRoomParams{
+		RoomID:        req.GetRoomId(),
+		MaxGuests:     req.GetMaxGuests(),
+		Balcony:       req.GetBalcony(),
+		Fridge:        req.GetFridge(),
+		IndoorPool:    req.GetIndoorPool(),
+		GamingConsole: req.GetGamingConsole(),
+		DefaultRate:   req.GetDefaultRate(),
+	})
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return newPBRoom(room), nil
+}
+
+// GetRoom returns a room, like `GET /v1/rooms/:room_id`.
+func (server *GRPCServer) GetRoom(ctx context.Context, req *pb.GetRoomRequest) (*pb.Room, error) {
+	if err := validateRoomID(req.GetRoomId()); err != nil {
+		return nil, grpcError(ctx, err)
+	}
+
+	room, err := server.store.GetRoom(ctx, req.GetRoomId())
+	if err != nil {
+		return nil, grpcError(ctx, roomError(err, req.GetRoomId()))
+	}
+	return newPBRoom(room), nil
+}
+
+// ListRooms returns the rooms by ID.
+func (server *GRPCServer) ListRooms(ctx context.Context, req *pb.ListRoomsRequest) (*pb.ListRoomsResponse, error) {
+	limit, err := grpcLimit(req.GetLimit())
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	if req.GetOffset() < 0 {
+		return nil, grpcError(ctx, newError(ErrValidation, "invalid_request", "offset must not be negative.", nil))
+	}
+
+	rooms, err := server.store.ListRooms(ctx, db.ListRoomsParams{Limit: limit, Offset: req.GetOffset()})
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	response := &pb.ListRoomsResponse{Rooms: []*pb.Room{}}
+	for _, room := range rooms {
+		response.Rooms = append(response.Rooms, newPBRoom(room))
+	}
+	return response, nil
+}
+
+// UpdateRoom updates the given fields of a room if it is still at the version of the request,
+// like `PATCH /v1/rooms/:room_id`.
+func (server *GRPCServer) UpdateRoom(ctx context.Context, req *pb.UpdateRoomRequest) (*pb.Room, error) {
+	if err := validateRoomID(req.GetRoomId()); err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	if (req.MaxGuests != nil && req.GetMaxGuests() < 1) || (req.DefaultRate != nil && req.GetDefaultRate() < 1) {
+		return nil, grpcError(ctx, newError(ErrValidation, "invalid_request", "max_guests and default_rate must be at least 1.", nil))
+	}
+
+	room, err := server.store.updateRoom(ctx, db.UpdateRoomParams{
+		RoomID:        req.GetRoomId(),
+		MaxGuests:     optionalInt4(req.MaxGuests),
+		Balcony:       optionalBool(req.Balcony),
+		Fridge:        optionalBool(req.Fridge),
+		IndoorPool:    optionalBool(req.IndoorPool),
+		GamingConsole: optionalBool(req.GamingConsole),
+		DefaultRate:   optionalInt4(req.DefaultRate),
+	}, checkVersion(req.GetVersion()))
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return newPBRoom(room), nil
+}
+
+// DeleteRoom deletes a room along with its calendar, in a single transaction.
+func (server *GRPCServer) DeleteRoom(ctx context.Context, req *pb.DeleteRoomRequest) (*emptypb.Empty, error) {
+	if err := validateRoomID(req.GetRoomId()); err != nil {
+		return nil, grpcError(ctx, err)
+	}
+
+	err := server.store.execTx(ctx, func(queries db.Querier) error {
+		if _, err := queries.GetRoom(ctx, req.GetRoomId()); err != nil {
+			return roomError(err, req.GetRoomId())
+		}
+		if _, err := queries.DeleteAllAvailabilityForRoom(ctx, req.GetRoomId()); err != nil {
+			return err
+		}
+		return queries.DeleteRoom(ctx, req.GetRoomId())
+	})
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// GetNight returns a night in the calendar of a room, like `GET /v1/rooms/:room_id/availability/:date`.
+func (server *GRPCServer) GetNight(ctx context.Context, req *pb.GetNightRequest) (*pb.Night, error) {
+	night, err := grpcNightRequest(req.GetRoomId(), req.GetDate())
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+
+	availability, err := server.store.GetRoomAvailabilityByDate(ctx, db.GetRoomAvailabilityByDateParams{RoomID: night.RoomID, Date: night.date()})
+	if err != nil {
+		return nil, grpcError(ctx, nightError(err, night))
+	}
+	return newPBNight(availability), nil
+}
+
+// ListNights returns the nights of a room from the first date of the request to the day before the second one.
+func (server *GRPCServer) ListNights(ctx context.Context, req *pb.ListNightsRequest) (*pb.ListNightsResponse, error) {
+	if err := validateRoomID(req.GetRoomId()); err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	from, to, err := parseNightRange(req.GetFrom(), req.GetTo(), "from", "to")
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+
+	if _, err := server.store.GetRoom(ctx, req.GetRoomId()); err != nil {
+		return nil, grpcError(ctx, roomError(err, req.GetRoomId()))
+	}
+	nights, err := server.store.ListRoomNights(ctx, db.ListRoomNightsParams{RoomID: req.GetRoomId(), StartDate: from, EndDate: to})
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	response := &pb.ListNightsResponse{Nights: []*pb.Night{}}
+	for _, night := range nights {
+		response.Nights = append(response.Nights, newPBNight(night))
+	}
+	return response, nil
+}
+
+// UpdateNight sets the availability and rate of a night if it is still at the version of the request,
+// like `PUT /v1/rooms/:room_id/availability/:date`.
+func (server *GRPCServer) UpdateNight(ctx context.Context, req *pb.UpdateNightRequest) (*pb.Night, error) {
+	night, err := grpcNightRequest(req.GetRoomId(), req.GetDate())
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	if req.GetNightRate() < 1 {
+		return nil, grpcError(ctx, newError(ErrValidation, "invalid_request", "night_rate must be at least 1.", nil))
+	}
+
+	availability, err := server.store.updateNight(ctx, night, req.GetIsAvailable(), req.GetNightRate(), checkVersion(req.GetVersion()))
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return newPBNight(availability), nil
+}
+
+// SearchRooms returns the rooms hosting the guests of a stay, with the requested amenities and every night
+// of the stay available, cheapest stay first.
+func (server *GRPCServer) SearchRooms(ctx context.Context, req *pb.SearchRoomsRequest) (*pb.SearchRoomsResponse, error) {
+	stay, err := parseStay(req.GetStay().GetCheckIn(), req.GetStay().GetCheckOut(), req.GetStay().GetGuests())
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	limit, err := grpcLimit(req.GetLimit())
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+
+	rows, err := server.store.SearchAvailableRooms(ctx, db.SearchAvailableRoomsParams{
+		Guests:        stay.Guests,
+		Balcony:       req.GetBalcony(),
+		Fridge:        req.GetFridge(),
+		IndoorPool:    req.GetIndoorPool(),
+		GamingConsole: req.GetGamingConsole(),
+		CheckIn:       stay.CheckIn,
+		CheckOut:      stay.CheckOut,
+		RowLimit:      limit,
+	})
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	response := &pb.SearchRoomsResponse{Results: []*pb.SearchRoomsResponse_Result{}}
+	for _, row := range rows {
+		response.Results = append(response.Results, &pb.SearchRoomsResponse_Result{Room: newPBRoom(row.Room), Total: row.Total})
+	}
+	return response, nil
+}
+
+// GetQuote prices a stay in a room, night by night.
+func (server *GRPCServer) GetQuote(ctx context.Context, req *pb.GetQuoteRequest) (*pb.Quote, error) {
+	if err := validateRoomID(req.GetRoomId()); err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	stay, err := parseStay(req.GetStay().GetCheckIn(), req.GetStay().GetCheckOut(), req.GetStay().GetGuests())
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+
+	quote, err := server.store.quote(ctx, req.GetRoomId(), stay)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return newPBQuote(quote), nil
+}
+
+// validateRoomID fails unless the ID of a room is positive, as room IDs are.
+func validateRoomID(roomID int32) error {
+	if roomID < 1 {
+		return newError(ErrValidation, "invalid_request", "room_id must be at least 1.", nil)
+	}
+	return nil
+}
+
+// grpcNightRequest validates the room ID and `YYYY-MM-DD` date of a night.
+func grpcNightRequest(roomID int32, date string) (nightRequest, error) {
+	if err := validateRoomID(roomID); err != nil {
+		return nightRequest{}, err
+	}
+	if _, err := time.Parse(dateLayout, date); err != nil {
+		return nightRequest{}, newError(ErrValidation, "invalid_request", "date must be formatted as YYYY-MM-DD.", err)
+	}
+	return nightRequest{RoomID: roomID, Date: date}, nil
+}
+
+// grpcLimit returns the number of rows to return for the limit of a request, which defaults to grpcDefaultLimit.
+func grpcLimit(limit int32) (int32, error) {
+	switch {
+	case limit == 0:
+		return grpcDefaultLimit, nil
+	case limit < 0 || limit > grpcMaxLimit:
+		return 0, newError(ErrValidation, "invalid_request", fmt.Sprintf("limit must be between 1 and %d.", grpcMaxLimit), nil)
+	default:
+		return limit, nil
+	}
+}
+
+// checkVersion fails unless the current version of a row is the version sent with a write,
+// which stands for the `If-Match` header of the REST API.
+func checkVersion(expected int32) func(version int32) error {
+	return func(version int32) error {
+		if version != expected {
+			return preconditionFailed()
+		}
+		return nil
+	}
+}
+
+// newPBRoom converts a room to its protobuf representation.
+func newPBRoom(room db.Room) *pb.Room {
+	return &pb.Room{
+		RoomId:        room.RoomID,
+		MaxGuests:     room.MaxGuests,
+		Balcony:       room.Balcony,
+		Fridge:        room.Fridge,
+		IndoorPool:    room.IndoorPool,
+		GamingConsole: room.GamingConsole,
+		DefaultRate:   room.DefaultRate,
+		Version:       room.Version,
+		UpdatedAt:     timestamppb.New(room.UpdatedAt),
+	}
+}
+
+// newPBNight converts a night to its protobuf representation.
+func newPBNight(night db.RoomAvailability) *pb.Night {
+	pbNight := &pb.Night{
+		RoomId:      night.RoomID,
+		Date:        night.Date.Time.Format(dateLayout),
+		IsAvailable: night.IsAvailable,
+		NightRate:   night.NightRate,
+		Version:     night.Version,
+		UpdatedAt:   timestamppb.New(night.UpdatedAt),
+	}
+	if night.BookedAt.Valid {
+		pbNight.BookedAt = timestamppb.New(night.BookedAt.Time)
+	}
+	return pbNight
+}
+
+// newPBQuote converts a quote to its protobuf representation.
+func newPBQuote(quote Quote) *pb.Quote {
+	pbQuote := &pb.Quote{
+		RoomId:      quote.RoomID,
+		CheckIn:     quote.CheckIn.Time.Format(dateLayout),
+		CheckOut:    quote.CheckOut.Time.Format(dateLayout),
+		Guests:      quote.Guests,
+		Nights:      []*pb.Quote_Night{},
+		Total:       quote.Total,
+		AverageRate: quote.AverageRate,
+	}
+	for _, night := range quote.Nights {
+		pbQuote.Nights = append(pbQuote.Nights, &pb.Quote_Night{Date: night.Date.Time.Format(dateLayout), NightRate: night.NightRate})
+	}
+	return pbQuote
+}
+
+// newPBRoomMetrics converts the metrics of a room to their protobuf representation.
+func newPBRoomMetrics(roomData RoomData) *pb.RoomMetrics {
+	metrics := &pb.RoomMetrics{
+		RoomId:         roomData.RoomID,
+		MaxGuests:      roomData.MaxGuests,
+		AvailableDates: roomData.AvailableDates,
+		AverageRate:    roomData.AverageRate,
+		HighestRate:    roomData.HighestRate,
+		LowestRate:     roomData.LowestRate,
+		Balcony:        roomData.Balcony,
+		Fridge:         roomData.MiniFridge,
+		IndoorPool:     roomData.IndoorPool,
+		GamingConsole:  roomData.GamingConsole,
+	}
+	for _, night := range roomData.RatePerNight {
+		metrics.RatePerNight = append(metrics.RatePerNight, &pb.RoomMetrics_NightRate{
+			Date:        night.Date.Time.Format(dateLayout),
+			IsAvailable: night.IsAvailable,
+			NightRate:   night.NightRate,
+		})
+	}
+	for _, month := range roomData.OccupancyPercentage {
+		metrics.OccupancyPercentage = append(metrics.OccupancyPercentage, &pb.RoomMetrics_MonthAvailability{
+			Year:                   int32(numericFloat(month.Year)),
+			Month:                  int32(numericFloat(month.Month)),
+			AvailabilityPercentage: numericFloat(month.AvailabilityPercentage),
+		})
+	}
+	for _, stats := range roomData.MonthlyStats {
+		metrics.MonthlyStats = append(metrics.MonthlyStats, &pb.RoomMetrics_MonthStats{
+			Month:               stats.Month,
+			Nights:              stats.Nights,
+			BookedNights:        stats.BookedNights,
+			OccupancyPercentage: stats.OccupancyPercentage,
+			Revenue:             stats.Revenue,
+			AverageRate:         stats.AverageRate,
+			LowestRate:          stats.LowestRate,
+			HighestRate:         stats.HighestRate,
+		})
+	}
+	return metrics
+}
+
+// numericFloat converts a NUMERIC to a float, which is 0 for NULL.
+func numericFloat(value pgtype.Numeric) float64 {
+	float, err := value.Float64Value()
+	if err != nil || !float.Valid {
+		return 0
+	}
+	return float.Float64
+}
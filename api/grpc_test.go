@@ -0,0 +1,240 @@
+package api
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+	"github.com/vivek-344/airbnb-api/pb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// newGRPCTestClient serves a gRPC server backed by an in-memory store over an in-process connection
+// and returns a client connected to it.
+func newGRPCTestClient(t *testing.T) (*grpc.ClientConn, *Store) {
+	t.Helper()
+	_, store := newTestServer(t)
+	server := NewGRPCServer(*store)
+
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn, store
+}
+
+// withTestAPIKey returns a context authenticating calls with a new API key.
+func withTestAPIKey(t *testing.T, store *Store, name string) context.Context {
+	t.Helper()
+	header := createTestAPIKey(t, store, name)
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", header.Get("Authorization"))
+}
+
+// requireGRPCError checks that err is a gRPC status with the given code and error code as reason.
+func requireGRPCError(t *testing.T, err error, code codes.Code, reason string) {
+	t.Helper()
+	st, ok := status.FromError(err)
+	require.True(t, ok, "not a gRPC status: %v", err)
+	require.Equal(t, code, st.Code(), st.Message())
+
+	require.Len(t, st.Details(), 1)
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	require.Equal(t, reason, info.Reason)
+	require.Equal(t, grpcErrorDomain, info.Domain)
+}
+
+func TestGRPCRooms(t *testing.T) {
+	conn, store := newGRPCTestClient(t)
+	client := pb.NewRoomServiceClient(conn)
+	ctx := context.Background()
+	authCtx := withTestAPIKey(t, store, "inventory")
+
+	// Writes require an API key.
+	_, err := client.CreateRoom(ctx, &pb.CreateRoomRequest{RoomId: 1, MaxGuests: 2, DefaultRate: 5000})
+	requireGRPCError(t, err, codes.Unauthenticated, "unauthorized")
+	badKeyCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer unknown")
+	_, err = client.CreateRoom(badKeyCtx, &pb.CreateRoomRequest{RoomId: 1, MaxGuests: 2, DefaultRate: 5000})
+	requireGRPCError(t, err, codes.Unauthenticated, "unauthorized")
+
+	var header metadata.MD
+	created, err := client.CreateRoom(authCtx, &pb.CreateRoomRequest{RoomId: 1, MaxGuests: 2, Fridge: true, DefaultRate: 5000}, grpc.Header(&header))
+	require.NoError(t, err)
+	require.Equal(t, int32(1), created.RoomId)
+	require.True(t, created.Fridge)
+	require.Len(t, header.Get(requestIDHeader), 1)
+
+	_, err = client.CreateRoom(authCtx, &pb.CreateRoomRequest{RoomId: 1, MaxGuests: 2, DefaultRate: 5000})
+	requireGRPCError(t, err, codes.AlreadyExists, "already_exists")
+	_, err = client.CreateRoom(authCtx, &pb.CreateRoomRequest{RoomId: 2, DefaultRate: 5000})
+	requireGRPCError(t, err, codes.InvalidArgument, "invalid_request")
+
+	room, err := client.GetRoom(ctx, &pb.GetRoomRequest{RoomId: 1})
+	require.NoError(t, err)
+	require.True(t, proto.Equal(created, room))
+
+	// Updates are rejected unless they are based on the current version of the room.
+	_, err = client.UpdateRoom(authCtx, &pb.UpdateRoomRequest{RoomId: 1, Version: room.Version + 1, MaxGuests: proto.Int32(3)})
+	requireGRPCError(t, err, codes.Aborted, "precondition_failed")
+	updated, err := client.UpdateRoom(authCtx, &pb.UpdateRoomRequest{RoomId: 1, Version: room.Version, MaxGuests: proto.Int32(3)})
+	require.NoError(t, err)
+	require.Equal(t, int32(3), updated.MaxGuests)
+	require.True(t, updated.Fridge)
+	require.Equal(t, room.Version+1, updated.Version)
+
+	// The writes are audited as made by the owner of the API key.
+	events, err := store.ListRoomAuditEvents(ctx, db.ListRoomAuditEventsParams{RoomID: 1, RowLimit: 10})
+	require.NoError(t, err)
+	require.NotEmpty(t, events)
+	for _, event := range events {
+		require.Equal(t, "inventory", event.Actor)
+	}
+
+	createTestRoom(t, store, 2, 5000, 6000)
+	rooms, err := client.ListRooms(ctx, &pb.ListRoomsRequest{Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, rooms.Rooms, 1)
+	require.Equal(t, int32(2), rooms.Rooms[0].RoomId)
+	_, err = client.ListRooms(ctx, &pb.ListRoomsRequest{Limit: grpcMaxLimit + 1})
+	requireGRPCError(t, err, codes.InvalidArgument, "invalid_request")
+
+	metrics, err := client.GetRoomMetrics(ctx, &pb.GetRoomMetricsRequest{RoomId: 2})
+	require.NoError(t, err)
+	require.Len(t, metrics.RatePerNight, 2)
+	require.Equal(t, int32(6000), metrics.HighestRate)
+	require.True(t, metrics.Balcony)
+
+	// Deleting a room deletes its calendar.
+	_, err = client.DeleteRoom(authCtx, &pb.DeleteRoomRequest{RoomId: 2})
+	require.NoError(t, err)
+	_, err = client.GetRoom(ctx, &pb.GetRoomRequest{RoomId: 2})
+	requireGRPCError(t, err, codes.NotFound, "room_not_found")
+	_, err = client.DeleteRoom(authCtx, &pb.DeleteRoomRequest{RoomId: 2})
+	requireGRPCError(t, err, codes.NotFound, "room_not_found")
+	_, err = client.GetRoomMetrics(ctx, &pb.GetRoomMetricsRequest{RoomId: 2})
+	requireGRPCError(t, err, codes.NotFound, "room_not_found")
+}
+
+func TestGRPCNights(t *testing.T) {
+	conn, store := newGRPCTestClient(t)
+	client := pb.NewRoomServiceClient(conn)
+	ctx := context.Background()
+	authCtx := withTestAPIKey(t, store, "revenue")
+	createTestRoom(t, store, 1, 5000, 6000, 7000)
+
+	date := testNight(1).Time.Format(dateLayout)
+	night, err := client.GetNight(ctx, &pb.GetNightRequest{RoomId: 1, Date: date})
+	require.NoError(t, err)
+	require.False(t, night.IsAvailable)
+	require.Equal(t, int32(6000), night.NightRate)
+	require.NotNil(t, night.BookedAt)
+
+	_, err = client.GetNight(ctx, &pb.GetNightRequest{RoomId: 1, Date: testNight(5).Time.Format(dateLayout)})
+	requireGRPCError(t, err, codes.NotFound, "night_not_found")
+	_, err = client.GetNight(ctx, &pb.GetNightRequest{RoomId: 1, Date: "tomorrow"})
+	requireGRPCError(t, err, codes.InvalidArgument, "invalid_request")
+
+	_, err = client.UpdateNight(ctx, &pb.UpdateNightRequest{RoomId: 1, Date: date, Version: night.Version, IsAvailable: true, NightRate: 6500})
+	requireGRPCError(t, err, codes.Unauthenticated, "unauthorized")
+	_, err = client.UpdateNight(authCtx, &pb.UpdateNightRequest{RoomId: 1, Date: date, Version: night.Version - 1, IsAvailable: true, NightRate: 6500})
+	requireGRPCError(t, err, codes.Aborted, "precondition_failed")
+	updated, err := client.UpdateNight(authCtx, &pb.UpdateNightRequest{RoomId: 1, Date: date, Version: night.Version, IsAvailable: true, NightRate: 6500})
+	require.NoError(t, err)
+	require.True(t, updated.IsAvailable)
+	require.Nil(t, updated.BookedAt)
+	require.Equal(t, night.Version+1, updated.Version)
+
+	nights, err := client.ListNights(ctx, &pb.ListNightsRequest{
+		RoomId: 1,
+		From:   testNight(1).Time.Format(dateLayout),
+		To:     testNight(5).Time.Format(dateLayout),
+	})
+	require.NoError(t, err)
+	require.Len(t, nights.Nights, 2)
+	require.True(t, proto.Equal(updated, nights.Nights[0]))
+	require.Equal(t, testNight(2).Time.Format(dateLayout), nights.Nights[1].Date)
+
+	_, err = client.ListNights(ctx, &pb.ListNightsRequest{RoomId: 2, From: date, To: testNight(5).Time.Format(dateLayout)})
+	requireGRPCError(t, err, codes.NotFound, "room_not_found")
+	_, err = client.ListNights(ctx, &pb.ListNightsRequest{RoomId: 1, From: date, To: date})
+	requireGRPCError(t, err, codes.InvalidArgument, "invalid_request")
+}
+
+func TestGRPCSearchAndQuote(t *testing.T) {
+	conn, store := newGRPCTestClient(t)
+	client := pb.NewRoomServiceClient(conn)
+	ctx := context.Background()
+	createTestRoom(t, store, 1, 5000, 6000, 7000)
+	createTestRoom(t, store, 2, 4000, 3000, 4000)
+	writeTestNight(t, store, 2, 1, true, 3000)
+
+	stay := func(from, to int, guests int32) *pb.Stay {
+		return &pb.Stay{CheckIn: testNight(from).Time.Format(dateLayout), CheckOut: testNight(to).Time.Format(dateLayout), Guests: guests}
+	}
+
+	// Room 1 is booked on the second night, so only room 2 is available for the whole stay.
+	found, err := client.SearchRooms(ctx, &pb.SearchRoomsRequest{Stay: stay(0, 3, 2)})
+	require.NoError(t, err)
+	require.Len(t, found.Results, 1)
+	require.Equal(t, int32(2), found.Results[0].Room.RoomId)
+	require.Equal(t, int64(11000), found.Results[0].Total)
+
+	found, err = client.SearchRooms(ctx, &pb.SearchRoomsRequest{Stay: stay(0, 1, 0), Balcony: true})
+	require.NoError(t, err)
+	require.Len(t, found.Results, 2)
+	require.Equal(t, int32(2), found.Results[0].Room.RoomId, "the cheapest stay comes first")
+
+	found, err = client.SearchRooms(ctx, &pb.SearchRoomsRequest{Stay: stay(0, 1, 0), IndoorPool: true})
+	require.NoError(t, err)
+	require.Empty(t, found.Results)
+	found, err = client.SearchRooms(ctx, &pb.SearchRoomsRequest{Stay: stay(0, 1, 5)})
+	require.NoError(t, err)
+	require.Empty(t, found.Results)
+
+	_, err = client.SearchRooms(ctx, &pb.SearchRoomsRequest{Stay: stay(1, 0, 1)})
+	requireGRPCError(t, err, codes.InvalidArgument, "invalid_request")
+	_, err = client.SearchRooms(ctx, &pb.SearchRoomsRequest{})
+	requireGRPCError(t, err, codes.InvalidArgument, "invalid_request")
+
+	quote, err := client.GetQuote(ctx, &pb.GetQuoteRequest{RoomId: 2, Stay: stay(0, 3, 2)})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), quote.Guests)
+	require.Len(t, quote.Nights, 3)
+	require.Equal(t, int32(3000), quote.Nights[1].NightRate)
+	require.Equal(t, int64(11000), quote.Total)
+	require.Equal(t, 3666.67, quote.AverageRate)
+
+	_, err = client.GetQuote(ctx, &pb.GetQuoteRequest{RoomId: 1, Stay: stay(0, 3, 2)})
+	requireGRPCError(t, err, codes.FailedPrecondition, "room_unavailable")
+	_, err = client.GetQuote(ctx, &pb.GetQuoteRequest{RoomId: 2, Stay: stay(2, 4, 2)})
+	requireGRPCError(t, err, codes.FailedPrecondition, "room_unavailable")
+	_, err = client.GetQuote(ctx, &pb.GetQuoteRequest{RoomId: 2, Stay: stay(0, 1, 5)})
+	requireGRPCError(t, err, codes.InvalidArgument, "too_many_guests")
+	_, err = client.GetQuote(ctx, &pb.GetQuoteRequest{RoomId: 3, Stay: stay(0, 1, 1)})
+	requireGRPCError(t, err, codes.NotFound, "room_not_found")
+}
+
+func TestGRPCHealth(t *testing.T) {
+	conn, _ := newGRPCTestClient(t)
+	client := healthpb.NewHealthClient(conn)
+
+	response, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: pb.RoomService_ServiceDesc.ServiceName})
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, response.Status)
+}
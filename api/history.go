@@ -0,0 +1,61 @@
+package api
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// historyRequest defines the query parameters filtering the history of a room.
+type historyRequest struct {
+	Date   string    `form:"date" binding:"omitempty,datetime=2006-01-02"`
+	Actor  string    `form:"actor"`
+	From   time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To     time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit  int32     `form:"limit,default=50" binding:"min=1,max=500"`
+	Offset int32     `form:"offset" binding:"min=0"`
+}
+
+// getRoomHistory returns the audit log of a room, most recent events first. Events can be filtered by
+// the night they changed (`date`), by actor, and by the time they occurred (`from` inclusive, `to` exclusive).
+// The history of deleted rooms remains available.
+func (server *Server) getRoomHistory(ctx *gin.Context) {
+	var req getRoomRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, newError(ErrValidation, "invalid_request", err.Error(), err))
+		return
+	}
+
+	var query historyRequest
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		abortWithError(ctx, newError(ErrValidation, "invalid_request", err.Error(), err))
+		return
+	}
+
+	arg := db.ListRoomAuditEventsParams{
+		RoomID:    req.RoomID,
+		Actor:     pgtype.Text{String: query.Actor, Valid: query.Actor != ""},
+		Since:     pgtype.Timestamptz{Time: query.From, Valid: !query.From.IsZero()},
+		Until:     pgtype.Timestamptz{Time: query.To, Valid: !query.To.IsZero()},
+		RowLimit:  query.Limit,
+		RowOffset: query.Offset,
+	}
+	if query.Date != "" {
+		date, _ := time.Parse(dateLayout, query.Date) // Validated when binding.
+		arg.Date = pgtype.Date{Time: date, Valid: true}
+	}
+
+	events, err := server.store.ListRoomAuditEvents(ctx, arg)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	history := RoomHistory{RoomID: req.RoomID, Events: make([]AuditEvent, 0, len(events))}
+	for _, event := range events {
+		history.Events = append(history.Events, newAuditEvent(event))
+	}
+	ctx.JSON(200, history)
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// getHistory fetches the history of a room and decodes it.
+func getHistory(t *testing.T, server *Server, path string, header http.Header) RoomHistory {
+	t.Helper()
+	recorder := serve(server, http.MethodGet, path, header)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var history RoomHistory
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &history))
+	return history
+}
+
+func TestRoomHistory(t *testing.T) {
+	server, store := newTestServer(t)
+	createTestRoom(t, store, 1, 5000, 6000)
+	auth := createTestAPIKey(t, store, "host")
+	date := testNight(1).Time.Format(dateLayout)
+
+	recorder := serveJSON(server, http.MethodPut, "/v1/rooms/1/availability/"+date,
+		withIfMatch(auth, `"1"`), `{"is_available": false, "night_rate": 7000}`)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	requestID := recorder.Header().Get(requestIDHeader)
+
+	_, err := store.UpdateMaxGuests(WithActor(context.Background(), "cli:ops"), db.UpdateMaxGuestsParams{RoomID: 1, MaxGuests: 6})
+	require.NoError(t, err)
+
+	// The history is only readable with an API key.
+	requireProblem(t, serve(server, http.MethodGet, "/v1/rooms/1/history", nil), http.StatusUnauthorized, "unauthorized")
+
+	history := getHistory(t, server, "/v1/rooms/1/history", auth)
+	require.Equal(t, int32(1), history.RoomID)
+	require.Len(t, history.Events, 5)
+
+	// Most recent first: the guest limit, the rate, then the creation of the room and its nights.
+	require.Equal(t, "cli:ops", history.Events[0].Actor)
+	require.Equal(t, actionRoomUpdate, history.Events[0].Action)
+	require.JSONEq(t, `{"max_guests": 4, "version": 1}`, string(history.Events[0].Before))
+	require.JSONEq(t, `{"max_guests": 6, "version": 2}`, string(history.Events[0].After))
+	require.Empty(t, history.Events[0].RequestID)
+
+	rate := history.Events[1]
+	require.Equal(t, "host", rate.Actor)
+	require.Equal(t, actionNightUpdate, rate.Action)
+	require.Equal(t, date, *rate.Date)
+	require.JSONEq(t, `{"night_rate": 6000, "version": 1}`, string(rate.Before))
+	require.JSONEq(t, `{"night_rate": 7000, "version": 2}`, string(rate.After))
+	require.Equal(t, requestID, rate.RequestID)
+
+	require.Equal(t, systemActor, history.Events[4].Actor)
+	require.Equal(t, actionRoomCreate, history.Events[4].Action)
+	require.Equal(t, "null", string(history.Events[4].Before))
+
+	// Who changed the rate of the night, and when?
+	history = getHistory(t, server, "/v1/rooms/1/history?date="+date+"&actor=host", auth)
+	require.Len(t, history.Events, 1)
+	require.Equal(t, rate, history.Events[0])
+
+	history = getHistory(t, server, "/v1/rooms/1/history?limit=1&offset=1", auth)
+	require.Equal(t, []AuditEvent{rate}, history.Events)
+
+	history = getHistory(t, server, "/v1/rooms/1/history?from=2024-06-29T00:00:00Z", auth)
+	require.Empty(t, history.Events)
+	history = getHistory(t, server, "/v1/rooms/2/history", auth)
+	require.Empty(t, history.Events)
+
+	requireProblem(t, serve(server, http.MethodGet, "/v1/rooms/1/history?date=20240628", auth), http.StatusBadRequest, "invalid_request")
+	requireProblem(t, serve(server, http.MethodGet, "/v1/rooms/1/history?limit=0", auth), http.StatusBadRequest, "invalid_request")
+}
+
+func TestAuditRollback(t *testing.T) {
+	server, store := newTestServer(t)
+	createTestRoom(t, store, 1)
+	auth := createTestAPIKey(t, store, "host")
+	failure := errors.New("failure")
+
+	// Events are recorded in the transaction of the write, so rolled back writes leave no trace.
+	err := store.ExecTx(context.Background(), TxOptions{}, func(queries db.Querier) error {
+		_, err := queries.UpdateMaxGuests(context.Background(), db.UpdateMaxGuestsParams{RoomID: 1, MaxGuests: 6})
+		require.NoError(t, err)
+		return failure
+	})
+	require.ErrorIs(t, err, failure)
+
+	// A failed write is not recorded either.
+	_, err = store.CreateRoom(context.Background(), db.CreateRoomParams{RoomID: 1, MaxGuests: 2, DefaultRate: 5000})
+	require.Error(t, err)
+
+	history := getHistory(t, server, "/v1/rooms/1/history", auth)
+	require.Len(t, history.Events, 1)
+	require.Equal(t, actionRoomCreate, history.Events[0].Action)
+}
+
+func TestAuditDiff(t *testing.T) {
+	before, after, err := auditDiff(db.Room{RoomID: 1, MaxGuests: 2, DefaultRate: 5000}, db.Room{RoomID: 1, MaxGuests: 2, DefaultRate: 6000})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"default_rate": 5000}`, string(before))
+	require.JSONEq(t, `{"default_rate": 6000}`, string(after))
+
+	before, after, err = auditDiff(nightCount{Nights: 3}, nil)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"nights": 3}`, string(before))
+	require.Nil(t, after)
+}
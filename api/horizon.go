@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// horizonLockID is the key of the advisory lock electing the replica that maintains availability horizons.
+const horizonLockID = 7_344_002
+
+// HorizonResult summarizes a run of the availability horizon job.
+type HorizonResult struct {
+	Rooms    int
+	Archived int64
+	Added    int64
+}
+
+// HorizonJob returns the job keeping the calendar of every room horizonDays nights ahead.
+func (store *Store) HorizonJob(horizonDays int32, interval time.Duration) Job {
+	return Job{
+		Name:     "availability_horizon",
+		LockID:   horizonLockID,
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			_, err := store.MaintainHorizon(ctx, horizonDays)
+			return err
+		},
+	}
+}
+
+// MaintainHorizon archives the past nights of every room and adds the missing nights up to horizonDays
+// from today, available at the default rate of the room. Existing nights are never modified.
+// A failing room does not stop the others; all failures are returned together.
+func (store *Store) MaintainHorizon(ctx context.Context, horizonDays int32) (HorizonResult, error) {
+	roomIDs, err := store.ListAllRoomIDs(ctx)
+	if err != nil {
+		return HorizonResult{}, fmt.Errorf("cannot list rooms: %w", err)
+	}
+
+	var result HorizonResult
+	var errs []error
+	for _, roomID := range roomIDs {
+		if ctx.Err() != nil {
+			errs = append(errs, ctx.Err())
+			break
+		}
+
+		archived, added, err := store.rollRoomAvailability(ctx, roomID, horizonDays)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("room %d: %w", roomID, err))
+			continue
+		}
+		result.Rooms++
+		result.Archived += archived
+		result.Added += added
+	}
+
+	slog.Info("availability horizon maintained",
+		slog.Int("rooms", result.Rooms),
+		slog.Int64("archived", result.Archived),
+		slog.Int64("added", result.Added),
+		slog.Int("failed", len(errs)))
+	return result, errors.Join(errs...)
+}
+
+// rollRoomAvailability moves the calendar of a room forward in a single transaction.
+// Committing it drops the cached metrics.
+func (store *Store) rollRoomAvailability(ctx context.Context, roomID int32, horizonDays int32) (archived, added int64, err error) {
+	err = store.execTx(ctx, func(queries db.Querier) error {
+		archivedDates, err := queries.ArchiveRoomPastAvailability(ctx, roomID)
+		if err != nil {
+			return err
+		}
+		addedDates, err := queries.ExtendRoomAvailability(ctx, db.ExtendRoomAvailabilityParams{RoomID: roomID, HorizonDays: horizonDays})
+		archived, added = int64(len(archivedDates)), int64(len(addedDates))
+		return err
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return archived, added, nil
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+func TestMaintainHorizon(t *testing.T) {
+	_, store := newTestServer(t)
+	ctx := context.Background()
+	createTestRoom(t, store, 101, 7000, 7000)
+	createTestRoom(t, store, 102)
+
+	_, err := store.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{RoomID: 101, Date: testNight(-1), NightRate: 7000})
+	require.NoError(t, err)
+
+	result, err := store.MaintainHorizon(ctx, 10)
+	require.NoError(t, err)
+	require.Equal(t, HorizonResult{Rooms: 2, Archived: 1, Added: 18}, result)
+
+	// Existing nights are kept and new ones use the default rate of the room.
+	kept, err := store.GetRoomAvailabilityByDate(ctx, db.GetRoomAvailabilityByDateParams{RoomID: 101, Date: testNight(0)})
+	require.NoError(t, err)
+	require.Equal(t, int32(7000), kept.NightRate)
+
+	added, err := store.GetRoomAvailabilityByDate(ctx, db.GetRoomAvailabilityByDateParams{RoomID: 102, Date: testNight(9)})
+	require.NoError(t, err)
+	require.True(t, added.IsAvailable)
+	require.Equal(t, int32(5000), added.NightRate)
+
+	// The job is idempotent within a day.
+	ran, err := store.RunJobOnce(ctx, store.HorizonJob(10, 0))
+	require.NoError(t, err)
+	require.True(t, ran)
+
+	count, err := store.GetDateCount(ctx, 102)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), count)
+}
@@ -0,0 +1,348 @@
+package api
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// Limits of the CSV imports.
+const (
+	maxImportBytes     = 10 << 20 // Size of an imported file.
+	maxImportGuests    = 16       // Guests of an imported room.
+	maxImportDaysAhead = 730      // Days from today to the last imported night.
+)
+
+// Headers expected in imported CSV files.
+var (
+	roomImportColumns     = []string{"room_id", "max_guests", "balcony", "fridge", "indoor_pool", "gaming_console", "default_rate"}
+	calendarImportColumns = []string{"room_id", "date", "is_available", "night_rate"}
+)
+
+// errImportRolledBack rolls back the transaction of a dry run or of an import with invalid rows.
+var errImportRolledBack = errors.New("import rolled back")
+
+// importRequest defines the query parameters of an import.
+type importRequest struct {
+	DryRun bool `form:"dry_run"`
+}
+
+// importErrors collects the errors of the rows of an import by line.
+type importErrors map[int][]ImportFieldError
+
+// add records an error of a column of the row on the given line.
+func (errs importErrors) add(line int, column, message string) {
+	errs[line] = append(errs[line], ImportFieldError{Column: column, Message: message})
+}
+
+// clone copies the errors, so that a transaction run again starts from the errors found while parsing.
+func (errs importErrors) clone() importErrors {
+	clone := make(importErrors, len(errs))
+	for line, fieldErrors := range errs {
+		clone[line] = slices.Clone(fieldErrors)
+	}
+	return clone
+}
+
+// list returns the errors ordered by line.
+func (errs importErrors) list() []ImportRowError {
+	rows := []ImportRowError{}
+	for line, fieldErrors := range errs {
+		rows = append(rows, ImportRowError{Line: line, Errors: fieldErrors})
+	}
+	slices.SortFunc(rows, func(a, b ImportRowError) int { return a.Line - b.Line })
+	return rows
+}
+
+// importRow is a parsed row of an import along with its line in the file.
+type importRow[T any] struct {
+	line  int
+	value T
+}
+
+// readImport reads the CSV body of an import, whose header must match columns, and parses every record.
+// Records with errors are reported rather than returned. Only an unreadable file fails the request.
+func readImport[T any](ctx *gin.Context, columns []string, parse func(record []string, line int, errs importErrors) T) ([]importRow[T], importErrors, error) {
+	reader := csv.NewReader(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportBytes))
+	reader.FieldsPerRecord = -1 // The number of fields is reported per row.
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, importReadError(err)
+	}
+	if len(header) != len(columns) || !slices.EqualFunc(header, columns, func(a, b string) bool { return strings.TrimSpace(a) == b }) {
+		message := fmt.Sprintf("The CSV header must be `%s`.", strings.Join(columns, ","))
+		return nil, nil, newError(ErrValidation, "invalid_request", message, nil)
+	}
+
+	var rows []importRow[T]
+	errs := importErrors{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, errs, nil
+		}
+		if err != nil {
+			return nil, nil, importReadError(err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		if len(record) != len(columns) {
+			errs.add(line, "", fmt.Sprintf("expected %d fields, got %d", len(columns), len(record)))
+			continue
+		}
+		for i := range record {
+			record[i] = strings.TrimSpace(record[i])
+		}
+		value := parse(record, line, errs)
+		if _, invalid := errs[line]; !invalid {
+			rows = append(rows, importRow[T]{line: line, value: value})
+		}
+	}
+}
+
+// importReadError reports a file that cannot be read as CSV or exceeds the size limit.
+func importReadError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return newError(ErrValidation, "invalid_request", fmt.Sprintf("The file must not exceed %d bytes.", maxBytesErr.Limit), err)
+	}
+	if errors.Is(err, io.EOF) {
+		return newError(ErrValidation, "invalid_request", "The file is empty.", err)
+	}
+	return newError(ErrValidation, "invalid_request", fmt.Sprintf("The file is not valid CSV: %v.", err), err)
+}
+
+// parseInt32 parses a column holding an integer between min and max.
+func parseInt32(value string, min, max int32) (int32, error) {
+	number, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not an integer", value)
+	}
+	if number < int64(min) || number > int64(max) {
+		return 0, fmt.Errorf("must be between %d and %d", min, max)
+	}
+	return int32(number), nil
+}
+
+// parseBool parses a boolean column.
+func parseBool(value string) (bool, error) {
+	boolean, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%q is not a boolean", value)
+	}
+	return boolean, nil
+}
+
+// parseRoomRecord parses a row of a room import.
+func parseRoomRecord(record []string, line int, errs importErrors) db.UpsertRoomParams {
+	var room db.UpsertRoomParams
+	var err error
+	if room.RoomID, err = parseInt32(record[0], 1, math.MaxInt32); err != nil {
+		errs.add(line, "room_id", err.Error())
+	}
+	if room.MaxGuests, err = parseInt32(record[1], 1, maxImportGuests); err != nil {
+		errs.add(line, "max_guests", err.Error())
+	}
+	for i, amenity := range []*bool{&room.Balcony, &room.Fridge, &room.IndoorPool, &room.GamingConsole} {
+		if *amenity, err = parseBool(record[2+i]); err != nil {
+			errs.add(line, roomImportColumns[2+i], err.Error())
+		}
+	}
+	if room.DefaultRate, err = parseInt32(record[6], 1, math.MaxInt32); err != nil {
+		errs.add(line, "default_rate", err.Error())
+	}
+	return room
+}
+
+// parseCalendarRecord parses a row of a calendar import. Dates are checked against today in the transaction.
+func parseCalendarRecord(record []string, line int, errs importErrors) db.UpsertRoomAvailabilityParams {
+	var night db.UpsertRoomAvailabilityParams
+	var err error
+	if night.RoomID, err = parseInt32(record[0], 1, math.MaxInt32); err != nil {
+		errs.add(line, "room_id", err.Error())
+	}
+	if date, err := time.Parse(dateLayout, record[1]); err != nil {
+		errs.add(line, "date", fmt.Sprintf("%q is not a date in the YYYY-MM-DD format", record[1]))
+	} else {
+		night.Date = pgtype.Date{Time: date, Valid: true}
+	}
+	if night.IsAvailable, err = parseBool(record[2]); err != nil {
+		errs.add(line, "is_available", err.Error())
+	}
+	if night.NightRate, err = parseInt32(record[3], 1, math.MaxInt32); err != nil {
+		errs.add(line, "night_rate", err.Error())
+	}
+	return night
+}
+
+// bindImport binds the mode of an import.
+func bindImport(ctx *gin.Context) (importRequest, error) {
+	var req importRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		return importRequest{}, newError(ErrValidation, "invalid_request", err.Error(), err)
+	}
+	return req, nil
+}
+
+// runImport runs fn in a transaction, which is committed unless the import is a dry run or a row is invalid.
+// fn validates the rows against the database and writes them, counting the rows created and updated.
+// The report is returned with 422 Unprocessable Entity if a row is invalid.
+func (server *Server) runImport(ctx *gin.Context, req importRequest, rows int, parseErrs importErrors, fn func(queries db.Querier, errs importErrors, report *ImportReport) error) {
+	var report ImportReport
+	var errs importErrors
+	err := server.store.ExecTx(ctx, TxOptions{}, func(queries db.Querier) error {
+		report = ImportReport{DryRun: req.DryRun, Rows: rows}
+		errs = parseErrs.clone()
+		if err := fn(queries, errs, &report); err != nil {
+			return err
+		}
+		if req.DryRun || len(errs) > 0 {
+			return errImportRolledBack
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportRolledBack) {
+		abortWithError(ctx, err)
+		return
+	}
+
+	report.Committed = err == nil
+	report.Errors = errs.list()
+	if len(report.Errors) > 0 {
+		ctx.JSON(http.StatusUnprocessableEntity, report)
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
+
+// importRooms creates or replaces the rooms of a CSV file with the header
+// `room_id,max_guests,balcony,fridge,indoor_pool,gaming_console,default_rate`.
+func (server *Server) importRooms(ctx *gin.Context) {
+	req, err := bindImport(ctx)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	rows, parseErrs, err := readImport(ctx, roomImportColumns, parseRoomRecord)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+	total := len(rows) + len(parseErrs)
+
+	// A room listed twice would be silently replaced by its last row.
+	lines := make(map[int32]int, len(rows))
+	for _, row := range rows {
+		if line, ok := lines[row.value.RoomID]; ok {
+			parseErrs.add(row.line, "room_id", fmt.Sprintf("room %d is already on line %d", row.value.RoomID, line))
+			continue
+		}
+		lines[row.value.RoomID] = row.line
+	}
+
+	server.runImport(ctx, req, total, parseErrs, func(queries db.Querier, errs importErrors, report *ImportReport) error {
+		if len(errs) > 0 {
+			return nil // Nothing would be committed.
+		}
+		for _, row := range rows {
+			existing, err := queries.GetRoom(ctx, row.value.RoomID)
+			if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+				return err
+			}
+			room, err := queries.UpsertRoom(ctx, row.value)
+			if err != nil {
+				return err
+			}
+			if room.Version == existing.Version {
+				report.Unchanged++ // The room already had these fields.
+				continue
+			}
+			report.count(room.Version)
+		}
+		return nil
+	})
+}
+
+// importCalendar creates or replaces the nights of a CSV file with the header `room_id,date,is_available,night_rate`.
+// Rooms must exist and nights must be between today and maxImportDaysAhead days from today.
+func (server *Server) importCalendar(ctx *gin.Context) {
+	req, err := bindImport(ctx)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	rows, parseErrs, err := readImport(ctx, calendarImportColumns, parseCalendarRecord)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+	total := len(rows) + len(parseErrs)
+
+	lines := make(map[string]int, len(rows))
+	for _, row := range rows {
+		key := fmt.Sprintf("%d/%s", row.value.RoomID, row.value.Date.Time.Format(dateLayout))
+		if line, ok := lines[key]; ok {
+			parseErrs.add(row.line, "date", fmt.Sprintf("the night is already on line %d", line))
+			continue
+		}
+		lines[key] = row.line
+	}
+
+	server.runImport(ctx, req, total, parseErrs, func(queries db.Querier, errs importErrors, report *ImportReport) error {
+		today, err := queries.GetCurrentDate(ctx)
+		if err != nil {
+			return err
+		}
+		last := today.Time.AddDate(0, 0, maxImportDaysAhead)
+
+		rooms := make(map[int32]bool)
+		for _, row := range rows {
+			night := row.value
+			if night.Date.Time.Before(today.Time) {
+				errs.add(row.line, "date", "must not be in the past")
+			} else if night.Date.Time.After(last) {
+				errs.add(row.line, "date", fmt.Sprintf("must be at most %d days from today", maxImportDaysAhead))
+			}
+
+			exists, checked := rooms[night.RoomID]
+			if !checked {
+				_, err := queries.GetRoom(ctx, night.RoomID)
+				if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+					return err
+				}
+				exists = err == nil
+				rooms[night.RoomID] = exists
+			}
+			if !exists {
+				errs.add(row.line, "room_id", fmt.Sprintf("room %d does not exist", night.RoomID))
+			}
+		}
+		if len(errs) > 0 {
+			return nil // Nothing would be committed.
+		}
+
+		for _, row := range rows {
+			night, err := queries.UpsertRoomAvailability(ctx, row.value)
+			if err != nil {
+				return err
+			}
+			report.count(night.Version)
+		}
+		return nil
+	})
+}
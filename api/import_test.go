@@ -0,0 +1,175 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// serveCSV posts a CSV file to the server and returns the recorded response.
+func serveCSV(server *Server, path string, header http.Header, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "text/csv")
+	for name, values := range header {
+		request.Header[name] = values
+	}
+	recorder := httptest.NewRecorder()
+	server.Router().ServeHTTP(recorder, request)
+	return recorder
+}
+
+// readImportReport decodes the report of an import with the given status.
+func readImportReport(t *testing.T, recorder *httptest.ResponseRecorder, status int) ImportReport {
+	t.Helper()
+	require.Equal(t, status, recorder.Code, recorder.Body.String())
+
+	var report ImportReport
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+	return report
+}
+
+const roomsCSV = `room_id,max_guests,balcony,fridge,indoor_pool,gaming_console,default_rate
+1,2,true,false,false,false,5000
+2,4,false,true,true,false,6500
+`
+
+func TestImportRooms(t *testing.T) {
+	server, store := newTestServer(t)
+	auth := createTestAPIKey(t, store, "onboarding")
+
+	// Imports write, so they require an API key.
+	requireProblem(t, serveCSV(server, "/v1/import/rooms", nil, roomsCSV), http.StatusUnauthorized, "unauthorized")
+
+	// A dry run reports what would be written without writing it.
+	report := readImportReport(t, serveCSV(server, "/v1/import/rooms?dry_run=true", auth, roomsCSV), http.StatusOK)
+	require.Equal(t, ImportReport{DryRun: true, Rows: 2, Created: 2, Errors: []ImportRowError{}}, report)
+	count, err := store.GetRoomCount(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	report = readImportReport(t, serveCSV(server, "/v1/import/rooms", auth, roomsCSV), http.StatusOK)
+	require.Equal(t, ImportReport{Committed: true, Rows: 2, Created: 2, Errors: []ImportRowError{}}, report)
+	room, err := store.GetRoom(context.Background(), 2)
+	require.NoError(t, err)
+	require.Equal(t, int32(4), room.MaxGuests)
+	require.True(t, room.IndoorPool)
+	require.Equal(t, int32(6500), room.DefaultRate)
+
+	// Importing the same rooms again leaves them unchanged, and changed rooms are replaced.
+	report = readImportReport(t, serveCSV(server, "/v1/import/rooms", auth, roomsCSV), http.StatusOK)
+	require.Equal(t, ImportReport{Committed: true, Rows: 2, Unchanged: 2, Errors: []ImportRowError{}}, report)
+	report = readImportReport(t, serveCSV(server, "/v1/import/rooms", auth, strings.ReplaceAll(roomsCSV, "6500", "7000")), http.StatusOK)
+	require.Equal(t, 1, report.Updated)
+	require.Equal(t, 1, report.Unchanged)
+
+	// Imports are recorded in the audit log with the key that made them.
+	history := getHistory(t, server, "/v1/rooms/2/history", auth)
+	require.Equal(t, "onboarding", history.Events[0].Actor)
+}
+
+func TestImportRoomsInvalid(t *testing.T) {
+	server, store := newTestServer(t)
+	auth := createTestAPIKey(t, store, "onboarding")
+
+	body := `room_id,max_guests,balcony,fridge,indoor_pool,gaming_console,default_rate
+1,2,true,false,false,false,5000
+0,40,yes,false,false,false,-1
+3,2,true,false
+1,3,true,false,false,false,5000
+`
+	report := readImportReport(t, serveCSV(server, "/v1/import/rooms", auth, body), http.StatusUnprocessableEntity)
+	require.False(t, report.Committed)
+	require.Equal(t, 4, report.Rows)
+	require.Equal(t, []ImportRowError{
+		{Line: 3, Errors: []ImportFieldError{
+			{Column: "room_id", Message: "must be between 1 and 2147483647"},
+			{Column: "max_guests", Message: "must be between 1 and 16"},
+			{Column: "balcony", Message: `"yes" is not a boolean`},
+			{Column: "default_rate", Message: "must be between 1 and 2147483647"},
+		}},
+		{Line: 4, Errors: []ImportFieldError{{Message: "expected 7 fields, got 4"}}},
+		{Line: 5, Errors: []ImportFieldError{{Column: "room_id", Message: "room 1 is already on line 2"}}},
+	}, report.Errors)
+
+	// Nothing is written when a row is invalid, including the valid rows.
+	count, err := store.GetRoomCount(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	// Files that cannot be read at all are rejected.
+	for name, body := range map[string]string{
+		"empty":  "",
+		"header": "id,guests\n1,2\n",
+		"quotes": "room_id,max_guests,balcony,fridge,indoor_pool,gaming_console,default_rate\n\"1,2\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			requireProblem(t, serveCSV(server, "/v1/import/rooms", auth, body), http.StatusBadRequest, "invalid_request")
+		})
+	}
+	requireProblem(t, serveCSV(server, "/v1/import/rooms?dry_run=maybe", auth, roomsCSV), http.StatusBadRequest, "invalid_request")
+}
+
+func TestImportCalendar(t *testing.T) {
+	server, store := newTestServer(t)
+	auth := createTestAPIKey(t, store, "onboarding")
+	createTestRoom(t, store, 1, 5000)
+
+	// The first night replaces the one of the test room, the second is new.
+	body := "room_id,date,is_available,night_rate\n1,2024-06-28,false,5500\n1,2024-06-29,true,6000\n"
+	report := readImportReport(t, serveCSV(server, "/v1/import/calendar?dry_run=true", auth, body), http.StatusOK)
+	require.Equal(t, ImportReport{DryRun: true, Rows: 2, Created: 1, Updated: 1, Errors: []ImportRowError{}}, report)
+	_, err := store.GetRoomAvailabilityByDate(context.Background(), db.GetRoomAvailabilityByDateParams{RoomID: 1, Date: testNight(1)})
+	require.Error(t, err)
+
+	report = readImportReport(t, serveCSV(server, "/v1/import/calendar", auth, body), http.StatusOK)
+	require.True(t, report.Committed)
+	night, err := store.GetRoomAvailabilityByDate(context.Background(), db.GetRoomAvailabilityByDateParams{RoomID: 1, Date: testNight(0)})
+	require.NoError(t, err)
+	require.False(t, night.IsAvailable)
+	require.Equal(t, int32(5500), night.NightRate)
+
+	// The metrics reflect the import.
+	recorder := serve(server, http.MethodGet, "/1", nil)
+	var data RoomData
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &data))
+	require.Len(t, data.RatePerNight, 2)
+}
+
+func TestImportCalendarInvalid(t *testing.T) {
+	server, store := newTestServer(t)
+	auth := createTestAPIKey(t, store, "onboarding")
+	createTestRoom(t, store, 1)
+
+	body := `room_id,date,is_available,night_rate
+1,2024-06-27,true,5000
+2,2024-06-28,true,5000
+1,2026-07-01,true,5000
+1,28/06/2024,maybe,0
+1,2024-06-29,true,5000
+1,2024-06-29,false,5000
+`
+	report := readImportReport(t, serveCSV(server, "/v1/import/calendar?dry_run=true", auth, body), http.StatusUnprocessableEntity)
+	require.True(t, report.DryRun)
+	require.Equal(t, 6, report.Rows)
+	require.Equal(t, []ImportRowError{
+		{Line: 2, Errors: []ImportFieldError{{Column: "date", Message: "must not be in the past"}}},
+		{Line: 3, Errors: []ImportFieldError{{Column: "room_id", Message: "room 2 does not exist"}}},
+		{Line: 4, Errors: []ImportFieldError{{Column: "date", Message: "must be at most 730 days from today"}}},
+		{Line: 5, Errors: []ImportFieldError{
+			{Column: "date", Message: `"28/06/2024" is not a date in the YYYY-MM-DD format`},
+			{Column: "is_available", Message: `"maybe" is not a boolean`},
+			{Column: "night_rate", Message: "must be between 1 and 2147483647"},
+		}},
+		{Line: 7, Errors: []ImportFieldError{{Column: "date", Message: "the night is already on line 6"}}},
+	}, report.Errors)
+
+	count, err := store.GetDateCount(context.Background(), 1)
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
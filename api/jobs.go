@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Job is a periodic background task. Replicas elect the one running it with a PostgreSQL advisory lock,
+// so that every run happens on a single replica.
+type Job struct {
+	// Name identifies the job in logs and metrics.
+	Name string
+	// LockID is the key of the advisory lock held while the job runs.
+	LockID int64
+	// Interval is the time between two runs.
+	Interval time.Duration
+	// Run performs one run of the job.
+	Run func(ctx context.Context) error
+	// CompletionLevel is the level of the log of completed runs, Info by default.
+	// Jobs running every few seconds log them at Debug.
+	CompletionLevel slog.Level
+}
+
+// RunJob runs the job right away and then at every interval until the context is canceled.
+func (store *Store) RunJob(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		start := time.Now()
+		ran, err := store.RunJobOnce(ctx, job)
+		switch {
+		case err != nil:
+			slog.Error("job failed", slog.String("job", job.Name), slog.Any("error", err))
+		case ran:
+			slog.Log(ctx, job.CompletionLevel, "job completed", slog.String("job", job.Name), slog.Duration("duration", time.Since(start)))
+		default:
+			slog.Debug("job skipped, another replica holds its lock", slog.String("job", job.Name))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunJobOnce runs the job unless another replica is already running it, and reports whether it ran.
+func (store *Store) RunJobOnce(ctx context.Context, job Job) (ran bool, err error) {
+	// Writes made by the job are audited as made by the job.
+	ctx = WithActor(ctx, "job:"+job.Name)
+	ran, err = store.withAdvisoryLock(ctx, job.LockID, job.Run)
+
+	outcome := "ok"
+	switch {
+	case err != nil:
+		outcome = "error"
+	case !ran:
+		outcome = "skipped"
+	default:
+		jobLastSuccess.WithLabelValues(job.Name).SetToCurrentTime()
+	}
+	jobRunsTotal.WithLabelValues(job.Name, outcome).Inc()
+	return ran, err
+}
+
+// withAdvisoryLock calls fn while holding the session advisory lock with the given key.
+// It returns without calling fn if the lock is held by another session.
+func (store *Store) withAdvisoryLock(ctx context.Context, lockID int64, fn func(ctx context.Context) error) (bool, error) {
+	if store.conn == nil {
+		return true, fn(ctx)
+	}
+
+	conn, err := store.conn.Acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Release()
+
+	var acquired bool
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", lockID).Scan(&acquired); err != nil {
+		return false, err
+	}
+	if !acquired {
+		return false, nil
+	}
+	defer func() {
+		// A connection that may still hold the lock must not go back to the pool; closing it releases the lock.
+		if _, err := conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", lockID); err != nil {
+			conn.Conn().Close(context.Background())
+		}
+	}()
+
+	return true, fn(ctx)
+}
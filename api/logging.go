@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// requestIDHeader carries the correlation ID of a request in both directions.
+const requestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the size of a client supplied request ID.
+const maxRequestIDLength = 128
+
+// loggerKey is the context key under which the request scoped logger is stored.
+type loggerKey struct{}
+
+// requestIDKey is the gin context key under which the request ID is stored.
+const requestIDKey = "request_id"
+
+// requestIDContextKey is the context key under which the request ID is stored, for code that only has a context.Context.
+type requestIDContextKey struct{}
+
+// requestIDMiddleware propagates the incoming `X-Request-ID` header or generates a new one,
+// echoes it in the response and attaches a logger carrying it to the request context.
+func requestIDMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestID := ctx.GetHeader(requestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		ctx.Set(requestIDKey, requestID)
+		ctx.Header(requestIDHeader, requestID)
+
+		logger := slog.Default().With(slog.String("request_id", requestID))
+		if spanContext := trace.SpanContextFromContext(ctx.Request.Context()); spanContext.HasTraceID() {
+			logger = logger.With(slog.String("trace_id", spanContext.TraceID().String()))
+		}
+		requestCtx := context.WithValue(ctx.Request.Context(), loggerKey{}, logger)
+		ctx.Request = ctx.Request.WithContext(context.WithValue(requestCtx, requestIDContextKey{}, requestID))
+
+		ctx.Next()
+	}
+}
+
+// validRequestID reports whether a client supplied request ID is safe to reuse.
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range requestID {
+		if c < '!' || c > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// requestID returns the ID of the current request.
+func requestID(ctx *gin.Context) string {
+	return ctx.GetString(requestIDKey)
+}
+
+// requestIDFromContext returns the ID of the request ctx belongs to, or "" outside of a request.
+func requestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
+
+// loggerFromContext returns the request scoped logger, or the default logger outside of a request.
+func loggerFromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// loggingMiddleware writes one structured access log line per request.
+func loggingMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+
+		loggerFromContext(ctx).LogAttrs(ctx, level, "request completed",
+			slog.String("method", ctx.Request.Method),
+			slog.String("path", ctx.Request.URL.Path),
+			slog.String("route", ctx.FullPath()),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("client_ip", ctx.ClientIP()),
+			slog.Int("size", ctx.Writer.Size()),
+		)
+	}
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestIDPropagation(t *testing.T) {
+	var logs bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+	defer slog.SetDefault(defaultLogger)
+
+	gin.SetMode(gin.TestMode)
+	server := NewServer(Store{})
+
+	request := httptest.NewRequest(http.MethodGet, "/abc", nil)
+	request.Header.Set(requestIDHeader, "client-supplied-id")
+	recorder := httptest.NewRecorder()
+	server.Router().ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Equal(t, "client-supplied-id", recorder.Header().Get(requestIDHeader))
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	require.Equal(t, "client-supplied-id", body["request_id"])
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &line))
+	require.Equal(t, "client-supplied-id", line["request_id"])
+	require.Equal(t, "/:room_id", line["route"])
+	require.EqualValues(t, http.StatusBadRequest, line["status"])
+}
+
+func TestRequestIDGenerated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := NewServer(Store{})
+
+	request := httptest.NewRequest(http.MethodGet, "/abc", nil)
+	request.Header.Set(requestIDHeader, "contains spaces")
+	recorder := httptest.NewRecorder()
+	server.Router().ServeHTTP(recorder, request)
+
+	generated := recorder.Header().Get(requestIDHeader)
+	require.NotEmpty(t, generated)
+	require.NotEqual(t, "contains spaces", generated)
+}
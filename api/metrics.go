@@ -0,0 +1,288 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// metricsNamespace prefixes every metric exposed by the service.
+// Metric names and labels are part of the public contract documented in the README; do not rename them.
+const metricsNamespace = "airbnb"
+
+var (
+	// metricsRegistry holds every collector served on `/metrics`.
+	metricsRegistry = prometheus.NewRegistry()
+
+	httpRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Total number of HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency in seconds by method, route and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Latency of sqlc queries in seconds by query name and outcome.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"query", "outcome"})
+
+	dbTxRetriesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "db",
+		Name:      "tx_retries_total",
+		Help:      "Total number of transactions run again after a serialization failure or deadlock, by PostgreSQL error code.",
+	}, []string{"code"})
+
+	jobRunsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "job",
+		Name:      "runs_total",
+		Help:      "Total number of background job runs by job and outcome.",
+	}, []string{"job", "outcome"})
+
+	jobLastSuccess = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "job",
+		Name:      "last_success_timestamp_seconds",
+		Help:      "Unix time of the last successful run of a background job on this replica.",
+	}, []string{"job"})
+
+	outboxPublishTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "outbox",
+		Name:      "publish_total",
+		Help:      "Total number of outbox events handed to a sink by sink and outcome (published or failed).",
+	}, []string{"sink", "outcome"})
+
+	eventStreams = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "event",
+		Name:      "streams",
+		Help:      "Number of server-sent event streams open on this replica.",
+	})
+
+	webhookDeliveriesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "webhook",
+		Name:      "deliveries_total",
+		Help:      "Total number of webhook delivery attempts by event type and outcome (delivered, retried or failed).",
+	}, []string{"event_type", "outcome"})
+)
+
+func init() {
+	metricsRegistry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestsTotal,
+		httpRequestDuration,
+		dbQueryDuration,
+		dbTxRetriesTotal,
+		jobRunsTotal,
+		jobLastSuccess,
+		outboxPublishTotal,
+		eventStreams,
+		webhookDeliveriesTotal,
+	)
+}
+
+// metricsHandler serves the Prometheus exposition format for all registered collectors.
+func metricsHandler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{}))
+}
+
+// metricsMiddleware records the request count and latency of every request handled by the router.
+func metricsMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		// Use the route template rather than the raw path to keep label cardinality bounded.
+		route := ctx.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(ctx.Writer.Status())
+
+		httpRequestsTotal.WithLabelValues(ctx.Request.Method, route, status).Inc()
+		httpRequestDuration.WithLabelValues(ctx.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// registerPoolMetrics exposes the statistics of the given connection pool.
+// Registering a new pool replaces the collector of any previously registered one.
+func registerPoolMetrics(pool *pgxpool.Pool) {
+	collector := &poolCollector{pool: pool}
+	err := metricsRegistry.Register(collector)
+
+	var registered prometheus.AlreadyRegisteredError
+	if errors.As(err, &registered) {
+		metricsRegistry.Unregister(registered.ExistingCollector)
+		metricsRegistry.MustRegister(collector)
+	}
+}
+
+var (
+	poolAcquiredConnsDesc = prometheus.NewDesc(metricsNamespace+"_db_pool_acquired_connections",
+		"Number of connections currently acquired from the pool.", nil, nil)
+	poolIdleConnsDesc = prometheus.NewDesc(metricsNamespace+"_db_pool_idle_connections",
+		"Number of idle connections in the pool.", nil, nil)
+	poolTotalConnsDesc = prometheus.NewDesc(metricsNamespace+"_db_pool_total_connections",
+		"Total number of connections currently in the pool.", nil, nil)
+	poolMaxConnsDesc = prometheus.NewDesc(metricsNamespace+"_db_pool_max_connections",
+		"Maximum size of the pool.", nil, nil)
+	poolAcquiresDesc = prometheus.NewDesc(metricsNamespace+"_db_pool_acquires_total",
+		"Cumulative number of successful connection acquires.", nil, nil)
+	poolEmptyAcquiresDesc = prometheus.NewDesc(metricsNamespace+"_db_pool_empty_acquires_total",
+		"Cumulative number of acquires that had to wait for a connection.", nil, nil)
+	poolAcquireWaitDesc = prometheus.NewDesc(metricsNamespace+"_db_pool_acquire_wait_seconds_total",
+		"Cumulative time spent waiting to acquire a connection, in seconds.", nil, nil)
+)
+
+// poolCollector reports pgxpool statistics at scrape time.
+type poolCollector struct {
+	pool *pgxpool.Pool
+}
+
+// Describe implements prometheus.Collector.
+func (collector *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolAcquiredConnsDesc
+	ch <- poolIdleConnsDesc
+	ch <- poolTotalConnsDesc
+	ch <- poolMaxConnsDesc
+	ch <- poolAcquiresDesc
+	ch <- poolEmptyAcquiresDesc
+	ch <- poolAcquireWaitDesc
+}
+
+// Collect implements prometheus.Collector.
+func (collector *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := collector.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(poolAcquiredConnsDesc, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(poolIdleConnsDesc, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(poolTotalConnsDesc, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(poolMaxConnsDesc, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(poolAcquiresDesc, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolEmptyAcquiresDesc, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolAcquireWaitDesc, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+}
+
+// queryNamePattern matches the `-- name: <Name> :<kind>` header sqlc puts on every generated query.
+var queryNamePattern = regexp.MustCompile(`^-- name: (\w+) :\w+`)
+
+// queryName returns the sqlc query name of the given SQL, or "unknown" for ad-hoc statements.
+func queryName(sql string) string {
+	match := queryNamePattern.FindStringSubmatch(sql)
+	if match == nil {
+		return "unknown"
+	}
+	return match[1]
+}
+
+// observeQuery records the latency of a single query execution.
+func observeQuery(sql string, start time.Time, err error) {
+	outcome := "ok"
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		outcome = "error"
+	}
+	dbQueryDuration.WithLabelValues(queryName(sql), outcome).Observe(time.Since(start).Seconds())
+}
+
+// instrumentedDBTX wraps a db.DBTX and records the latency of every query sent through it.
+type instrumentedDBTX struct {
+	db db.DBTX
+}
+
+// newInstrumentedDBTX wraps the given db.DBTX with query latency metrics.
+func newInstrumentedDBTX(dbtx db.DBTX) db.DBTX {
+	return &instrumentedDBTX{db: dbtx}
+}
+
+// Exec implements db.DBTX.
+func (dbtx *instrumentedDBTX) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	start := time.Now()
+	tag, err := dbtx.db.Exec(ctx, sql, args...)
+	observeQuery(sql, start, err)
+	return tag, err
+}
+
+// Query implements db.DBTX. The latency is recorded once the rows are closed.
+func (dbtx *instrumentedDBTX) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	start := time.Now()
+	rows, err := dbtx.db.Query(ctx, sql, args...)
+	if err != nil {
+		observeQuery(sql, start, err)
+		return rows, err
+	}
+	return &instrumentedRows{Rows: rows, sql: sql, start: start}, nil
+}
+
+// QueryRow implements db.DBTX. The latency is recorded once the row is scanned.
+func (dbtx *instrumentedDBTX) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	start := time.Now()
+	return &instrumentedRow{row: dbtx.db.QueryRow(ctx, sql, args...), sql: sql, start: start}
+}
+
+// CopyFrom implements db.DBTX. Bulk copies are labelled `CopyFrom:<table>`.
+func (dbtx *instrumentedDBTX) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	start := time.Now()
+	count, err := dbtx.db.CopyFrom(ctx, tableName, columnNames, rowSrc)
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+	dbQueryDuration.WithLabelValues("CopyFrom:"+tableName.Sanitize(), outcome).Observe(time.Since(start).Seconds())
+	return count, err
+}
+
+// instrumentedRows records the query latency when the result set is closed.
+type instrumentedRows struct {
+	pgx.Rows
+	sql   string
+	start time.Time
+	once  sync.Once
+}
+
+// Close implements pgx.Rows.
+func (rows *instrumentedRows) Close() {
+	rows.Rows.Close()
+	rows.once.Do(func() {
+		observeQuery(rows.sql, rows.start, rows.Rows.Err())
+	})
+}
+
+// instrumentedRow records the query latency when the row is scanned.
+type instrumentedRow struct {
+	row   pgx.Row
+	sql   string
+	start time.Time
+}
+
+// Scan implements pgx.Row.
+func (row *instrumentedRow) Scan(dest ...any) error {
+	err := row.row.Scan(dest...)
+	observeQuery(row.sql, row.start, err)
+	return err
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryName(t *testing.T) {
+	require.Equal(t, "GetRoom", queryName("-- name: GetRoom :one\nSELECT * FROM room"))
+	require.Equal(t, "ListRooms", queryName("-- name: ListRooms :many\nSELECT * FROM room"))
+	require.Equal(t, "unknown", queryName("SELECT 1"))
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := NewServer(Store{})
+	counter := httpRequestsTotal.WithLabelValues(http.MethodGet, "/:room_id", "400")
+	before := testutil.ToFloat64(counter)
+
+	// An invalid room ID is rejected before touching the database.
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/abc", nil)
+	server.Router().ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Equal(t, before+1, testutil.ToFloat64(counter))
+
+	recorder = httptest.NewRecorder()
+	request = httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	server.Router().ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	body := recorder.Body.String()
+	require.Contains(t, body, `airbnb_http_requests_total{method="GET",route="/:room_id",status="400"}`)
+	require.Contains(t, body, `airbnb_http_request_duration_seconds_bucket{method="GET",route="/:room_id",status="400"`)
+}
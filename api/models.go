@@ -1,10 +1,17 @@
 package api
 
 import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	db "github.com/vivek-344/airbnb-api/db/sqlc"
 )
 
+// dateLayout is the format of dates in API requests and responses.
+const dateLayout = "2006-01-02"
+
 // RoomData holds detailed information about a room and its availability for API responses.
 type RoomData struct {
 	RoomID              int32                             `json:"room_id"`
@@ -12,6 +19,7 @@ type RoomData struct {
 	MaxGuests           int32                             `json:"max_guests"`
 	AvailableDates      []string                          `json:"available_dates"`
 	OccupancyPercentage []db.GetAvailabilityPercentageRow `json:"occupancy_percentage"`
+	MonthlyStats        []MonthStats                      `json:"monthly_stats"`
 	AverageRate         float64                           `json:"average_rate"`
 	HighestRate         int32                             `json:"highest_rate"`
 	LowestRate          int32                             `json:"lowest_rate"`
@@ -21,9 +29,344 @@ type RoomData struct {
 	GamingConsole       bool                              `json:"gaming_console"`
 }
 
+// MonthStats holds the occupancy and rates of the nights of a room in a month of its calendar.
+type MonthStats struct {
+	Month               string  `json:"month"`
+	Nights              int32   `json:"nights"`
+	BookedNights        int32   `json:"booked_nights"`
+	OccupancyPercentage float64 `json:"occupancy_percentage"`
+	Revenue             int64   `json:"revenue"`
+	AverageRate         float64 `json:"average_rate"`
+	LowestRate          int32   `json:"lowest_rate"`
+	HighestRate         int32   `json:"highest_rate"`
+}
+
+// newMonthStats converts the stored statistics of a month to their API representation.
+func newMonthStats(stats db.RoomMonthStat) MonthStats {
+	monthStats := MonthStats{
+		Month:        stats.Month.Time.Format("2006-01"),
+		Nights:       stats.Nights,
+		BookedNights: stats.BookedNights,
+		Revenue:      stats.Revenue,
+		LowestRate:   stats.LowestRate,
+		HighestRate:  stats.HighestRate,
+	}
+	if stats.Nights > 0 {
+		monthStats.OccupancyPercentage = round2(float64(stats.BookedNights) * 100 / float64(stats.Nights))
+		monthStats.AverageRate = round2(float64(stats.RateSum) / float64(stats.Nights))
+	}
+	return monthStats
+}
+
 // DateData holds information about the room's availability and the nightly rate for a specific date.
 type DateData struct {
 	Date        pgtype.Date `json:"date"`
 	IsAvailable bool        `json:"is_available"`
 	NightRate   int32       `json:"night_rate"`
 }
+
+// Room is the representation of a room returned and updated by the `/v1/rooms` endpoints.
+// Its version is also sent as the `ETag` of the response.
+type Room struct {
+	RoomID        int32     `json:"room_id"`
+	MaxGuests     int32     `json:"max_guests"`
+	Balcony       bool      `json:"balcony"`
+	Fridge        bool      `json:"fridge"`
+	IndoorPool    bool      `json:"indoor_pool"`
+	GamingConsole bool      `json:"gaming_console"`
+	DefaultRate   int32     `json:"default_rate"`
+	Version       int32     `json:"version"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// newRoom converts a room row into its API representation.
+func newRoom(room db.Room) Room {
+	return Room{
+		RoomID:        room.RoomID,
+		MaxGuests:     room.MaxGuests,
+		Balcony:       room.Balcony,
+		Fridge:        room.Fridge,
+		IndoorPool:    room.IndoorPool,
+		GamingConsole: room.GamingConsole,
+		DefaultRate:   room.DefaultRate,
+		Version:       room.Version,
+		UpdatedAt:     room.UpdatedAt,
+	}
+}
+
+// Night is the representation of a night in the calendar of a room. Its version is also sent as the `ETag` of the response.
+type Night struct {
+	RoomID      int32       `json:"room_id"`
+	Date        pgtype.Date `json:"date"`
+	IsAvailable bool        `json:"is_available"`
+	NightRate   int32       `json:"night_rate"`
+	Version     int32       `json:"version"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	BookedAt    *time.Time  `json:"booked_at"` // Null while the night is available.
+}
+
+// newNight converts an availability row into its API representation.
+func newNight(night db.RoomAvailability) Night {
+	response := Night{
+		RoomID:      night.RoomID,
+		Date:        night.Date,
+		IsAvailable: night.IsAvailable,
+		NightRate:   night.NightRate,
+		Version:     night.Version,
+		UpdatedAt:   night.UpdatedAt,
+	}
+	if night.BookedAt.Valid {
+		response.BookedAt = &night.BookedAt.Time
+	}
+	return response
+}
+
+// AuditEvent is an entry of the audit log returned by the history endpoint.
+// Before and after hold the fields of the row that changed, and are null when the row did not exist.
+type AuditEvent struct {
+	ID         int64           `json:"id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Actor      string          `json:"actor"`
+	Action     string          `json:"action"`
+	RoomID     int32           `json:"room_id"`
+	Date       *string         `json:"date,omitempty"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	RequestID  string          `json:"request_id,omitempty"`
+}
+
+// newAuditEvent converts an audit log row into its API representation.
+func newAuditEvent(event db.AuditEvent) AuditEvent {
+	result := AuditEvent{
+		ID:         event.ID,
+		OccurredAt: event.OccurredAt,
+		Actor:      event.Actor,
+		Action:     event.Action,
+		RoomID:     event.RoomID.Int32,
+		Before:     event.Before,
+		After:      event.After,
+		RequestID:  event.RequestID.String,
+	}
+	if event.Date.Valid {
+		date := event.Date.Time.Format(dateLayout)
+		result.Date = &date
+	}
+	return result
+}
+
+// RoomHistory is the response of the history endpoint, with the most recent events first.
+type RoomHistory struct {
+	RoomID int32        `json:"room_id"`
+	Events []AuditEvent `json:"events"`
+}
+
+// PeriodStats holds the occupancy and rates of the nights between two dates, inclusive,
+// counting both current and archived nights.
+type PeriodStats struct {
+	From                string  `json:"from"`
+	To                  string  `json:"to"`
+	Nights              int64   `json:"nights"`
+	BookedNights        int64   `json:"booked_nights"`
+	OccupancyPercentage float64 `json:"occupancy_percentage"`
+	Revenue             int64   `json:"revenue"`
+	AverageRate         float64 `json:"average_rate"`
+	LowestRate          int32   `json:"lowest_rate"`
+	HighestRate         int32   `json:"highest_rate"`
+}
+
+// PortfolioPeriodStats holds the statistics of a period over all rooms.
+type PortfolioPeriodStats struct {
+	Rooms int64 `json:"rooms"`
+	PeriodStats
+}
+
+// PeriodChange holds the change of the statistics of a period from the same period last year.
+// Changes are null when last year has no nights, or no revenue for the revenue change.
+type PeriodChange struct {
+	OccupancyPoints       *float64 `json:"occupancy_points"`
+	AverageRatePercentage *float64 `json:"average_rate_percentage"`
+	RevenuePercentage     *float64 `json:"revenue_percentage"`
+}
+
+// RoomComparison compares a period of a room to the same period last year.
+type RoomComparison struct {
+	RoomID       int32        `json:"room_id"`
+	Current      PeriodStats  `json:"current"`
+	PreviousYear PeriodStats  `json:"previous_year"`
+	Change       PeriodChange `json:"change"`
+}
+
+// PortfolioComparison compares a period of all rooms to the same period last year.
+type PortfolioComparison struct {
+	Current      PortfolioPeriodStats `json:"current"`
+	PreviousYear PortfolioPeriodStats `json:"previous_year"`
+	Change       PeriodChange         `json:"change"`
+}
+
+// OccupancyForecast holds the occupancy of the nights of a month on the books and projected from the booking pace.
+type OccupancyForecast struct {
+	Month                       string  `json:"month"`
+	Nights                      int64   `json:"nights"`
+	BookedNights                int64   `json:"booked_nights"`
+	OccupancyPercentage         float64 `json:"occupancy_percentage"`
+	ExpectedPickup              float64 `json:"expected_pickup"`
+	ForecastOccupancyPercentage float64 `json:"forecast_occupancy_percentage"`
+	HistoryNights               int64   `json:"history_nights"`
+}
+
+// RoomForecast is the response of the forecast endpoint of a room.
+type RoomForecast struct {
+	RoomID int32 `json:"room_id"`
+	OccupancyForecast
+}
+
+// ImportReport is the response of a CSV import. Created and updated count the rows written, or that would
+// be written by a dry run, and unchanged the rows left as they were. Nothing is committed when a row is invalid.
+type ImportReport struct {
+	DryRun    bool             `json:"dry_run"`
+	Committed bool             `json:"committed"`
+	Rows      int              `json:"rows"`
+	Created   int              `json:"created"`
+	Updated   int              `json:"updated"`
+	Unchanged int              `json:"unchanged"`
+	Errors    []ImportRowError `json:"errors"`
+}
+
+// count counts a written row as created or updated from its version.
+func (report *ImportReport) count(version int32) {
+	if version == 1 {
+		report.Created++
+	} else {
+		report.Updated++
+	}
+}
+
+// ImportRowError holds the errors of a row of an imported file. Lines are numbered from 1, the header.
+type ImportRowError struct {
+	Line   int                `json:"line"`
+	Errors []ImportFieldError `json:"errors"`
+}
+
+// ImportFieldError is an invalid value of a row. The column is empty when the row itself is malformed.
+type ImportFieldError struct {
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// WebhookSubscription is the representation of a webhook subscription. The secret signing its deliveries
+// is only returned when the subscription is created.
+type WebhookSubscription struct {
+	ID         int64     `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Secret     string    `json:"secret,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// newWebhookSubscription converts a subscription row into its API representation, without its secret.
+func newWebhookSubscription(subscription db.WebhookSubscription) WebhookSubscription {
+	return WebhookSubscription{
+		ID:         subscription.ID,
+		URL:        subscription.Url,
+		EventTypes: subscription.EventTypes,
+		CreatedAt:  subscription.CreatedAt,
+	}
+}
+
+// RoomEvent is the payload of an event about a room. Before is null for a created room,
+// and after for a deleted one.
+type RoomEvent struct {
+	ID         uuid.UUID `json:"id"`
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	RoomID     int32     `json:"room_id"`
+	Before     *Room     `json:"before"`
+	After      *Room     `json:"after"`
+}
+
+// NightEvent is the payload of an event about a night. Before is null for a created night.
+type NightEvent struct {
+	ID         uuid.UUID   `json:"id"`
+	Type       string      `json:"type"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	RoomID     int32       `json:"room_id"`
+	Date       pgtype.Date `json:"date"`
+	Before     *Night      `json:"before"`
+	After      Night       `json:"after"`
+}
+
+// CalendarEvent is the payload of an event about many nights of a room written at once, by one of the
+// calendar-wide actions of the audit log. Dates are the nights written, in order.
+type CalendarEvent struct {
+	ID         uuid.UUID     `json:"id"`
+	Type       string        `json:"type"`
+	OccurredAt time.Time     `json:"occurred_at"`
+	RoomID     int32         `json:"room_id"`
+	Action     string        `json:"action"`
+	Dates      []pgtype.Date `json:"dates"`
+}
+
+// WebhookDelivery is an entry of the delivery log of a subscription. The next attempt is null unless
+// the delivery is pending; the fields of the last attempt are null until it is made.
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	EventID        uuid.UUID       `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	RoomID         int32           `json:"room_id"`
+	Status         string          `json:"status"`
+	Attempts       int32           `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at"`
+	LastAttemptAt  *time.Time      `json:"last_attempt_at"`
+	ResponseStatus *int32          `json:"response_status"`
+	LastError      *string         `json:"last_error"`
+	ReplayOf       *int64          `json:"replay_of"`
+	CreatedAt      time.Time       `json:"created_at"`
+	Payload        json.RawMessage `json:"payload"`
+}
+
+// newWebhookDelivery converts a delivery row into its API representation.
+func newWebhookDelivery(delivery db.WebhookDelivery) WebhookDelivery {
+	response := WebhookDelivery{
+		ID:        delivery.ID,
+		EventID:   delivery.EventID,
+		EventType: delivery.EventType,
+		RoomID:    delivery.RoomID,
+		Status:    delivery.Status,
+		Attempts:  delivery.Attempts,
+		CreatedAt: delivery.CreatedAt,
+		Payload:   delivery.Payload,
+	}
+	if delivery.Status == "pending" {
+		response.NextAttemptAt = &delivery.NextAttemptAt
+	}
+	if delivery.LastAttemptAt.Valid {
+		response.LastAttemptAt = &delivery.LastAttemptAt.Time
+	}
+	if delivery.ResponseStatus.Valid {
+		response.ResponseStatus = &delivery.ResponseStatus.Int32
+	}
+	if delivery.LastError.Valid {
+		response.LastError = &delivery.LastError.String
+	}
+	if delivery.ReplayOf.Valid {
+		response.ReplayOf = &delivery.ReplayOf.Int64
+	}
+	return response
+}
+
+// Quote is the price of a stay in a room, night by night.
+type Quote struct {
+	RoomID      int32        `json:"room_id"`
+	CheckIn     pgtype.Date  `json:"check_in"`
+	CheckOut    pgtype.Date  `json:"check_out"`
+	Guests      int32        `json:"guests"`
+	Nights      []QuoteNight `json:"nights"`
+	Total       int64        `json:"total"`
+	AverageRate float64      `json:"average_rate"`
+}
+
+// QuoteNight is the rate of a night of a quoted stay.
+type QuoteNight struct {
+	Date      pgtype.Date `json:"date"`
+	NightRate int32       `json:"night_rate"`
+}
@@ -0,0 +1,345 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// Types of the events written to the outbox.
+const (
+	eventRoomCreated         = "room.created"
+	eventRoomUpdated         = "room.updated"
+	eventRoomDeleted         = "room.deleted"
+	eventAvailabilityChanged = "availability.changed" // A night was created, booked or released.
+	eventRateChanged         = "rate.changed"         // The rate of a night changed.
+	eventReservationCreated  = "reservation.created"  // An available night was booked.
+	eventCalendarChanged     = "calendar.changed"     // Many nights of a room were written at once.
+)
+
+// outboxLockID is the key of the advisory lock electing the replica that dispatches the outbox.
+// Events are only published in order if a single dispatcher runs at a time.
+const outboxLockID = 7_344_006
+
+// outboxBatchSize is the number of pending events fetched at once by the dispatcher.
+const outboxBatchSize = 100
+
+// emit writes an event about a room to the outbox, in the transaction of the write it describes.
+// The payload holds the ID and type of the event.
+func (q auditedQueries) emit(ctx context.Context, id uuid.UUID, eventType string, roomID int32, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("cannot encode event: %w", err)
+	}
+	_, err = q.Querier.CreateOutboxEvent(ctx, db.CreateOutboxEventParams{
+		RoomID:    roomID,
+		EventID:   id,
+		EventType: eventType,
+		Payload:   data,
+	})
+	return err
+}
+
+// emitRoom writes the event of a write to a room. before or after is nil for a created or deleted room,
+// and an update changing nothing but the version emits nothing.
+func (q auditedQueries) emitRoom(ctx context.Context, roomID int32, before, after any) error {
+	event := RoomEvent{ID: uuid.New(), RoomID: roomID, OccurredAt: time.Now().UTC()}
+	if room, ok := before.(db.Room); ok {
+		state := newRoom(room)
+		event.Before = &state
+	}
+	if room, ok := after.(db.Room); ok {
+		state := newRoom(room)
+		event.After = &state
+		event.OccurredAt = room.UpdatedAt
+	}
+
+	switch {
+	case event.Before == nil:
+		event.Type = eventRoomCreated
+	case event.After == nil:
+		event.Type = eventRoomDeleted
+	default:
+		unchanged := *event.Before
+		unchanged.Version, unchanged.UpdatedAt = event.After.Version, event.After.UpdatedAt
+		if unchanged == *event.After {
+			return nil
+		}
+		event.Type = eventRoomUpdated
+	}
+	return q.emit(ctx, event.ID, event.Type, roomID, event)
+}
+
+// emitNight writes the events of a write to a night. before is nil for a created night.
+// A write changing nothing emits nothing.
+func (q auditedQueries) emitNight(ctx context.Context, before any, after db.RoomAvailability) error {
+	var previous *Night
+	eventTypes := []string{eventAvailabilityChanged}
+	if night, ok := before.(db.RoomAvailability); ok {
+		state := newNight(night)
+		previous = &state
+
+		eventTypes = nil
+		if night.IsAvailable != after.IsAvailable {
+			eventTypes = append(eventTypes, eventAvailabilityChanged)
+		}
+		if night.IsAvailable && !after.IsAvailable {
+			eventTypes = append(eventTypes, eventReservationCreated)
+		}
+		if night.NightRate != after.NightRate {
+			eventTypes = append(eventTypes, eventRateChanged)
+		}
+	}
+
+	for _, eventType := range eventTypes {
+		event := NightEvent{
+			ID:         uuid.New(),
+			Type:       eventType,
+			OccurredAt: after.UpdatedAt,
+			RoomID:     after.RoomID,
+			Date:       after.Date,
+			Before:     previous,
+			After:      newNight(after),
+		}
+		if err := q.emit(ctx, event.ID, eventType, after.RoomID, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// emitCalendar writes the event of a calendar-wide write to the nights of a room on the given dates.
+func (q auditedQueries) emitCalendar(ctx context.Context, action string, roomID int32, dates []pgtype.Date) error {
+	event := CalendarEvent{
+		ID:         uuid.New(),
+		Type:       eventCalendarChanged,
+		OccurredAt: time.Now().UTC(),
+		RoomID:     roomID,
+		Action:     action,
+		Dates:      slices.SortedFunc(slices.Values(dates), func(a, b pgtype.Date) int { return a.Time.Compare(b.Time) }),
+	}
+	return q.emit(ctx, event.ID, event.Type, roomID, event)
+}
+
+// OutboxSink publishes the events of the outbox. An event may be published more than once, e.g. when
+// the dispatcher stops between publishing it and marking it as published, so consumers must deduplicate
+// events by ID.
+type OutboxSink interface {
+	// Name identifies the sink in logs and metrics.
+	Name() string
+	// Publish publishes an event, returning once it is durably handed over.
+	Publish(ctx context.Context, event db.Outbox) error
+}
+
+// OutboxResult summarizes a run of the outbox dispatcher.
+type OutboxResult struct {
+	Published int // Events published to every sink.
+	Failed    int // Events a sink failed to publish.
+	Deferred  int // Events left for the next run, as an earlier event of their room failed.
+}
+
+// OutboxJob returns the job publishing the pending events of the outbox to the sinks, and deleting
+// the events published more than retention ago. A zero retention keeps them forever.
+func (store *Store) OutboxJob(sinks []OutboxSink, retention, interval time.Duration) Job {
+	return Job{
+		Name:     "outbox_dispatch",
+		LockID:   outboxLockID,
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			_, err := store.DispatchOutbox(ctx, sinks)
+			if err != nil || retention <= 0 {
+				return err
+			}
+			_, err = store.DeletePublishedOutboxEvents(ctx, pgtype.Interval{Microseconds: retention.Microseconds(), Valid: true})
+			return err
+		},
+		CompletionLevel: slog.LevelDebug,
+	}
+}
+
+// DispatchOutbox publishes the pending events to every sink in ID order, which is the commit order of the events
+// of a room, and marks them as published once every sink has published them. When a sink fails, the later events
+// of the room are deferred to the next run so that they are not published out of order, while other rooms go on.
+// All failures are returned together.
+func (store *Store) DispatchOutbox(ctx context.Context, sinks []OutboxSink) (OutboxResult, error) {
+	var result OutboxResult
+	var errs []error
+	blocked := map[int32]bool{}
+
+	arg := db.ListPendingOutboxEventsParams{RowLimit: outboxBatchSize}
+	for {
+		events, err := store.ListPendingOutboxEvents(ctx, arg)
+		if err != nil {
+			return result, errors.Join(append(errs, err)...)
+		}
+
+		for _, event := range events {
+			arg.AfterID = event.ID
+			if blocked[event.RoomID] {
+				result.Deferred++
+				continue
+			}
+
+			if err := publishOutboxEvent(ctx, sinks, event); err != nil {
+				if ctx.Err() != nil {
+					return result, errors.Join(append(errs, ctx.Err())...)
+				}
+				blocked[event.RoomID] = true
+				result.Failed++
+				errs = append(errs, fmt.Errorf("event %s of room %d: %w", event.EventID, event.RoomID, err))
+				continue
+			}
+
+			// Marking an event again is a no-op, e.g. when it was published by a run by hand.
+			if _, err := store.MarkOutboxEventPublished(ctx, event.ID); err != nil {
+				return result, errors.Join(append(errs, err)...)
+			}
+			result.Published++
+		}
+
+		if len(events) < int(arg.RowLimit) {
+			if result.Published > 0 {
+				store.events.signal() // Other replicas are notified by PostgreSQL.
+			}
+			return result, errors.Join(errs...)
+		}
+	}
+}
+
+// publishOutboxEvent publishes an event to every sink, stopping at the first failure.
+func publishOutboxEvent(ctx context.Context, sinks []OutboxSink, event db.Outbox) error {
+	for _, sink := range sinks {
+		if err := sink.Publish(ctx, event); err != nil {
+			outboxPublishTotal.WithLabelValues(sink.Name(), "failed").Inc()
+			return fmt.Errorf("sink %s: %w", sink.Name(), err)
+		}
+		outboxPublishTotal.WithLabelValues(sink.Name(), "published").Inc()
+	}
+	return nil
+}
+
+// OpenOutboxSinks returns the sinks with the given names: `webhooks`, which queues the deliveries of the webhook
+// subscriptions, `stdout`, and `file`, which appends to the file at path. The returned function closes them.
+func (store *Store) OpenOutboxSinks(names []string, path string) ([]OutboxSink, func() error, error) {
+	var sinks []OutboxSink
+	var files []*LineSink
+	closeSinks := func() error {
+		var errs []error
+		for _, file := range files {
+			errs = append(errs, file.Close())
+		}
+		return errors.Join(errs...)
+	}
+
+	for _, name := range names {
+		switch strings.TrimSpace(name) {
+		case "":
+		case "webhooks":
+			sinks = append(sinks, store.WebhookSink())
+		case "stdout":
+			sinks = append(sinks, NewLineSink("stdout", os.Stdout))
+		case "file":
+			file, err := OpenFileSink(path)
+			if err != nil {
+				closeSinks()
+				return nil, nil, err
+			}
+			files = append(files, file)
+			sinks = append(sinks, file)
+		default:
+			closeSinks()
+			return nil, nil, fmt.Errorf("unknown outbox sink %q", name)
+		}
+	}
+	return sinks, closeSinks, nil
+}
+
+// webhookSink queues a delivery of every event for each webhook subscription to its type.
+// Publishing an event again queues no second delivery.
+type webhookSink struct {
+	store *Store
+}
+
+// WebhookSink returns the sink queuing the deliveries of the webhook subscriptions.
+func (store *Store) WebhookSink() OutboxSink {
+	return webhookSink{store: store}
+}
+
+func (sink webhookSink) Name() string {
+	return "webhooks"
+}
+
+func (sink webhookSink) Publish(ctx context.Context, event db.Outbox) error {
+	_, err := sink.store.CreateWebhookDeliveries(ctx, db.CreateWebhookDeliveriesParams{
+		EventID:   event.EventID,
+		EventType: event.EventType,
+		RoomID:    event.RoomID,
+		Payload:   event.Payload,
+	})
+	return err
+}
+
+// LineSink writes the payload of every event as a line of JSON.
+type LineSink struct {
+	name string
+	mu   sync.Mutex
+	w    io.Writer
+	file *os.File // Synced after every event when writing to a file.
+}
+
+// NewLineSink returns a sink writing events to w, e.g. os.Stdout.
+func NewLineSink(name string, w io.Writer) *LineSink {
+	return &LineSink{name: name, w: w}
+}
+
+// OpenFileSink returns a sink appending events to the file at path, which is created if needed.
+func OpenFileSink(path string) (*LineSink, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open outbox file: %w", err)
+	}
+	return &LineSink{name: "file", w: file, file: file}, nil
+}
+
+func (sink *LineSink) Name() string {
+	return sink.name
+}
+
+func (sink *LineSink) Publish(ctx context.Context, event db.Outbox) error {
+	var line bytes.Buffer
+	if err := json.Compact(&line, event.Payload); err != nil {
+		return err
+	}
+	line.WriteByte('\n')
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if _, err := sink.w.Write(line.Bytes()); err != nil {
+		return err
+	}
+	if sink.file != nil {
+		return sink.file.Sync()
+	}
+	return nil
+}
+
+// Close closes the file of the sink, if any.
+func (sink *LineSink) Close() error {
+	if sink.file == nil {
+		return nil
+	}
+	return sink.file.Close()
+}
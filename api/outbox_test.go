@@ -0,0 +1,266 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+	"github.com/vivek-344/airbnb-api/util"
+)
+
+// recordingSink records the events it publishes, failing those for which fail returns an error.
+type recordingSink struct {
+	events []db.Outbox
+	fail   func(event db.Outbox) error
+}
+
+func (sink *recordingSink) Name() string {
+	return "recording"
+}
+
+func (sink *recordingSink) Publish(ctx context.Context, event db.Outbox) error {
+	if sink.fail != nil {
+		if err := sink.fail(event); err != nil {
+			return err
+		}
+	}
+	sink.events = append(sink.events, event)
+	return nil
+}
+
+// types returns the types of the published events.
+func (sink *recordingSink) types() []string {
+	types := []string{}
+	for _, event := range sink.events {
+		types = append(types, event.EventType)
+	}
+	return types
+}
+
+func TestOutboxEvents(t *testing.T) {
+	_, store := newTestServer(t)
+	ctx := context.Background()
+
+	_, err := store.CreateRoom(ctx, db.CreateRoomParams{RoomID: 1, MaxGuests: 2, DefaultRate: 5000})
+	require.NoError(t, err)
+	_, err = store.UpdateMaxGuests(ctx, db.UpdateMaxGuestsParams{RoomID: 1, MaxGuests: 3})
+	require.NoError(t, err)
+	_, err = store.UpsertRoom(ctx, db.UpsertRoomParams{RoomID: 1, MaxGuests: 3, DefaultRate: 5000})
+	require.NoError(t, err)
+	_, err = store.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{RoomID: 1, Date: testNight(0), IsAvailable: true, NightRate: 5000})
+	require.NoError(t, err)
+
+	// Events are written in the transaction of the write, so a rolled back write leaves none.
+	err = store.ExecTx(ctx, TxOptions{}, func(queries db.Querier) error {
+		if _, err := queries.CreateRoom(ctx, db.CreateRoomParams{RoomID: 2, MaxGuests: 2, DefaultRate: 5000}); err != nil {
+			return err
+		}
+		return errors.New("rolled back")
+	})
+	require.Error(t, err)
+
+	sink := &recordingSink{}
+	var lines bytes.Buffer
+	result, err := store.DispatchOutbox(ctx, []OutboxSink{sink, NewLineSink("buffer", &lines)})
+	require.NoError(t, err)
+	require.Equal(t, OutboxResult{Published: 3}, result)
+
+	// Upserting the room as it was changes nothing.
+	require.Equal(t, []string{eventRoomCreated, eventRoomUpdated, eventAvailabilityChanged}, sink.types())
+
+	var updated RoomEvent
+	require.NoError(t, json.Unmarshal(sink.events[1].Payload, &updated))
+	require.Equal(t, sink.events[1].EventID, updated.ID)
+	require.Equal(t, int32(2), updated.Before.MaxGuests)
+	require.Equal(t, int32(3), updated.After.MaxGuests)
+
+	// Line sinks write one payload per line.
+	written := strings.Split(strings.TrimSpace(lines.String()), "\n")
+	require.Len(t, written, 3)
+	require.JSONEq(t, string(sink.events[2].Payload), written[2])
+
+	// Published events are not published again.
+	result, err = store.DispatchOutbox(ctx, []OutboxSink{sink})
+	require.NoError(t, err)
+	require.Zero(t, result)
+
+	// Calendar-wide writes emit a single event with the dates of the nights written.
+	_, err = store.DeleteAllAvailabilityForRoom(ctx, 1)
+	require.NoError(t, err)
+	require.NoError(t, store.DeleteRoom(ctx, 1))
+	_, err = store.DispatchOutbox(ctx, []OutboxSink{sink})
+	require.NoError(t, err)
+	require.Equal(t, []string{eventRoomCreated, eventRoomUpdated, eventAvailabilityChanged, eventCalendarChanged, eventRoomDeleted}, sink.types())
+
+	var cleared CalendarEvent
+	require.NoError(t, json.Unmarshal(sink.events[3].Payload, &cleared))
+	require.Equal(t, actionCalendarClear, cleared.Action)
+	require.Equal(t, []pgtype.Date{testNight(0)}, cleared.Dates)
+}
+
+func TestOutboxCalendarEvents(t *testing.T) {
+	_, store := newTestServer(t)
+	ctx := context.Background()
+	createTestRoom(t, store, 1)
+	createTestRoom(t, store, 2)
+	createTestNights(t, store, 1, "2024-06-26", 5000, 5000, 5000)
+	_, err := store.DispatchOutbox(ctx, nil) // Publishes the writes above.
+	require.NoError(t, err)
+
+	// Rolling the horizon archives the past nights of room 1 and adds the missing nights of both rooms.
+	_, err = store.MaintainHorizon(ctx, 2)
+	require.NoError(t, err)
+
+	// Replacing nights of a bulk load updates the existing ones and inserts the others.
+	rows := func(yield func(db.CopyRoomAvailabilityParams, error) bool) {
+		for _, day := range []int{3, 1} {
+			if !yield(db.CopyRoomAvailabilityParams{RoomID: 2, Date: testNight(day), IsAvailable: true, NightRate: 6000}, nil) {
+				return
+			}
+		}
+	}
+	_, err = store.BulkLoadAvailability(ctx, rows, db.BulkOptions{Replace: true})
+	require.NoError(t, err)
+
+	// A rolled back write emits nothing.
+	err = store.ExecTx(ctx, TxOptions{}, func(queries db.Querier) error {
+		if _, err := queries.DeleteAllAvailabilityForRoom(ctx, 2); err != nil {
+			return err
+		}
+		return errors.New("rolled back")
+	})
+	require.Error(t, err)
+
+	sink := &recordingSink{}
+	_, err = store.DispatchOutbox(ctx, []OutboxSink{sink})
+	require.NoError(t, err)
+
+	type calendarWrite struct {
+		RoomID int32
+		Action string
+		Dates  []pgtype.Date
+	}
+	writes := []calendarWrite{}
+	for _, event := range sink.events {
+		require.Equal(t, eventCalendarChanged, event.EventType)
+		var payload CalendarEvent
+		require.NoError(t, json.Unmarshal(event.Payload, &payload))
+		require.Equal(t, event.EventID, payload.ID)
+		writes = append(writes, calendarWrite{payload.RoomID, payload.Action, payload.Dates})
+	}
+	require.Equal(t, []calendarWrite{
+		{1, actionCalendarArchive, []pgtype.Date{testNight(-2), testNight(-1)}},
+		{1, actionCalendarExtend, []pgtype.Date{testNight(1)}},
+		{2, actionCalendarExtend, []pgtype.Date{testNight(0), testNight(1)}},
+		{2, actionCalendarImport, []pgtype.Date{testNight(1), testNight(3)}},
+	}, writes)
+}
+
+func TestSeedRerunWritesNothing(t *testing.T) {
+	_, store := newTestServer(t)
+	ctx := context.Background()
+	options := util.DefaultSeedOptions()
+	options.Rooms = 2
+	options.HorizonDays = 30
+	options.StartDate = testToday
+
+	_, err := util.Seed(ctx, store, options, db.BulkOptions{})
+	require.NoError(t, err)
+	_, err = store.DispatchOutbox(ctx, nil)
+	require.NoError(t, err)
+	room, err := store.GetRoom(ctx, options.FirstRoomID)
+	require.NoError(t, err)
+	nights, err := store.ListRoomNights(ctx, db.ListRoomNightsParams{RoomID: room.RoomID, StartDate: testNight(0), EndDate: testNight(30)})
+	require.NoError(t, err)
+
+	// Seeding the same data again keeps every version and emits no event.
+	_, err = util.Seed(ctx, store, options, db.BulkOptions{})
+	require.NoError(t, err)
+	pending, err := store.ListPendingOutboxEvents(ctx, db.ListPendingOutboxEventsParams{RowLimit: outboxBatchSize})
+	require.NoError(t, err)
+	require.Empty(t, pending)
+
+	reseeded, err := store.GetRoom(ctx, options.FirstRoomID)
+	require.NoError(t, err)
+	require.Equal(t, room, reseeded)
+	reseededNights, err := store.ListRoomNights(ctx, db.ListRoomNightsParams{RoomID: room.RoomID, StartDate: testNight(0), EndDate: testNight(30)})
+	require.NoError(t, err)
+	require.Equal(t, nights, reseededNights)
+}
+
+func TestOutboxOrderPerRoom(t *testing.T) {
+	_, store := newTestServer(t)
+	ctx := context.Background()
+	createTestRoom(t, store, 1)
+	createTestRoom(t, store, 2)
+	_, err := store.DispatchOutbox(ctx, nil) // Publishes the creation of the rooms.
+	require.NoError(t, err)
+
+	for _, night := range []db.CreateRoomAvailabilityParams{
+		{RoomID: 1, Date: testNight(0), IsAvailable: true, NightRate: 5000},
+		{RoomID: 2, Date: testNight(0), IsAvailable: true, NightRate: 5000},
+		{RoomID: 1, Date: testNight(1), IsAvailable: true, NightRate: 5000},
+	} {
+		_, err := store.CreateRoomAvailability(ctx, night)
+		require.NoError(t, err)
+	}
+	events, err := store.ListPendingOutboxEvents(ctx, db.ListPendingOutboxEventsParams{RowLimit: 10})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+
+	// The first event of room 1 fails, so the second one waits for it, while room 2 goes on.
+	failed := events[0].EventID
+	sink := &recordingSink{fail: func(event db.Outbox) error {
+		if event.EventID == failed {
+			return errors.New("unavailable")
+		}
+		return nil
+	}}
+	result, err := store.DispatchOutbox(ctx, []OutboxSink{sink})
+	require.ErrorContains(t, err, "unavailable")
+	require.Equal(t, OutboxResult{Published: 1, Failed: 1, Deferred: 1}, result)
+	require.Equal(t, events[1].EventID, sink.events[0].EventID)
+
+	failed = uuid.Nil
+	result, err = store.DispatchOutbox(ctx, []OutboxSink{sink})
+	require.NoError(t, err)
+	require.Equal(t, OutboxResult{Published: 2}, result)
+	require.Equal(t, events[0].EventID, sink.events[1].EventID)
+	require.Equal(t, events[2].EventID, sink.events[2].EventID)
+}
+
+func TestOpenOutboxSinks(t *testing.T) {
+	_, store := newTestServer(t)
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+
+	sinks, closeSinks, err := store.OpenOutboxSinks([]string{"webhooks", " file", ""}, path)
+	require.NoError(t, err)
+	require.Len(t, sinks, 2)
+	require.Equal(t, "webhooks", sinks[0].Name())
+
+	_, err = store.CreateRoom(ctx, db.CreateRoomParams{RoomID: 1, MaxGuests: 2, DefaultRate: 5000})
+	require.NoError(t, err)
+	_, err = store.DispatchOutbox(ctx, sinks)
+	require.NoError(t, err)
+	require.NoError(t, closeSinks())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var event RoomEvent
+	require.NoError(t, json.Unmarshal(content, &event))
+	require.Equal(t, eventRoomCreated, event.Type)
+	require.Nil(t, event.Before)
+
+	_, _, err = store.OpenOutboxSinks([]string{"kafka"}, path)
+	require.ErrorContains(t, err, `unknown outbox sink "kafka"`)
+}
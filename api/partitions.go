@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// partitionLockID is the key of the advisory lock electing the replica that maintains calendar partitions.
+const partitionLockID = 7_344_003
+
+// PartitionResult summarizes a run of the partition maintenance job.
+type PartitionResult struct {
+	Created  int
+	Archived int32
+}
+
+// PartitionJob returns the job keeping a monthly partition of the calendars monthsAhead months ahead.
+func (store *Store) PartitionJob(monthsAhead int, interval time.Duration) Job {
+	return Job{
+		Name:     "availability_partitions",
+		LockID:   partitionLockID,
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			_, err := store.MaintainPartitions(ctx, monthsAhead)
+			return err
+		},
+	}
+}
+
+// MaintainPartitions creates the monthly partitions of the calendars from the current month to monthsAhead
+// months later, then archives the partitions of the months before. Past nights are archived room by room
+// first, so that the audit log records them; the old partitions are then empty and only detached and dropped.
+// The current month is that of the database's CURRENT_DATE, which also decides which nights are past.
+func (store *Store) MaintainPartitions(ctx context.Context, monthsAhead int) (PartitionResult, error) {
+	today, err := store.GetCurrentDate(ctx)
+	if err != nil {
+		return PartitionResult{}, fmt.Errorf("cannot read the current date: %w", err)
+	}
+	first := time.Date(today.Time.Year(), today.Time.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	var result PartitionResult
+	for i := 0; i <= monthsAhead; i++ {
+		month := first.AddDate(0, i, 0)
+		created, err := store.CreateRoomAvailabilityPartition(ctx, pgtype.Date{Time: month, Valid: true})
+		if err != nil {
+			return result, fmt.Errorf("cannot create the partition of %s: %w", month.Format("2006-01"), err)
+		}
+		if created {
+			result.Created++
+		}
+	}
+
+	if err := store.ArchiveOldRoomAvailabilityData(ctx); err != nil {
+		return result, fmt.Errorf("cannot archive past nights: %w", err)
+	}
+	archived, err := store.ArchiveRoomAvailabilityPartitions(ctx, pgtype.Date{Time: first, Valid: true})
+	if err != nil {
+		return result, fmt.Errorf("cannot archive old partitions: %w", err)
+	}
+	result.Archived = archived
+
+	slog.Info("availability partitions maintained",
+		slog.Int("created", result.Created),
+		slog.Int("archived", int(result.Archived)))
+	return result, nil
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+func TestMaintainPartitions(t *testing.T) {
+	_, store := newTestServer(t)
+	ctx := context.Background()
+	createTestRoom(t, store, 1, 5000, 6000)
+
+	// A partition left over from April, with a night the horizon job has not archived.
+	_, err := store.CreateRoomAvailabilityPartition(ctx, testNight(-70))
+	require.NoError(t, err)
+	_, err = store.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{RoomID: 1, Date: testNight(-70), NightRate: 5000})
+	require.NoError(t, err)
+
+	// Months start from the database's current date, June 2024, whatever the clock of the host.
+	result, err := store.MaintainPartitions(ctx, 2)
+	require.NoError(t, err)
+	require.Equal(t, PartitionResult{Created: 3, Archived: 1}, result)
+
+	partitions, err := store.ListRoomAvailabilityPartitions(ctx)
+	require.NoError(t, err)
+	var names []string
+	for _, partition := range partitions {
+		names = append(names, partition.Name)
+	}
+	require.Equal(t, []string{"room_availability_2024_06", "room_availability_2024_07", "room_availability_2024_08", "room_availability_default"}, names)
+
+	// The past night was archived through the audit log, and the current nights were kept.
+	count, err := store.GetDateCount(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+	events, err := store.ListRoomAuditEvents(ctx, db.ListRoomAuditEventsParams{RoomID: 1, RowLimit: 1})
+	require.NoError(t, err)
+	require.Equal(t, actionCalendarArchive, events[0].Action)
+
+	// The job is idempotent within a month.
+	result, err = store.MaintainPartitions(ctx, 2)
+	require.NoError(t, err)
+	require.Equal(t, PartitionResult{}, result)
+}
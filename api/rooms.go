@@ -0,0 +1,258 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// nightRequest defines the expected URI parameters for a night in the calendar of a room.
+type nightRequest struct {
+	RoomID int32  `binding:"required,min=1" uri:"room_id"`
+	Date   string `binding:"required,datetime=2006-01-02" uri:"date"`
+}
+
+// date returns the night as a DATE.
+func (req nightRequest) date() pgtype.Date {
+	date, _ := time.Parse(dateLayout, req.Date) // Validated when binding.
+	return pgtype.Date{Time: date, Valid: true}
+}
+
+// patchRoomRequest is the body of PATCH /v1/rooms/:room_id. Omitted fields are left unchanged.
+type patchRoomRequest struct {
+	MaxGuests     *int32 `json:"max_guests" binding:"omitempty,min=1"`
+	Balcony       *bool  `json:"balcony"`
+	Fridge        *bool  `json:"fridge"`
+	IndoorPool    *bool  `json:"indoor_pool"`
+	GamingConsole *bool  `json:"gaming_console"`
+	DefaultRate   *int32 `json:"default_rate" binding:"omitempty,min=1"`
+}
+
+// putNightRequest is the body of PUT /v1/rooms/:room_id/availability/:date.
+type putNightRequest struct {
+	IsAvailable *bool `json:"is_available" binding:"required"`
+	NightRate   int32 `json:"night_rate" binding:"required,min=1"`
+}
+
+// nightError translates an error returned while fetching a night, reporting missing rows as an unknown night.
+func nightError(err error, req nightRequest) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return newError(ErrNotFound, "night_not_found", fmt.Sprintf("Room %d has no night on %s.", req.RoomID, req.Date), err)
+	}
+	return translateError(err)
+}
+
+// versionETag returns the strong entity tag of a row at the given version.
+func versionETag(version int32) string {
+	return `"` + strconv.Itoa(int(version)) + `"`
+}
+
+// requireIfMatch returns the `If-Match` header of a write request, which must be present
+// so that clients cannot overwrite changes they have not seen.
+func requireIfMatch(ctx *gin.Context) (string, error) {
+	ifMatch := ctx.GetHeader("If-Match")
+	if strings.TrimSpace(ifMatch) == "" {
+		return "", newError(ErrPreconditionRequired, "precondition_required",
+			"Send the ETag of the resource in `If-Match` to update it.", nil)
+	}
+	return ifMatch, nil
+}
+
+// checkIfMatch fails unless an `If-Match` header value matches the current entity tag of the resource.
+// Strong comparison is used, as mandated by RFC 9110 for `If-Match`, so weak tags never match.
+func checkIfMatch(ifMatch string, etag string) error {
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return nil
+		}
+	}
+	return preconditionFailed()
+}
+
+// preconditionFailed reports a write based on a version of the resource that is no longer current.
+func preconditionFailed() error {
+	return newError(ErrPreconditionFailed, "precondition_failed",
+		"The resource was modified since it was fetched; fetch it again and retry.", nil)
+}
+
+// getRoom returns the details of a room, with its version as `ETag`.
+func (server *Server) getRoom(ctx *gin.Context) {
+	var req getRoomRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, newError(ErrValidation, "invalid_request", err.Error(), err))
+		return
+	}
+
+	room, err := server.store.GetRoom(ctx, req.RoomID)
+	if err != nil {
+		abortWithError(ctx, roomError(err, req.RoomID))
+		return
+	}
+
+	ctx.Header("ETag", versionETag(room.Version))
+	ctx.JSON(200, newRoom(room))
+}
+
+// patchRoom updates the given fields of a room if it is still at the version sent in `If-Match`.
+func (server *Server) patchRoom(ctx *gin.Context) {
+	var req getRoomRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, newError(ErrValidation, "invalid_request", err.Error(), err))
+		return
+	}
+
+	var body patchRoomRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		abortWithError(ctx, newError(ErrValidation, "invalid_request", err.Error(), err))
+		return
+	}
+
+	ifMatch, err := requireIfMatch(ctx)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	room, err := server.store.updateRoom(ctx, db.UpdateRoomParams{
+		RoomID:        req.RoomID,
+		MaxGuests:     optionalInt4(body.MaxGuests),
+		Balcony:       optionalBool(body.Balcony),
+		Fridge:        optionalBool(body.Fridge),
+		IndoorPool:    optionalBool(body.IndoorPool),
+		GamingConsole: optionalBool(body.GamingConsole),
+		DefaultRate:   optionalInt4(body.DefaultRate),
+	}, func(version int32) error { return checkIfMatch(ifMatch, versionETag(version)) })
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.Header("ETag", versionETag(room.Version))
+	ctx.JSON(200, newRoom(room))
+}
+
+// getNight returns a night in the calendar of a room, with its version as `ETag`.
+func (server *Server) getNight(ctx *gin.Context) {
+	var req nightRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, newError(ErrValidation, "invalid_request", err.Error(), err))
+		return
+	}
+
+	night, err := server.store.GetRoomAvailabilityByDate(ctx, db.GetRoomAvailabilityByDateParams{RoomID: req.RoomID, Date: req.date()})
+	if err != nil {
+		abortWithError(ctx, nightError(err, req))
+		return
+	}
+
+	ctx.Header("ETag", versionETag(night.Version))
+	ctx.JSON(200, newNight(night))
+}
+
+// putNight sets the availability and rate of a night if it is still at the version sent in `If-Match`.
+func (server *Server) putNight(ctx *gin.Context) {
+	var req nightRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, newError(ErrValidation, "invalid_request", err.Error(), err))
+		return
+	}
+
+	var body putNightRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		abortWithError(ctx, newError(ErrValidation, "invalid_request", err.Error(), err))
+		return
+	}
+
+	ifMatch, err := requireIfMatch(ctx)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	night, err := server.store.updateNight(ctx, req, *body.IsAvailable, body.NightRate,
+		func(version int32) error { return checkIfMatch(ifMatch, versionETag(version)) })
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.Header("ETag", versionETag(night.Version))
+	ctx.JSON(200, newNight(night))
+}
+
+// updateRoom updates the given fields of a room in a transaction, if check accepts its current version.
+// The version of arg is set to the current one.
+func (store *Store) updateRoom(ctx context.Context, arg db.UpdateRoomParams, check func(version int32) error) (db.Room, error) {
+	var room db.Room
+	err := store.execTx(ctx, func(queries db.Querier) error {
+		current, err := queries.GetRoom(ctx, arg.RoomID)
+		if err != nil {
+			return roomError(err, arg.RoomID)
+		}
+		if err := check(current.Version); err != nil {
+			return err
+		}
+
+		arg.Version = current.Version
+		room, err = queries.UpdateRoom(ctx, arg)
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Another request updated the room since it was read.
+			return preconditionFailed()
+		}
+		return err
+	})
+	return room, err
+}
+
+// updateNight sets the availability and rate of a night in a transaction, if check accepts its current version.
+func (store *Store) updateNight(ctx context.Context, req nightRequest, isAvailable bool, nightRate int32, check func(version int32) error) (db.RoomAvailability, error) {
+	var night db.RoomAvailability
+	err := store.execTx(ctx, func(queries db.Querier) error {
+		current, err := queries.GetRoomAvailabilityByDate(ctx, db.GetRoomAvailabilityByDateParams{RoomID: req.RoomID, Date: req.date()})
+		if err != nil {
+			return nightError(err, req)
+		}
+		if err := check(current.Version); err != nil {
+			return err
+		}
+
+		night, err = queries.UpdateRoomAvailability(ctx, db.UpdateRoomAvailabilityParams{
+			RoomID:      req.RoomID,
+			Date:        req.date(),
+			IsAvailable: isAvailable,
+			NightRate:   nightRate,
+			Version:     current.Version,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Another request updated the night since it was read.
+			return preconditionFailed()
+		}
+		return err
+	})
+	return night, err
+}
+
+// optionalInt4 converts an optional JSON field to a nullable query argument.
+func optionalInt4(value *int32) pgtype.Int4 {
+	if value == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: *value, Valid: true}
+}
+
+// optionalBool converts an optional JSON field to a nullable query argument.
+func optionalBool(value *bool) pgtype.Bool {
+	if value == nil {
+		return pgtype.Bool{}
+	}
+	return pgtype.Bool{Bool: *value, Valid: true}
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// createTestAPIKey stores a new API key and returns the header authenticating requests with it.
+func createTestAPIKey(t *testing.T, store *Store, name string) http.Header {
+	t.Helper()
+	key, hash, err := GenerateAPIKey()
+	require.NoError(t, err)
+	_, err = store.CreateAPIKey(context.Background(), db.CreateAPIKeyParams{Name: name, KeyHash: hash})
+	require.NoError(t, err)
+	return http.Header{"Authorization": {"Bearer " + key}}
+}
+
+// serveJSON sends a request with a JSON body to the server and returns the recorded response.
+func serveJSON(server *Server, method, path string, header http.Header, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	for name, values := range header {
+		request.Header[name] = values
+	}
+	recorder := httptest.NewRecorder()
+	server.Router().ServeHTTP(recorder, request)
+	return recorder
+}
+
+// withIfMatch returns a copy of header with the given `If-Match` value.
+func withIfMatch(header http.Header, ifMatch string) http.Header {
+	header = header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("If-Match", ifMatch)
+	return header
+}
+
+func TestGetRoom(t *testing.T) {
+	server, store := newTestServer(t)
+	createTestRoom(t, store, 1, 5000)
+
+	recorder := serve(server, http.MethodGet, "/v1/rooms/1", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, `"1"`, recorder.Header().Get("ETag"))
+
+	var room Room
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &room))
+	require.Equal(t, int32(1), room.RoomID)
+	require.Equal(t, int32(4), room.MaxGuests)
+	require.Equal(t, int32(5000), room.DefaultRate)
+	require.Equal(t, int32(1), room.Version)
+
+	requireProblem(t, serve(server, http.MethodGet, "/v1/rooms/2", nil), http.StatusNotFound, "room_not_found")
+
+	// The versioned routes coexist with the room metrics route.
+	require.Equal(t, http.StatusOK, serve(server, http.MethodGet, "/1", nil).Code)
+}
+
+func TestPatchRoom(t *testing.T) {
+	server, store := newTestServer(t)
+	createTestRoom(t, store, 1, 5000)
+	auth := createTestAPIKey(t, store, "host")
+
+	// Writes require an API key.
+	recorder := serveJSON(server, http.MethodPatch, "/v1/rooms/1", withIfMatch(nil, `"1"`), `{"fridge": true}`)
+	requireProblem(t, recorder, http.StatusUnauthorized, "unauthorized")
+	require.Equal(t, "Bearer", recorder.Header().Get("WWW-Authenticate"))
+	recorder = serveJSON(server, http.MethodPatch, "/v1/rooms/1", http.Header{"Authorization": {"Bearer abk_unknown"}}, `{"fridge": true}`)
+	requireProblem(t, recorder, http.StatusUnauthorized, "unauthorized")
+
+	// Writes require the ETag of the version they are based on.
+	recorder = serveJSON(server, http.MethodPatch, "/v1/rooms/1", auth, `{"fridge": true}`)
+	requireProblem(t, recorder, http.StatusPreconditionRequired, "precondition_required")
+
+	recorder = serveJSON(server, http.MethodPatch, "/v1/rooms/1", withIfMatch(auth, `"1"`), `{"fridge": true, "default_rate": 6500}`)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, `"2"`, recorder.Header().Get("ETag"))
+
+	var room Room
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &room))
+	require.True(t, room.Fridge)
+	require.True(t, room.Balcony)
+	require.Equal(t, int32(4), room.MaxGuests)
+	require.Equal(t, int32(6500), room.DefaultRate)
+	require.Equal(t, int32(2), room.Version)
+
+	// A second host editing the first version is rejected instead of overwriting the change.
+	recorder = serveJSON(server, http.MethodPatch, "/v1/rooms/1", withIfMatch(auth, `"1"`), `{"fridge": false}`)
+	requireProblem(t, recorder, http.StatusPreconditionFailed, "precondition_failed")
+
+	// Weak tags never match; "*" matches any version.
+	recorder = serveJSON(server, http.MethodPatch, "/v1/rooms/1", withIfMatch(auth, `W/"2"`), `{"fridge": false}`)
+	requireProblem(t, recorder, http.StatusPreconditionFailed, "precondition_failed")
+	recorder = serveJSON(server, http.MethodPatch, "/v1/rooms/1", withIfMatch(auth, `"1", *`), `{"fridge": false}`)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, `"3"`, recorder.Header().Get("ETag"))
+
+	recorder = serveJSON(server, http.MethodPatch, "/v1/rooms/1", withIfMatch(auth, `"3"`), `{"max_guests": 0}`)
+	requireProblem(t, recorder, http.StatusBadRequest, "invalid_request")
+	recorder = serveJSON(server, http.MethodPatch, "/v1/rooms/2", withIfMatch(auth, `"1"`), `{"fridge": true}`)
+	requireProblem(t, recorder, http.StatusNotFound, "room_not_found")
+}
+
+func TestPutNight(t *testing.T) {
+	server, store := newTestServer(t)
+	createTestRoom(t, store, 1, 5000, 6000)
+	auth := createTestAPIKey(t, store, "host")
+	path := "/v1/rooms/1/availability/" + testNight(1).Time.Format(dateLayout)
+
+	recorder := serve(server, http.MethodGet, path, nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, `"1"`, recorder.Header().Get("ETag"))
+
+	var night Night
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &night))
+	require.False(t, night.IsAvailable)
+	require.Equal(t, int32(6000), night.NightRate)
+	require.NotNil(t, night.BookedAt)
+
+	// Fill the metrics cache, which the update must invalidate.
+	require.Equal(t, http.StatusOK, serve(server, http.MethodGet, "/1", nil).Code)
+
+	recorder = serveJSON(server, http.MethodPut, path, auth, `{"is_available": true, "night_rate": 7000}`)
+	requireProblem(t, recorder, http.StatusPreconditionRequired, "precondition_required")
+
+	recorder = serveJSON(server, http.MethodPut, path, withIfMatch(auth, `"1"`), `{"is_available": true, "night_rate": 7000}`)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, `"2"`, recorder.Header().Get("ETag"))
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &night))
+	require.True(t, night.IsAvailable)
+	require.Equal(t, int32(7000), night.NightRate)
+	require.Nil(t, night.BookedAt)
+	require.Equal(t, int32(2), night.Version)
+
+	var roomData RoomData
+	require.NoError(t, json.Unmarshal(serve(server, http.MethodGet, "/1", nil).Body.Bytes(), &roomData))
+	require.Equal(t, int32(7000), roomData.HighestRate)
+
+	recorder = serveJSON(server, http.MethodPut, path, withIfMatch(auth, `"1"`), `{"is_available": false, "night_rate": 5000}`)
+	requireProblem(t, recorder, http.StatusPreconditionFailed, "precondition_failed")
+
+	recorder = serveJSON(server, http.MethodPut, path, withIfMatch(auth, `"2"`), `{"night_rate": 5000}`)
+	requireProblem(t, recorder, http.StatusBadRequest, "invalid_request")
+	recorder = serveJSON(server, http.MethodPut, "/v1/rooms/1/availability/2024-13-01", withIfMatch(auth, `"1"`), `{"is_available": true, "night_rate": 5000}`)
+	requireProblem(t, recorder, http.StatusBadRequest, "invalid_request")
+	recorder = serveJSON(server, http.MethodPut, "/v1/rooms/1/availability/2030-01-01", withIfMatch(auth, `"1"`), `{"is_available": true, "night_rate": 5000}`)
+	requireProblem(t, recorder, http.StatusNotFound, "night_not_found")
+}
@@ -1,59 +1,111 @@
 package api
 
 import (
-	"log"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5"
 	db "github.com/vivek-344/airbnb-api/db/sqlc"
 )
 
-// Store provides all functions to execute db queries.
-type Store struct {
-	*db.Queries
-	conn *pgxpool.Pool
-}
-
-// NewStore creates a new Store that wraps the database connection pool and query methods.
-func NewStore(conn *pgxpool.Pool) *Store {
-	return &Store{
-		conn:    conn,
-		Queries: db.New(conn),
-	}
-}
-
 // getRoomRequest defines the expected URI parameters for fetching room data.
 type getRoomRequest struct {
 	RoomID int32 `binding:"required,min=1" uri:"room_id"`
 }
 
+// roomError translates an error returned while fetching a room, reporting missing rows as an unknown room.
+func roomError(err error, roomID int32) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return newError(ErrNotFound, "room_not_found", fmt.Sprintf("Room %d does not exist.", roomID), err)
+	}
+	return translateError(err)
+}
+
 // getRoomData is a handler method that delegates the request to the store.
 func (server *Server) getRoomData(ctx *gin.Context) {
 	server.store.getRoomData(ctx)
 }
 
 // getRoomData fetches detailed information about a room, including its availability, rates, and stats.
+// Responses are served from the metrics cache and carry an `ETag`, so clients can revalidate with `If-None-Match`.
+// Clients accepting CSV or XLSX get the nightly rates as a spreadsheet instead.
 func (store *Store) getRoomData(ctx *gin.Context) {
 	var req getRoomRequest
 
 	// Bind URI parameters to the request struct and validate them.
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(400, gin.H{"error": err.Error()}) // Respond with 400 Bad Request on validation failure.
+		abortWithError(ctx, newError(ErrValidation, "invalid_request", err.Error(), err)) // Respond with 400 Bad Request on validation failure.
 		return
 	}
 
-	// Fetch room details based on RoomID.
-	room, err := store.GetRoom(ctx, req.RoomID)
+	// A client asking for a fresh copy bypasses the cached metrics.
+	entry, err := store.roomMetrics(ctx, req.RoomID, strings.Contains(ctx.GetHeader("Cache-Control"), "no-cache"))
 	if err != nil {
-		ctx.JSON(404, gin.H{"error": "Room not found"}) // Respond with 404 if the room doesn't exist.
+		abortWithError(ctx, err)
 		return
 	}
 
+	// Spreadsheets flatten the nightly rates and monthly availability of the cached metrics into rows.
+	if format := negotiateFormat(ctx); format != gin.MIMEJSON {
+		var roomData RoomData
+		if err := json.Unmarshal(entry.body, &roomData); err != nil {
+			abortWithError(ctx, err)
+			return
+		}
+		writeTable(ctx, format, fmt.Sprintf("room-%d-metrics", req.RoomID), metricsHeader, metricsRows(roomData))
+		return
+	}
+
+	// Clients must revalidate before reusing a stored response, which is cheap thanks to the ETag.
+	ctx.Header("ETag", entry.etag)
+	ctx.Header("Cache-Control", "private, no-cache")
+	if etagMatches(ctx.GetHeader("If-None-Match"), entry.etag) {
+		ctx.Status(304)
+		return
+	}
+
+	// Send the room data as a JSON response with HTTP status 200.
+	ctx.Data(200, "application/json; charset=utf-8", entry.body)
+}
+
+// roomMetrics returns the cached room details and statistics of a room as JSON, computing them on a miss
+// or when fresh is set.
+func (store *Store) roomMetrics(ctx context.Context, roomID int32, fresh bool) (*metricsEntry, error) {
+	key := metricsKey{RoomID: roomID, Window: metricsWindowDays}
+	if entry, ok := store.cache.get(key); ok && !fresh {
+		return entry, nil
+	}
+
+	// Metrics computed while the room is written are not cached, as they may predate the write.
+	generation := store.cache.generation(roomID)
+	roomData, err := store.buildRoomData(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(roomData)
+	if err != nil {
+		return nil, err
+	}
+	return store.cache.put(key, body, generation), nil
+}
+
+// buildRoomData computes the room details and statistics returned by getRoomData.
+func (store *Store) buildRoomData(ctx context.Context, roomID int32) (RoomData, error) {
+	// Fetch room details based on RoomID.
+	room, err := store.GetRoom(ctx, roomID)
+	if err != nil {
+		return RoomData{}, roomError(err, roomID) // Reported as 404 if the room doesn't exist, 503 if the database is down.
+	}
+
 	// Fetch room availability and nightly rates for the next 30 days.
-	ratePerNight, err := store.ListRoomAvailability(ctx, req.RoomID)
+	ratePerNight, err := store.ListRoomAvailability(ctx, roomID)
 	if err != nil {
-		ctx.JSON(500, gin.H{"error": "Failed to fetch room availability"}) // Respond with 500 if fetching fails.
-		return
+		return RoomData{}, err // Reported as 500 if fetching fails.
 	}
 
 	// Convert the availability data into a format suitable for the API response.
@@ -67,44 +119,55 @@ func (store *Store) getRoomData(ctx *gin.Context) {
 	}
 
 	// Fetch a list of all available dates for the room.
-	availableDates, err := store.ListAvailableDates(ctx, req.RoomID)
+	availableDates, err := store.ListAvailableDates(ctx, roomID)
 	if err != nil {
-		ctx.JSON(500, gin.H{"error": "Failed to fetch available dates"}) // Respond with 500 on failure.
-		return
+		return RoomData{}, err // Reported as 500 on failure.
 	}
 
 	// Convert the available dates from `pgtype.Date` to string for API response.
 	var availableDateStrings []string
 	for _, date := range availableDates {
-		availableDateStrings = append(availableDateStrings, date.Time.Format("2006-01-02"))
+		availableDateStrings = append(availableDateStrings, date.Time.Format(dateLayout))
 	}
 
+	logger := loggerFromContext(ctx)
+
 	// Fetch the occupancy percentage for the room and handle errors gracefully.
 	var occupancyPercentage []db.GetAvailabilityPercentageRow
-	occupancyPercentage, err = store.GetAvailabilityPercentage(ctx, req.RoomID)
+	occupancyPercentage, err = store.GetAvailabilityPercentage(ctx, roomID)
 	if err != nil {
-		log.Printf("Error fetching occupancy percentage for room %d: %v", req.RoomID, err)
+		logger.Warn("failed to fetch occupancy percentage", slog.Int("room_id", int(roomID)), slog.Any("error", err))
 		occupancyPercentage = []db.GetAvailabilityPercentageRow{} // Use an empty slice instead of nil.
 	}
 
+	// Fetch the statistics of every month of the calendar, maintained as nights are written.
+	monthlyStats := []MonthStats{}
+	storedStats, err := store.ListRoomMonthStats(ctx, roomID)
+	if err != nil {
+		logger.Warn("failed to fetch monthly statistics", slog.Int("room_id", int(roomID)), slog.Any("error", err))
+	}
+	for _, stats := range storedStats {
+		monthlyStats = append(monthlyStats, newMonthStats(stats))
+	}
+
 	// Fetch the average nightly rate for the room.
-	averageRate, err := store.GetAverageRate(ctx, req.RoomID)
+	averageRate, err := store.GetAverageRate(ctx, roomID)
 	if err != nil {
-		log.Printf("Error fetching average rate for room %d: %v", req.RoomID, err)
+		logger.Warn("failed to fetch average rate", slog.Int("room_id", int(roomID)), slog.Any("error", err))
 		averageRate = 0 // Default to 0 if the average rate can't be calculated.
 	}
 
 	// Fetch the highest nightly rate for the room.
-	highestRate, err := store.GetMaximumRate(ctx, req.RoomID)
+	highestRate, err := store.GetMaximumRate(ctx, roomID)
 	if err != nil {
-		log.Printf("Error fetching highest rate for room %d: %v", req.RoomID, err)
+		logger.Warn("failed to fetch highest rate", slog.Int("room_id", int(roomID)), slog.Any("error", err))
 		highestRate = 0 // Default to 0 if no rate is found.
 	}
 
 	// Fetch the lowest nightly rate for the room.
-	lowestRate, err := store.GetMinimumRate(ctx, req.RoomID)
+	lowestRate, err := store.GetMinimumRate(ctx, roomID)
 	if err != nil {
-		log.Printf("Error fetching lowest rate for room %d: %v", req.RoomID, err)
+		logger.Warn("failed to fetch lowest rate", slog.Int("room_id", int(roomID)), slog.Any("error", err))
 		lowestRate = 0 // Default to 0 if no rate is found.
 	}
 
@@ -115,6 +178,7 @@ func (store *Store) getRoomData(ctx *gin.Context) {
 		MaxGuests:           room.MaxGuests,
 		AvailableDates:      availableDateStrings,
 		OccupancyPercentage: occupancyPercentage,
+		MonthlyStats:        monthlyStats,
 		AverageRate:         averageRate,
 		HighestRate:         highestRate,
 		LowestRate:          lowestRate,
@@ -124,6 +188,5 @@ func (store *Store) getRoomData(ctx *gin.Context) {
 		GamingConsole:       room.GamingConsole,
 	}
 
-	// Send the room data as a JSON response with HTTP status 200.
-	ctx.JSON(200, roomData)
+	return roomData, nil
 }
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+	"github.com/vivek-344/airbnb-api/db/memdb"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+func TestGetRoomData(t *testing.T) {
+	server, store := newTestServer(t)
+	createTestRoom(t, store, 101, 5000, 6000, 7000, 8000)
+
+	recorder := serve(server, http.MethodGet, "/101", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.NotEmpty(t, recorder.Header().Get("ETag"))
+
+	var body RoomData
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	require.Equal(t, int32(101), body.RoomID)
+	require.Equal(t, int32(4), body.MaxGuests)
+	require.True(t, body.Balcony)
+	require.False(t, body.MiniFridge)
+	require.True(t, body.GamingConsole)
+
+	require.Len(t, body.RatePerNight, 4)
+	require.Equal(t, testNight(1), body.RatePerNight[1].Date)
+	require.False(t, body.RatePerNight[1].IsAvailable)
+	require.Equal(t, int32(6000), body.RatePerNight[1].NightRate)
+	require.Equal(t, []string{"2024-06-28", "2024-06-30"}, body.AvailableDates)
+
+	require.InDelta(t, 6500, body.AverageRate, 0.001)
+	require.Equal(t, int32(8000), body.HighestRate)
+	require.Equal(t, int32(5000), body.LowestRate)
+
+	// June 28-30 has 2 of 3 nights available, July 1 is booked.
+	require.Len(t, body.OccupancyPercentage, 2)
+	percentage, err := body.OccupancyPercentage[0].AvailabilityPercentage.Float64Value()
+	require.NoError(t, err)
+	require.InDelta(t, 66.67, percentage.Float64, 0.001)
+
+	// Rates 5000 and 7000 are available, 6000 is booked in June; 8000 is booked in July.
+	require.Equal(t, []MonthStats{
+		{Month: "2024-06", Nights: 3, BookedNights: 1, OccupancyPercentage: 33.33, Revenue: 6000, AverageRate: 6000, LowestRate: 5000, HighestRate: 7000},
+		{Month: "2024-07", Nights: 1, BookedNights: 1, OccupancyPercentage: 100, Revenue: 8000, AverageRate: 8000, LowestRate: 8000, HighestRate: 8000},
+	}, body.MonthlyStats)
+}
+
+func TestGetRoomDataWithoutCalendar(t *testing.T) {
+	server, store := newTestServer(t)
+	createTestRoom(t, store, 101)
+
+	// Rates cannot be computed without nights, which is not an error.
+	recorder := serve(server, http.MethodGet, "/101", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var body RoomData
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	require.Empty(t, body.RatePerNight)
+	require.NotNil(t, body.MonthlyStats)
+	require.Empty(t, body.MonthlyStats)
+	require.Zero(t, body.AverageRate)
+	require.Zero(t, body.HighestRate)
+	require.Zero(t, body.LowestRate)
+}
+
+func TestGetRoomDataInvalidID(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	for _, path := range []string{"/abc", "/0", "/-1", "/99999999999"} {
+		t.Run(path, func(t *testing.T) {
+			recorder := serve(server, http.MethodGet, path, nil)
+			requireProblem(t, recorder, http.StatusBadRequest, "invalid_request")
+		})
+	}
+}
+
+func TestGetRoomDataNotFound(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	recorder := serve(server, http.MethodGet, "/404", nil)
+	requireProblem(t, recorder, http.StatusNotFound, "room_not_found")
+}
+
+// unavailableQuerier simulates a database shutting down.
+type unavailableQuerier struct {
+	*memdb.Queries
+}
+
+func (unavailableQuerier) GetRoom(ctx context.Context, roomID int32) (db.Room, error) {
+	return db.Room{}, &pgconn.PgError{Code: "57P01", Message: "terminating connection due to administrator command"}
+}
+
+func TestGetRoomDataUnavailable(t *testing.T) {
+	queries := memdb.New()
+	store := newMemoryStore(queries)
+	store.Querier = unavailableQuerier{queries}
+	server := NewServer(*store)
+
+	recorder := serve(server, http.MethodGet, "/101", nil)
+	requireProblem(t, recorder, http.StatusServiceUnavailable, "database_unavailable")
+}
+
+func TestGetRoomDataCaching(t *testing.T) {
+	server, store := newTestServer(t)
+	createTestRoom(t, store, 101, 5000, 6000)
+
+	first := serve(server, http.MethodGet, "/101", nil)
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+
+	// Revalidating an unchanged representation returns no body.
+	revalidated := serve(server, http.MethodGet, "/101", http.Header{"If-None-Match": {etag}})
+	require.Equal(t, http.StatusNotModified, revalidated.Code)
+	require.Empty(t, revalidated.Body.String())
+
+	// Writes bypassing the store are not seen until the client asks for a fresh copy.
+	ctx := context.Background()
+	_, err := store.Querier.UpdateMaxGuests(ctx, db.UpdateMaxGuestsParams{RoomID: 101, MaxGuests: 6})
+	require.NoError(t, err)
+
+	cached := serve(server, http.MethodGet, "/101", nil)
+	require.Equal(t, etag, cached.Header().Get("ETag"))
+
+	fresh := serve(server, http.MethodGet, "/101", http.Header{"Cache-Control": {"no-cache"}})
+	require.Equal(t, http.StatusOK, fresh.Code)
+	require.NotEqual(t, etag, fresh.Header().Get("ETag"))
+
+	// Writes through the store invalidate the cached metrics.
+	_, err = store.UpdateMaxGuests(ctx, db.UpdateMaxGuestsParams{RoomID: 101, MaxGuests: 8})
+	require.NoError(t, err)
+
+	updated := serve(server, http.MethodGet, "/101", http.Header{"If-None-Match": {fresh.Header().Get("ETag")}})
+	require.Equal(t, http.StatusOK, updated.Code)
+
+	var body RoomData
+	require.NoError(t, json.Unmarshal(updated.Body.Bytes(), &body))
+	require.Equal(t, int32(8), body.MaxGuests)
+}
@@ -1,13 +1,24 @@
 package api
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/graph-gophers/graphql-go"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
+// shutdownTimeout bounds the time the server waits for the requests in flight when it stops.
+const shutdownTimeout = 30 * time.Second
+
 // Server serves HTTP requests for our room service.
 type Server struct {
-	store  Store
-	router *gin.Engine
+	store   Store
+	router  *gin.Engine
+	graphql *graphql.Schema
 }
 
 // Router returns the router instance of the server.
@@ -18,7 +29,74 @@ func (server *Server) Router() *gin.Engine {
 // NewServer creates a new HTTP server and setup routing.
 func NewServer(store Store) *Server {
 	server := &Server{store: store}
-	router := gin.Default()
+	server.graphql = newGraphQLSchema(&server.store)
+	router := gin.New()
+
+	// Let handlers pass the gin context to queries while keeping the request's trace span as parent.
+	router.ContextWithFallback = true
+
+	router.Use(
+		otelgin.Middleware(serviceName),
+		requestIDMiddleware(),
+		loggingMiddleware(),
+		metricsMiddleware(),
+		gin.CustomRecovery(func(ctx *gin.Context, recovered any) {
+			abortWithError(ctx, fmt.Errorf("panic: %v", recovered))
+		}),
+	)
+
+	// Report unknown routes and methods as problem details as well.
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(func(ctx *gin.Context) {
+		abortWithError(ctx, newError(ErrNotFound, "route_not_found", "No route matches the request path.", nil))
+	})
+	router.NoMethod(func(ctx *gin.Context) {
+		abortWithError(ctx, newError(ErrMethodNotAllowed, "method_not_allowed", "The route does not support this method.", nil))
+	})
+
+	// Expose Prometheus metrics for scraping.
+	router.GET("/metrics", metricsHandler())
+
+	// Versioned room and calendar resources. Writes require an API key and an `If-Match` precondition,
+	// and are recorded in the audit log, which is only readable with an API key.
+	v1 := router.Group("/v1")
+	v1.GET("/rooms/:room_id", server.getRoom)
+	v1.PATCH("/rooms/:room_id", server.store.requireAPIKey(), server.patchRoom)
+	v1.GET("/rooms/:room_id/availability/:date", server.getNight)
+	v1.PUT("/rooms/:room_id/availability/:date", server.store.requireAPIKey(), server.putNight)
+	v1.GET("/rooms/:room_id/history", server.store.requireAPIKey(), server.getRoomHistory)
+
+	// CSV imports of rooms and calendars, validated as a whole and committed in a single transaction.
+	v1.POST("/import/rooms", server.store.requireAPIKey(), server.importRooms)
+	v1.POST("/import/calendar", server.store.requireAPIKey(), server.importCalendar)
+
+	// Webhook subscriptions, notified of changes to nights, and their delivery logs.
+	webhooks := v1.Group("/webhooks", server.store.requireAPIKey())
+	webhooks.POST("", server.createWebhook)
+	webhooks.GET("", server.listWebhooks)
+	webhooks.GET("/:webhook_id", server.getWebhook)
+	webhooks.DELETE("/:webhook_id", server.deleteWebhook)
+	webhooks.GET("/:webhook_id/deliveries", server.listWebhookDeliveries)
+	webhooks.POST("/:webhook_id/deliveries/:delivery_id/replay", server.replayWebhookDelivery)
+
+	// Live streams of the changes to rooms and calendars, as server-sent events.
+	v1.GET("/rooms/:room_id/events", server.streamRoomEvents)
+	v1.GET("/portfolio/events", server.streamPortfolioEvents)
+
+	// Year-over-year comparisons, including archived nights.
+	v1.GET("/rooms/:room_id/comparison", server.getRoomComparison)
+	v1.GET("/portfolio/comparison", server.getPortfolioComparison)
+
+	// Nightly rates and availability of every room, for export as CSV or XLSX.
+	v1.GET("/portfolio/metrics", server.getPortfolioMetrics)
+
+	// Occupancy forecasts from the booking pace.
+	v1.GET("/rooms/:room_id/forecast", server.getRoomForecast)
+	v1.GET("/portfolio/forecast", server.getPortfolioForecast)
+
+	// GraphQL queries of rooms with their calendars, metrics and reservations, batched across rooms.
+	router.GET("/graphql", server.serveGraphQL)
+	router.POST("/graphql", server.serveGraphQL)
 
 	// Change to GET method for fetching room data
 	router.GET("/:room_id", server.getRoomData)
@@ -27,11 +105,23 @@ func NewServer(store Store) *Server {
 	return server
 }
 
-// Start runs the HTTP server on a specific address.
-func (server *Server) Start(address string) error {
-	return server.router.Run(address)
-}
+// Start runs the HTTP server on a specific address until the context is canceled. It then stops accepting
+// connections and waits up to shutdownTimeout for the requests in flight to complete. Event streams end
+// when the event hub stops, so they don't hold the shutdown up.
+func (server *Server) Start(ctx context.Context, address string) error {
+	httpServer := &http.Server{Addr: address, Handler: server.router.Handler()}
+	errs := make(chan error, 1)
+	go func() {
+		errs <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
 
-func errorResponse(err error) gin.H {
-	return gin.H{"error": err.Error()}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return httpServer.Shutdown(shutdownCtx)
 }
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"github.com/vivek-344/airbnb-api/db/memdb"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// testToday is the CURRENT_DATE of the in-memory database used by the handler tests.
+var testToday = time.Date(2024, 6, 28, 0, 0, 0, 0, time.UTC)
+
+// newMemoryStore creates a Store keeping its data in memory, so that handlers can be tested without PostgreSQL.
+// Transactions are serialized, whatever their isolation level, and background jobs run without leader
+// election since there is a single replica.
+func newMemoryStore(queries *memdb.Queries) *Store {
+	return &Store{
+		Querier: queries,
+		cache:   newMetricsCache(metricsCacheSize, metricsCacheTTL),
+		events:  newEventHub(),
+		beginTx: func(ctx context.Context, _ pgx.TxOptions, fn func(db.Querier) error) error {
+			return queries.ExecTx(ctx, fn)
+		},
+	}
+}
+
+// newTestServer creates a server backed by an in-memory store whose clock is set to testToday.
+func newTestServer(t *testing.T) (*Server, *Store) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	store := newMemoryStore(memdb.NewWithClock(func() time.Time { return testToday.Add(12 * time.Hour) }))
+	return NewServer(*store), store
+}
+
+// testNight returns the date the given number of days after testToday.
+func testNight(day int) pgtype.Date {
+	return pgtype.Date{Time: testToday.AddDate(0, 0, day), Valid: true}
+}
+
+// createTestRoom creates a room with a calendar alternating available and booked nights from today.
+func createTestRoom(t *testing.T, store *Store, roomID int32, rates ...int32) {
+	t.Helper()
+	ctx := context.Background()
+
+	_, err := store.CreateRoom(ctx, db.CreateRoomParams{RoomID: roomID, MaxGuests: 4, Balcony: true, GamingConsole: true, DefaultRate: 5000})
+	require.NoError(t, err)
+	for day, rate := range rates {
+		_, err := store.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{
+			RoomID:      roomID,
+			Date:        testNight(day),
+			IsAvailable: day%2 == 0,
+			NightRate:   rate,
+		})
+		require.NoError(t, err)
+	}
+}
+
+// serve sends a request to the server and returns the recorded response.
+func serve(server *Server, method, path string, header http.Header) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, nil)
+	for name, values := range header {
+		request.Header[name] = values
+	}
+	recorder := httptest.NewRecorder()
+	server.Router().ServeHTTP(recorder, request)
+	return recorder
+}
+
+// requireProblem checks that the response is a problem with the given status and code.
+func requireProblem(t *testing.T, recorder *httptest.ResponseRecorder, status int, code string) {
+	t.Helper()
+	require.Equal(t, status, recorder.Code)
+	require.Equal(t, problemContentType, recorder.Header().Get("Content-Type"))
+
+	var body problem
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	require.Equal(t, status, body.Status)
+	require.Equal(t, code, body.Code)
+}
+
+func TestUnknownRoute(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	recorder := serve(server, http.MethodGet, "/1/unknown", nil)
+	requireProblem(t, recorder, http.StatusNotFound, "route_not_found")
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	recorder := serve(server, http.MethodDelete, "/1", nil)
+	requireProblem(t, recorder, http.StatusMethodNotAllowed, "method_not_allowed")
+}
+
+func TestMetricsRoute(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	recorder := serve(server, http.MethodGet, "/metrics", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "airbnb_http_requests_total")
+}
+
+func TestServerShutdown(t *testing.T) {
+	server, _ := newTestServer(t)
+	started := make(chan struct{})
+	server.Router().GET("/slow", func(ctx *gin.Context) {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		ctx.String(http.StatusOK, "done")
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- server.Start(ctx, address)
+	}()
+
+	responses := make(chan *http.Response, 1)
+	go func() {
+		for { // Until the server listens.
+			response, err := http.Get("http://" + address + "/slow")
+			if err == nil {
+				responses <- response
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	// A request in flight when the server stops completes before Start returns.
+	<-started
+	cancel()
+	require.NoError(t, <-stopped)
+	response := <-responses
+	defer response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// CheckMonthStats returns the months whose stored statistics differ from the nights of their calendar,
+// which only happens if the triggers maintaining them were bypassed, e.g. by a manual change.
+// If repair is set, the statistics of these months are recomputed and the metrics cache is dropped.
+func (store *Store) CheckMonthStats(ctx context.Context, repair bool) ([]db.ListInconsistentRoomMonthStatsRow, error) {
+	inconsistent, err := store.ListInconsistentRoomMonthStats(ctx)
+	if err != nil || len(inconsistent) == 0 || !repair {
+		return inconsistent, err
+	}
+
+	arg := db.RefreshRoomMonthStatsParams{}
+	for _, month := range inconsistent {
+		arg.RoomIds = append(arg.RoomIds, month.RoomID)
+		arg.Months = append(arg.Months, month.Month)
+	}
+	err = store.RefreshRoomMonthStats(ctx, arg)
+	store.cache.purge()
+	if err != nil {
+		return inconsistent, err
+	}
+
+	slog.Info("monthly statistics repaired", slog.Int("months", len(inconsistent)))
+	return inconsistent, nil
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// maxStayNights is the longest stay that can be searched or quoted, and the longest range of nights listed at once.
+const maxStayNights = 366
+
+// Stay is a number of consecutive nights, from the check-in date to the day before the check-out date.
+type Stay struct {
+	CheckIn  pgtype.Date
+	CheckOut pgtype.Date
+	Guests   int32
+}
+
+// parseStay validates a stay given as `YYYY-MM-DD` dates. The number of guests defaults to 1.
+func parseStay(checkIn, checkOut string, guests int32) (Stay, error) {
+	from, to, err := parseNightRange(checkIn, checkOut, "check_in", "check_out")
+	if err != nil {
+		return Stay{}, err
+	}
+	if guests < 0 {
+		return Stay{}, newError(ErrValidation, "invalid_request", "guests must not be negative.", nil)
+	}
+	return Stay{CheckIn: from, CheckOut: to, Guests: max(guests, 1)}, nil
+}
+
+// parseNightRange validates the dates of a range of nights, the second one excluded, spanning at most maxStayNights.
+func parseNightRange(from, to, fromName, toName string) (pgtype.Date, pgtype.Date, error) {
+	first, err := time.Parse(dateLayout, from)
+	if err != nil {
+		return pgtype.Date{}, pgtype.Date{}, newError(ErrValidation, "invalid_request", fmt.Sprintf("%s must be a date formatted as YYYY-MM-DD.", fromName), err)
+	}
+	last, err := time.Parse(dateLayout, to)
+	if err != nil {
+		return pgtype.Date{}, pgtype.Date{}, newError(ErrValidation, "invalid_request", fmt.Sprintf("%s must be a date formatted as YYYY-MM-DD.", toName), err)
+	}
+	if !last.After(first) || last.After(first.AddDate(0, 0, maxStayNights)) {
+		return pgtype.Date{}, pgtype.Date{}, newError(ErrValidation, "invalid_request",
+			fmt.Sprintf("%s must be between 1 and %d days after %s.", toName, maxStayNights, fromName), nil)
+	}
+	return pgtype.Date{Time: first, Valid: true}, pgtype.Date{Time: last, Valid: true}, nil
+}
+
+// nights returns the number of nights of the stay.
+func (stay Stay) nights() int {
+	return int(stay.CheckOut.Time.Sub(stay.CheckIn.Time).Hours() / 24)
+}
+
+// quote prices a stay in a room, which must host the guests and have every night of the stay available.
+func (store *Store) quote(ctx context.Context, roomID int32, stay Stay) (Quote, error) {
+	room, err := store.GetRoom(ctx, roomID)
+	if err != nil {
+		return Quote{}, roomError(err, roomID)
+	}
+	if stay.Guests > room.MaxGuests {
+		return Quote{}, newError(ErrValidation, "too_many_guests", fmt.Sprintf("Room %d hosts at most %d guests.", roomID, room.MaxGuests), nil)
+	}
+
+	nights, err := store.ListRoomNights(ctx, db.ListRoomNightsParams{RoomID: roomID, StartDate: stay.CheckIn, EndDate: stay.CheckOut})
+	if err != nil {
+		return Quote{}, err
+	}
+
+	quote := Quote{
+		RoomID:   roomID,
+		CheckIn:  stay.CheckIn,
+		CheckOut: stay.CheckOut,
+		Guests:   stay.Guests,
+		Nights:   []QuoteNight{},
+	}
+	for i := range stay.nights() {
+		date := stay.CheckIn.Time.AddDate(0, 0, i)
+		// Nights are listed by date, so the night of the i-th day is the i-th one unless one is missing.
+		if i >= len(nights) || !nights[i].Date.Time.Equal(date) || !nights[i].IsAvailable {
+			return Quote{}, newError(ErrConflict, "room_unavailable",
+				fmt.Sprintf("Room %d is not available on %s.", roomID, date.Format(dateLayout)), nil)
+		}
+		quote.Nights = append(quote.Nights, QuoteNight{Date: nights[i].Date, NightRate: nights[i].NightRate})
+		quote.Total += int64(nights[i].NightRate)
+	}
+	quote.AverageRate = round2(float64(quote.Total) / float64(len(quote.Nights)))
+	return quote, nil
+}
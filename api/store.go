@@ -0,0 +1,169 @@
+package api
+
+import (
+	"context"
+	"iter"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// Store provides all functions to execute db queries.
+// The queries run against any db.Querier: PostgreSQL in production, or memory in tests.
+type Store struct {
+	db.Querier
+	conn   *pgxpool.Pool // nil for in-memory stores.
+	cache  *metricsCache
+	events *eventHub
+
+	// beginTx runs fn in a single transaction, without retries.
+	beginTx func(ctx context.Context, options pgx.TxOptions, fn func(db.Querier) error) error
+
+	// privateWebhooks lets webhooks target loopback, link-local and private addresses, for tests.
+	privateWebhooks bool
+}
+
+// NewStore creates a new Store that wraps the database connection pool and query methods.
+// Every query is instrumented with latency metrics and the pool statistics are exposed on `/metrics`.
+func NewStore(conn *pgxpool.Pool) *Store {
+	registerPoolMetrics(conn)
+
+	return &Store{
+		Querier: db.New(newInstrumentedDBTX(conn)),
+		conn:    conn,
+		cache:   newMetricsCache(metricsCacheSize, metricsCacheTTL),
+		events:  newEventHub(),
+		beginTx: func(ctx context.Context, options pgx.TxOptions, fn func(db.Querier) error) error {
+			return pgx.BeginTxFunc(ctx, conn, options, func(tx pgx.Tx) error {
+				return fn(db.New(newInstrumentedDBTX(tx)))
+			})
+		},
+	}
+}
+
+// The write methods below shadow those of the embedded queries. Each runs in its own transaction,
+// along with the audit event it records, and drops the cached metrics of the rooms it wrote once committed.
+
+// write runs fn in a transaction whose writes are recorded in the audit log.
+func write[T any](ctx context.Context, store *Store, fn func(queries db.Querier) (T, error)) (T, error) {
+	var result T
+	var touched touchedRooms
+	err := store.beginTx(ctx, pgx.TxOptions{}, func(queries db.Querier) error {
+		var err error
+		result, err = fn(auditedQueries{Querier: queries, touched: &touched})
+		return err
+	})
+	if err == nil {
+		store.cache.invalidateRooms(touched)
+	}
+	return result, err
+}
+
+// CreateRoom creates a room and drops any cached metrics for its ID.
+func (store *Store) CreateRoom(ctx context.Context, arg db.CreateRoomParams) (db.Room, error) {
+	return write(ctx, store, func(queries db.Querier) (db.Room, error) { return queries.CreateRoom(ctx, arg) })
+}
+
+// UpsertRoom creates or replaces a room and drops its cached metrics.
+func (store *Store) UpsertRoom(ctx context.Context, arg db.UpsertRoomParams) (db.Room, error) {
+	return write(ctx, store, func(queries db.Querier) (db.Room, error) { return queries.UpsertRoom(ctx, arg) })
+}
+
+// UpdateRoom updates the given fields of a room at the given version and drops its cached metrics.
+func (store *Store) UpdateRoom(ctx context.Context, arg db.UpdateRoomParams) (db.Room, error) {
+	return write(ctx, store, func(queries db.Querier) (db.Room, error) { return queries.UpdateRoom(ctx, arg) })
+}
+
+// UpdateMaxGuests updates the guest limit of a room and drops its cached metrics.
+func (store *Store) UpdateMaxGuests(ctx context.Context, arg db.UpdateMaxGuestsParams) (db.Room, error) {
+	return write(ctx, store, func(queries db.Querier) (db.Room, error) { return queries.UpdateMaxGuests(ctx, arg) })
+}
+
+// UpdateRoomFridge updates the fridge amenity of a room and drops its cached metrics.
+func (store *Store) UpdateRoomFridge(ctx context.Context, arg db.UpdateRoomFridgeParams) (db.Room, error) {
+	return write(ctx, store, func(queries db.Querier) (db.Room, error) { return queries.UpdateRoomFridge(ctx, arg) })
+}
+
+// UpdateRoomConsole updates the gaming console amenity of a room and drops its cached metrics.
+func (store *Store) UpdateRoomConsole(ctx context.Context, arg db.UpdateRoomConsoleParams) (db.Room, error) {
+	return write(ctx, store, func(queries db.Querier) (db.Room, error) { return queries.UpdateRoomConsole(ctx, arg) })
+}
+
+// DeleteRoom deletes a room and drops its cached metrics.
+func (store *Store) DeleteRoom(ctx context.Context, roomID int32) error {
+	_, err := write(ctx, store, func(queries db.Querier) (struct{}, error) { return struct{}{}, queries.DeleteRoom(ctx, roomID) })
+	return err
+}
+
+// CreateRoomAvailability adds a night to the calendar of a room and drops its cached metrics.
+func (store *Store) CreateRoomAvailability(ctx context.Context, arg db.CreateRoomAvailabilityParams) (db.RoomAvailability, error) {
+	return write(ctx, store, func(queries db.Querier) (db.RoomAvailability, error) {
+		return queries.CreateRoomAvailability(ctx, arg)
+	})
+}
+
+// UpdateRoomAvailability updates a night in the calendar of a room and drops its cached metrics.
+func (store *Store) UpdateRoomAvailability(ctx context.Context, arg db.UpdateRoomAvailabilityParams) (db.RoomAvailability, error) {
+	return write(ctx, store, func(queries db.Querier) (db.RoomAvailability, error) {
+		return queries.UpdateRoomAvailability(ctx, arg)
+	})
+}
+
+// UpsertRoomAvailability creates or replaces a night in the calendar of a room and drops its cached metrics.
+func (store *Store) UpsertRoomAvailability(ctx context.Context, arg db.UpsertRoomAvailabilityParams) (db.RoomAvailability, error) {
+	return write(ctx, store, func(queries db.Querier) (db.RoomAvailability, error) {
+		return queries.UpsertRoomAvailability(ctx, arg)
+	})
+}
+
+// DeleteAllAvailabilityForRoom clears the calendar of a room and drops its cached metrics.
+func (store *Store) DeleteAllAvailabilityForRoom(ctx context.Context, roomID int32) ([]pgtype.Date, error) {
+	return write(ctx, store, func(queries db.Querier) ([]pgtype.Date, error) {
+		return queries.DeleteAllAvailabilityForRoom(ctx, roomID)
+	})
+}
+
+// ArchiveOldRoomAvailabilityData archives past nights of every room and drops all cached metrics.
+func (store *Store) ArchiveOldRoomAvailabilityData(ctx context.Context) error {
+	_, err := write(ctx, store, func(queries db.Querier) (struct{}, error) {
+		return struct{}{}, queries.ArchiveOldRoomAvailabilityData(ctx)
+	})
+	return err
+}
+
+// ArchiveRoomPastAvailability archives the past nights of a room and drops its cached metrics.
+func (store *Store) ArchiveRoomPastAvailability(ctx context.Context, roomID int32) ([]pgtype.Date, error) {
+	return write(ctx, store, func(queries db.Querier) ([]pgtype.Date, error) {
+		return queries.ArchiveRoomPastAvailability(ctx, roomID)
+	})
+}
+
+// ExtendRoomAvailability adds the missing nights of a room up to the horizon and drops its cached metrics.
+func (store *Store) ExtendRoomAvailability(ctx context.Context, arg db.ExtendRoomAvailabilityParams) ([]pgtype.Date, error) {
+	return write(ctx, store, func(queries db.Querier) ([]pgtype.Date, error) { return queries.ExtendRoomAvailability(ctx, arg) })
+}
+
+// CopyRoomAvailability copies nights with the COPY protocol and drops the cached metrics of their rooms.
+func (store *Store) CopyRoomAvailability(ctx context.Context, arg []db.CopyRoomAvailabilityParams) (int64, error) {
+	return write(ctx, store, func(queries db.Querier) (int64, error) { return queries.CopyRoomAvailability(ctx, arg) })
+}
+
+// DeleteRoomAvailabilityNights deletes the given nights and drops the cached metrics of their rooms.
+func (store *Store) DeleteRoomAvailabilityNights(ctx context.Context, arg db.DeleteRoomAvailabilityNightsParams) ([]db.DeleteRoomAvailabilityNightsRow, error) {
+	return write(ctx, store, func(queries db.Querier) ([]db.DeleteRoomAvailabilityNightsRow, error) {
+		return queries.DeleteRoomAvailabilityNights(ctx, arg)
+	})
+}
+
+// CreateAPIKey stores the hash of a new API key.
+func (store *Store) CreateAPIKey(ctx context.Context, arg db.CreateAPIKeyParams) (db.ApiKey, error) {
+	return write(ctx, store, func(queries db.Querier) (db.ApiKey, error) { return queries.CreateAPIKey(ctx, arg) })
+}
+
+// BulkLoadAvailability copies availability rows in batches with the COPY protocol.
+// Every batch is a transaction run by ExecTx, which drops the cached metrics of the rooms of the batch.
+func (store *Store) BulkLoadAvailability(ctx context.Context, rows iter.Seq2[db.CopyRoomAvailabilityParams, error], options db.BulkOptions) (int64, error) {
+	return db.BulkLoadAvailability(ctx, store.execTx, rows, options)
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// defaultTxAttempts is the number of times a transaction is run when TxOptions.MaxAttempts is not set.
+const defaultTxAttempts = 3
+
+// txRetryBaseDelay is the backoff before the first retry of a transaction. It doubles with every attempt.
+const txRetryBaseDelay = 10 * time.Millisecond
+
+// PostgreSQL error codes of transactions that can succeed when run again.
+const (
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+)
+
+// TxOptions configures a transaction run by Store.ExecTx.
+type TxOptions struct {
+	// IsoLevel is the isolation level of the transaction, READ COMMITTED by default.
+	IsoLevel pgx.TxIsoLevel
+	// ReadOnly runs the transaction in read only mode.
+	ReadOnly bool
+	// MaxAttempts is the number of times the transaction is run when it fails with a serialization failure
+	// or a deadlock. It defaults to 3.
+	MaxAttempts int
+}
+
+// ExecTx calls fn with queries running in a single transaction, which is committed if fn succeeds and
+// rolled back otherwise. Serialization failures and deadlocks roll back the transaction and run fn again
+// after a short backoff, so fn must not have side effects outside of the transaction.
+// The writes made by fn are recorded in the audit log, in the same transaction.
+// Committed transactions drop the cached metrics of the rooms they wrote.
+func (store *Store) ExecTx(ctx context.Context, options TxOptions, fn func(queries db.Querier) error) error {
+	attempts := options.MaxAttempts
+	if attempts <= 0 {
+		attempts = defaultTxAttempts
+	}
+
+	txOptions := pgx.TxOptions{IsoLevel: options.IsoLevel}
+	if options.ReadOnly {
+		txOptions.AccessMode = pgx.ReadOnly
+	}
+
+	var err error
+	var touched touchedRooms
+	for attempt := 1; ; attempt++ {
+		err = store.beginTx(ctx, txOptions, func(queries db.Querier) error {
+			touched = touchedRooms{}
+			return fn(auditedQueries{Querier: queries, touched: &touched})
+		})
+		code, retryable := retryableTxError(err)
+		if !retryable || attempt == attempts {
+			break
+		}
+
+		dbTxRetriesTotal.WithLabelValues(code).Inc()
+		loggerFromContext(ctx).Debug("retrying transaction", slog.Int("attempt", attempt), slog.Any("error", err))
+		if err := sleep(ctx, txBackoff(attempt)); err != nil {
+			return err
+		}
+	}
+
+	if err == nil {
+		store.cache.invalidateRooms(touched)
+	}
+	return err
+}
+
+// execTx runs fn in a transaction with the default options. It implements db.TxFunc.
+func (store *Store) execTx(ctx context.Context, fn func(queries db.Querier) error) error {
+	return store.ExecTx(ctx, TxOptions{}, fn)
+}
+
+// retryableTxError reports whether the transaction failed because of a concurrent transaction,
+// and returns the PostgreSQL error code.
+func retryableTxError(err error) (string, bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && (pgErr.Code == pgSerializationFailure || pgErr.Code == pgDeadlockDetected) {
+		return pgErr.Code, true
+	}
+	return "", false
+}
+
+// txBackoff returns the delay before the given retry, with full jitter so that conflicting
+// transactions do not retry in lockstep.
+func txBackoff(attempt int) time.Duration {
+	ceiling := txRetryBaseDelay << (attempt - 1)
+	return time.Duration(rand.Int64N(int64(ceiling))) + time.Millisecond
+}
+
+// sleep waits for the given duration unless the context is done first.
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// createRoomInTx creates a room with the queries of a transaction.
+func createRoomInTx(ctx context.Context, queries db.Querier, roomID int32) error {
+	_, err := queries.CreateRoom(ctx, db.CreateRoomParams{RoomID: roomID, MaxGuests: 2, DefaultRate: 5000})
+	return err
+}
+
+func TestExecTxCommit(t *testing.T) {
+	_, store := newTestServer(t)
+	ctx := context.Background()
+
+	err := store.ExecTx(ctx, TxOptions{}, func(queries db.Querier) error {
+		if err := createRoomInTx(ctx, queries, 101); err != nil {
+			return err
+		}
+		return createRoomInTx(ctx, queries, 102)
+	})
+	require.NoError(t, err)
+
+	count, err := store.GetRoomCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+}
+
+func TestExecTxRollback(t *testing.T) {
+	_, store := newTestServer(t)
+	ctx := context.Background()
+	createTestRoom(t, store, 101)
+
+	// The second room conflicts with the existing one, so the first is rolled back as well.
+	err := store.ExecTx(ctx, TxOptions{}, func(queries db.Querier) error {
+		if err := createRoomInTx(ctx, queries, 102); err != nil {
+			return err
+		}
+		return createRoomInTx(ctx, queries, 101)
+	})
+	require.ErrorIs(t, translateError(err), ErrConflict)
+
+	_, err = store.GetRoom(ctx, 102)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func TestExecTxRetry(t *testing.T) {
+	_, store := newTestServer(t)
+	ctx := context.Background()
+
+	calls := 0
+	err := store.ExecTx(ctx, TxOptions{IsoLevel: pgx.Serializable}, func(queries db.Querier) error {
+		calls++
+		if err := createRoomInTx(ctx, queries, 101); err != nil {
+			return err
+		}
+		if calls == 1 {
+			return &pgconn.PgError{Code: pgSerializationFailure}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+
+	// The first attempt was rolled back, otherwise the second would have failed with a unique violation.
+	count, err := store.GetRoomCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
+
+func TestExecTxGivesUp(t *testing.T) {
+	_, store := newTestServer(t)
+	ctx := context.Background()
+
+	calls := 0
+	err := store.ExecTx(ctx, TxOptions{MaxAttempts: 4}, func(queries db.Querier) error {
+		calls++
+		return &pgconn.PgError{Code: pgDeadlockDetected}
+	})
+	require.Equal(t, 4, calls)
+
+	var domainErr *Error
+	require.ErrorAs(t, translateError(err), &domainErr)
+	require.Equal(t, "transaction_conflict", domainErr.Code)
+}
+
+func TestExecTxDoesNotRetryOtherErrors(t *testing.T) {
+	_, store := newTestServer(t)
+	failure := errors.New("failure")
+
+	calls := 0
+	err := store.ExecTx(context.Background(), TxOptions{}, func(queries db.Querier) error {
+		calls++
+		return failure
+	})
+	require.ErrorIs(t, err, failure)
+	require.Equal(t, 1, calls)
+}
+
+func TestExecTxCanceled(t *testing.T) {
+	_, store := newTestServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	calls := 0
+	err := store.ExecTx(ctx, TxOptions{}, func(queries db.Querier) error {
+		calls++
+		cancel()
+		return &pgconn.PgError{Code: pgSerializationFailure}
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 1, calls)
+}
+
+func TestExecTxInvalidatesCache(t *testing.T) {
+	_, store := newTestServer(t)
+	ctx := context.Background()
+	createTestRoom(t, store, 101, 5000)
+	written := metricsKey{RoomID: 101, Window: metricsWindowDays}
+	other := metricsKey{RoomID: 102, Window: metricsWindowDays}
+
+	store.cache.put(written, []byte("{}"), store.cache.generation(written.RoomID))
+	store.cache.put(other, []byte("{}"), store.cache.generation(other.RoomID))
+	require.NoError(t, store.ExecTx(ctx, TxOptions{}, func(queries db.Querier) error { return nil }))
+	_, ok := store.cache.get(written)
+	require.True(t, ok)
+
+	// Only the rooms written by a committed transaction are dropped.
+	require.NoError(t, store.ExecTx(ctx, TxOptions{}, func(queries db.Querier) error {
+		_, err := queries.UpsertRoomAvailability(ctx, db.UpsertRoomAvailabilityParams{RoomID: 101, Date: testNight(0), IsAvailable: false, NightRate: 6000})
+		return err
+	}))
+	_, ok = store.cache.get(written)
+	require.False(t, ok)
+	_, ok = store.cache.get(other)
+	require.True(t, ok)
+
+	// Rolled back writes keep the cache.
+	store.cache.put(written, []byte("{}"), store.cache.generation(written.RoomID))
+	failure := errors.New("failure")
+	err := store.ExecTx(ctx, TxOptions{}, func(queries db.Querier) error {
+		_, err := queries.UpsertRoomAvailability(ctx, db.UpsertRoomAvailabilityParams{RoomID: 101, Date: testNight(0), IsAvailable: true, NightRate: 6000})
+		require.NoError(t, err)
+		return failure
+	})
+	require.ErrorIs(t, err, failure)
+	_, ok = store.cache.get(written)
+	require.True(t, ok)
+
+	// Writes to the calendars of every room drop the whole cache.
+	require.NoError(t, store.ExecTx(ctx, TxOptions{}, func(queries db.Querier) error {
+		return queries.ArchiveOldRoomAvailabilityData(ctx)
+	}))
+	_, ok = store.cache.get(written)
+	require.False(t, ok)
+	_, ok = store.cache.get(other)
+	require.False(t, ok)
+}
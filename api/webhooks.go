@@ -0,0 +1,546 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// Headers sent with every webhook delivery.
+const (
+	webhookEventHeader     = "Webhook-Event"
+	webhookEventIDHeader   = "Webhook-Event-Id"
+	webhookDeliveryHeader  = "Webhook-Delivery-Id"
+	webhookSignatureHeader = "Webhook-Signature"
+)
+
+// webhookLockID is the key of the advisory lock electing the replica that delivers webhooks.
+const webhookLockID = 7_344_005
+
+// Limits of webhook subscriptions and deliveries.
+const (
+	webhookSecretBytes    = 32       // Random bytes of a generated secret.
+	maxWebhookErrorLength = 500      // Characters of the error recorded for a failed attempt.
+	maxWebhookReadBytes   = 64 << 10 // Bytes of a response read before closing the connection.
+)
+
+// WebhookOptions configures the delivery of webhooks.
+type WebhookOptions struct {
+	// MaxAttempts is the number of attempts after which a delivery is marked as failed.
+	MaxAttempts int32
+	// RetryDelay is the delay before the first retry, doubled after every failed attempt up to MaxRetryDelay.
+	// It must be positive.
+	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration
+	// Timeout bounds every attempt, including reading the response.
+	Timeout time.Duration
+	// BatchSize is the number of due deliveries of a subscription fetched at once.
+	BatchSize int32
+	// Concurrency is the number of subscriptions delivered to at once. It must be positive.
+	Concurrency int
+}
+
+// DefaultWebhookOptions returns options retrying a delivery for about a day.
+func DefaultWebhookOptions() WebhookOptions {
+	return WebhookOptions{
+		MaxAttempts:   10,
+		RetryDelay:    30 * time.Second,
+		MaxRetryDelay: 6 * time.Hour,
+		Timeout:       10 * time.Second,
+		BatchSize:     100,
+		Concurrency:   8,
+	}
+}
+
+// retryDelay returns the delay before the attempt following the given number of failed attempts.
+func (options WebhookOptions) retryDelay(attempts int32) time.Duration {
+	delay := options.RetryDelay
+	for i := int32(1); i < attempts && delay < options.MaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, options.MaxRetryDelay)
+}
+
+// WebhookResult summarizes a run of the webhook delivery job.
+type WebhookResult struct {
+	Delivered int
+	Retried   int
+	Failed    int
+}
+
+// WebhookJob returns the job delivering the due webhooks.
+func (store *Store) WebhookJob(options WebhookOptions, interval time.Duration) Job {
+	return Job{
+		Name:     "webhook_delivery",
+		LockID:   webhookLockID,
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			_, err := store.DeliverWebhooks(ctx, options)
+			return err
+		},
+		CompletionLevel: slog.LevelDebug,
+	}
+}
+
+// add adds the deliveries of another run to the result.
+func (result *WebhookResult) add(other WebhookResult) {
+	result.Delivered += other.Delivered
+	result.Retried += other.Retried
+	result.Failed += other.Failed
+}
+
+// DeliverWebhooks posts every due delivery to the URL of its subscription and records the attempt.
+// A delivery answered with a 2xx status is delivered; any other outcome is retried with exponential backoff,
+// until the delivery fails after options.MaxAttempts attempts. Redirects are not followed.
+//
+// Up to options.Concurrency subscriptions are delivered to at once, each by a single worker posting its
+// deliveries one at a time, oldest first. A subscription whose receiver does not respond is skipped until
+// the next run, so that an unreachable receiver costs at most one timeout per run and never holds up the
+// deliveries of other subscriptions.
+func (store *Store) DeliverWebhooks(ctx context.Context, options WebhookOptions) (WebhookResult, error) {
+	subscriptionIDs, err := store.ListDueWebhookSubscriptions(ctx)
+	if err != nil {
+		return WebhookResult{}, err
+	}
+
+	client := store.webhookClient(options.Timeout)
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		result    WebhookResult
+		firstErr  error
+		semaphore = make(chan struct{}, options.Concurrency)
+	)
+	for _, subscriptionID := range subscriptionIDs {
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			subscriptionResult, err := store.deliverSubscriptionWebhooks(ctx, client, options, subscriptionID)
+			mu.Lock()
+			defer mu.Unlock()
+			result.add(subscriptionResult)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}()
+	}
+	wg.Wait()
+	return result, firstErr
+}
+
+// deliverSubscriptionWebhooks posts the due deliveries of a subscription, oldest first, until its receiver
+// does not respond.
+func (store *Store) deliverSubscriptionWebhooks(ctx context.Context, client *http.Client, options WebhookOptions, subscriptionID int64) (WebhookResult, error) {
+	var result WebhookResult
+	for {
+		deliveries, err := store.ListDueWebhookDeliveries(ctx, db.ListDueWebhookDeliveriesParams{
+			SubscriptionID: subscriptionID,
+			RowLimit:       options.BatchSize,
+		})
+		if err != nil {
+			return result, err
+		}
+
+		for _, delivery := range deliveries {
+			status, err := sendWebhook(ctx, client, delivery)
+			if ctx.Err() != nil {
+				return result, ctx.Err() // The attempt was interrupted, not failed.
+			}
+
+			arg := db.RecordWebhookDeliveryAttemptParams{
+				ID:             delivery.ID,
+				Status:         "delivered",
+				RetryDelay:     pgtype.Interval{Valid: true},
+				ResponseStatus: status,
+			}
+			outcome := "delivered"
+			if err != nil {
+				message := err.Error()
+				if len(message) > maxWebhookErrorLength {
+					message = message[:maxWebhookErrorLength]
+				}
+				arg.LastError = pgtype.Text{String: message, Valid: true}
+
+				if attempts := delivery.Attempts + 1; attempts >= options.MaxAttempts {
+					arg.Status, outcome = "failed", "failed"
+					result.Failed++
+				} else {
+					arg.Status, outcome = "pending", "retried"
+					arg.RetryDelay.Microseconds = options.retryDelay(attempts).Microseconds()
+					result.Retried++
+				}
+			} else {
+				result.Delivered++
+			}
+
+			if _, err := store.RecordWebhookDeliveryAttempt(ctx, arg); err != nil {
+				return result, err
+			}
+			webhookDeliveriesTotal.WithLabelValues(delivery.EventType, outcome).Inc()
+
+			if err != nil && !status.Valid {
+				return result, nil // The receiver is unreachable; its other deliveries wait for the next run.
+			}
+		}
+
+		if len(deliveries) < int(options.BatchSize) {
+			return result, nil
+		}
+	}
+}
+
+// errWebhookAddress reports a connection to an address webhooks may not target.
+var errWebhookAddress = errors.New("webhooks may not target loopback, link-local or private addresses")
+
+// webhookAddressAllowed reports whether webhooks may target an address: only public unicast addresses are,
+// so that subscriptions cannot reach the services of the internal network or the cloud metadata endpoint.
+func (store *Store) webhookAddressAllowed(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return store.privateWebhooks || (addr.IsGlobalUnicast() && !addr.IsPrivate())
+}
+
+// webhookClient returns the client posting deliveries. Redirects are not followed and proxies are not used.
+// The address of every connection is checked once resolved, so that a host resolving to another address
+// than when its subscription was created cannot reach a private one.
+func (store *Store) webhookClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !store.webhookAddressAllowed(addrPort.Addr()) {
+				return errWebhookAddress
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// sendWebhook posts a delivery and returns the status of the response, if any.
+// A response with a status other than 2xx is returned as an error.
+func sendWebhook(ctx context.Context, client *http.Client, delivery db.ListDueWebhookDeliveriesRow) (pgtype.Int4, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return pgtype.Int4{}, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", serviceName+"-webhooks")
+	request.Header.Set(webhookEventHeader, delivery.EventType)
+	request.Header.Set(webhookEventIDHeader, delivery.EventID.String())
+	request.Header.Set(webhookDeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	request.Header.Set(webhookSignatureHeader, webhookSignature(delivery.Secret, time.Now().Unix(), delivery.Payload))
+
+	response, err := client.Do(request)
+	if err != nil {
+		return pgtype.Int4{}, err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, maxWebhookReadBytes)) // Lets the connection be reused.
+
+	status := pgtype.Int4{Int32: int32(response.StatusCode), Valid: true}
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return status, fmt.Errorf("unexpected response status %d", response.StatusCode)
+	}
+	return status, nil
+}
+
+// webhookSignature returns the signature header of a delivery sent at timestamp (Unix seconds):
+// `t=<timestamp>,v1=<signature>`, the signature being the hex HMAC-SHA256 of `<timestamp>.<body>` keyed
+// with the secret of the subscription. Receivers should reject deliveries with an old timestamp.
+func webhookSignature(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// webhookState is the state of a subscription recorded in the audit log, which is not meant to hold secrets.
+type webhookState struct {
+	ID         int64    `json:"id"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+}
+
+func (q auditedQueries) CreateWebhookSubscription(ctx context.Context, arg db.CreateWebhookSubscriptionParams) (db.WebhookSubscription, error) {
+	subscription, err := q.Querier.CreateWebhookSubscription(ctx, arg)
+	if err != nil {
+		return subscription, err
+	}
+	state := webhookState{subscription.ID, subscription.Url, subscription.EventTypes}
+	return subscription, q.recordEvent(ctx, db.CreateAuditEventParams{Action: actionWebhookCreate}, nil, state)
+}
+
+func (q auditedQueries) DeleteWebhookSubscription(ctx context.Context, id int64) (int64, error) {
+	subscription, err := q.Querier.GetWebhookSubscription(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := q.Querier.DeleteWebhookSubscription(ctx, id)
+	if err != nil || deleted == 0 {
+		return deleted, err
+	}
+	state := webhookState{subscription.ID, subscription.Url, subscription.EventTypes}
+	return deleted, q.recordEvent(ctx, db.CreateAuditEventParams{Action: actionWebhookDelete}, state, nil)
+}
+
+// CreateWebhookSubscription subscribes a URL to events.
+func (store *Store) CreateWebhookSubscription(ctx context.Context, arg db.CreateWebhookSubscriptionParams) (db.WebhookSubscription, error) {
+	return write(ctx, store, func(queries db.Querier) (db.WebhookSubscription, error) {
+		return queries.CreateWebhookSubscription(ctx, arg)
+	})
+}
+
+// DeleteWebhookSubscription deletes a subscription along with its delivery log.
+func (store *Store) DeleteWebhookSubscription(ctx context.Context, id int64) (int64, error) {
+	return write(ctx, store, func(queries db.Querier) (int64, error) { return queries.DeleteWebhookSubscription(ctx, id) })
+}
+
+// createWebhookRequest defines the body of a new subscription. A secret is generated if none is given.
+type createWebhookRequest struct {
+	URL        string   `json:"url" binding:"required,url,max=2048"`
+	Secret     string   `json:"secret" binding:"omitempty,min=16,max=256"`
+	EventTypes []string `json:"event_types" binding:"required,min=1,dive,oneof=room.created room.updated room.deleted availability.changed rate.changed reservation.created calendar.changed"`
+}
+
+// webhookRequest defines the URI parameters identifying a subscription.
+type webhookRequest struct {
+	WebhookID int64 `uri:"webhook_id" binding:"required,min=1"`
+}
+
+// webhookDeliveryRequest defines the URI parameters identifying a delivery of a subscription.
+type webhookDeliveryRequest struct {
+	WebhookID  int64 `uri:"webhook_id" binding:"required,min=1"`
+	DeliveryID int64 `uri:"delivery_id" binding:"required,min=1"`
+}
+
+// listWebhookDeliveriesRequest defines the query parameters filtering the delivery log of a subscription.
+type listWebhookDeliveriesRequest struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending delivered failed"`
+	Limit  int32  `form:"limit,default=50" binding:"min=1,max=500"`
+	Offset int32  `form:"offset" binding:"min=0"`
+}
+
+// webhookError reports a missing subscription as such.
+func webhookError(err error, webhookID int64) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return newError(ErrNotFound, "webhook_not_found", fmt.Sprintf("Webhook %d does not exist.", webhookID), err)
+	}
+	return translateError(err)
+}
+
+// checkWebhookURL checks that a URL is an absolute HTTP or HTTPS URL whose host is not, and does not resolve to,
+// a loopback, link-local or private address. Hosts that do not resolve are accepted, since deliveries check the
+// addresses they connect to anyway.
+func (server *Server) checkWebhookURL(ctx context.Context, rawURL string) error {
+	target, err := url.Parse(rawURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Hostname() == "" {
+		return newError(ErrValidation, "invalid_request", "The URL must be an absolute HTTP or HTTPS URL.", err)
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", target.Hostname())
+	if err != nil {
+		return nil
+	}
+	for _, addr := range addrs {
+		if !server.store.webhookAddressAllowed(addr) {
+			return newError(ErrValidation, "invalid_request", "The URL must not target a loopback, link-local or private address.", errWebhookAddress)
+		}
+	}
+	return nil
+}
+
+// createWebhook subscribes an HTTP(S) URL to the given event types. The response is the only one holding the secret.
+func (server *Server) createWebhook(ctx *gin.Context) {
+	var req createWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, newError(ErrValidation, "invalid_request", err.Error(), err))
+		return
+	}
+	if err := server.checkWebhookURL(ctx, req.URL); err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	if req.Secret == "" {
+		secret := make([]byte, webhookSecretBytes)
+		if _, err := rand.Read(secret); err != nil {
+			abortWithError(ctx, err)
+			return
+		}
+		req.Secret = "whsec_" + hex.EncodeToString(secret)
+	}
+	slices.Sort(req.EventTypes)
+
+	subscription, err := server.store.CreateWebhookSubscription(ctx, db.CreateWebhookSubscriptionParams{
+		Url:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: slices.Compact(req.EventTypes),
+	})
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	response := newWebhookSubscription(subscription)
+	response.Secret = subscription.Secret
+	ctx.Header("Location", fmt.Sprintf("/v1/webhooks/%d", subscription.ID))
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// listWebhooks returns every subscription, oldest first.
+func (server *Server) listWebhooks(ctx *gin.Context) {
+	subscriptions, err := server.store.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	response := make([]WebhookSubscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		response = append(response, newWebhookSubscription(subscription))
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// getWebhook returns a subscription.
+func (server *Server) getWebhook(ctx *gin.Context) {
+	var req webhookRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, newError(ErrValidation, "invalid_request", err.Error(), err))
+		return
+	}
+
+	subscription, err := server.store.GetWebhookSubscription(ctx, req.WebhookID)
+	if err != nil {
+		abortWithError(ctx, webhookError(err, req.WebhookID))
+		return
+	}
+	ctx.JSON(http.StatusOK, newWebhookSubscription(subscription))
+}
+
+// deleteWebhook deletes a subscription. Its pending deliveries are dropped along with its delivery log.
+func (server *Server) deleteWebhook(ctx *gin.Context) {
+	var req webhookRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, newError(ErrValidation, "invalid_request", err.Error(), err))
+		return
+	}
+
+	deleted, err := server.store.DeleteWebhookSubscription(ctx, req.WebhookID)
+	if err == nil && deleted == 0 {
+		err = pgx.ErrNoRows
+	}
+	if err != nil {
+		abortWithError(ctx, webhookError(err, req.WebhookID))
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// listWebhookDeliveries returns the delivery log of a subscription, most recent deliveries first.
+func (server *Server) listWebhookDeliveries(ctx *gin.Context) {
+	var req webhookRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, newError(ErrValidation, "invalid_request", err.Error(), err))
+		return
+	}
+
+	var query listWebhookDeliveriesRequest
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		abortWithError(ctx, newError(ErrValidation, "invalid_request", err.Error(), err))
+		return
+	}
+
+	if _, err := server.store.GetWebhookSubscription(ctx, req.WebhookID); err != nil {
+		abortWithError(ctx, webhookError(err, req.WebhookID))
+		return
+	}
+
+	deliveries, err := server.store.ListWebhookDeliveries(ctx, db.ListWebhookDeliveriesParams{
+		SubscriptionID: req.WebhookID,
+		Status:         pgtype.Text{String: query.Status, Valid: query.Status != ""},
+		RowLimit:       query.Limit,
+		RowOffset:      query.Offset,
+	})
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	response := make([]WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		response = append(response, newWebhookDelivery(delivery))
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// replayWebhookDelivery queues a new delivery of the event of a logged delivery, whatever its status.
+// The event keeps its ID, so that receivers can recognize events they already processed.
+func (server *Server) replayWebhookDelivery(ctx *gin.Context) {
+	var req webhookDeliveryRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, newError(ErrValidation, "invalid_request", err.Error(), err))
+		return
+	}
+
+	delivery, err := server.store.GetWebhookDelivery(ctx, req.DeliveryID)
+	if err == nil && delivery.SubscriptionID != req.WebhookID {
+		err = pgx.ErrNoRows
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		message := fmt.Sprintf("Webhook %d has no delivery %d.", req.WebhookID, req.DeliveryID)
+		abortWithError(ctx, newError(ErrNotFound, "webhook_delivery_not_found", message, err))
+		return
+	}
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	replay, err := server.store.ReplayWebhookDelivery(ctx, delivery.ID)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, newWebhookDelivery(replay))
+}
@@ -0,0 +1,373 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/vivek-344/airbnb-api/db/memdb"
+)
+
+// testWebhookSecret is the secret of the test subscriptions.
+const testWebhookSecret = "0123456789abcdef0123456789abcdef"
+
+// webhookReceiver records the deliveries it receives and answers them with a configurable status.
+type webhookReceiver struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newWebhookReceiver(t *testing.T) *webhookReceiver {
+	t.Helper()
+	receiver := &webhookReceiver{status: http.StatusNoContent}
+	receiver.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		receiver.mu.Lock()
+		defer receiver.mu.Unlock()
+		receiver.requests = append(receiver.requests, r)
+		receiver.bodies = append(receiver.bodies, body)
+		w.WriteHeader(receiver.status)
+	}))
+	t.Cleanup(receiver.Close)
+	return receiver
+}
+
+// respond sets the status of the following responses.
+func (receiver *webhookReceiver) respond(status int) {
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+	receiver.status = status
+}
+
+// received returns the number of deliveries received.
+func (receiver *webhookReceiver) received() int {
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+	return len(receiver.requests)
+}
+
+// newWebhookTestServer creates a test server whose database clock is set by the returned function.
+func newWebhookTestServer(t *testing.T) (*Server, *Store, func(time.Duration)) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	now := testToday.Add(12 * time.Hour)
+	store := newMemoryStore(memdb.NewWithClock(func() time.Time { return now }))
+	store.privateWebhooks = true // The receivers listen on the loopback interface.
+	advance := func(d time.Duration) { now = now.Add(d) }
+	return NewServer(*store), store, advance
+}
+
+// createTestWebhook subscribes the receiver to the given event types.
+func createTestWebhook(t *testing.T, server *Server, auth http.Header, url string, eventTypes ...string) WebhookSubscription {
+	t.Helper()
+	types, err := json.Marshal(eventTypes)
+	require.NoError(t, err)
+	body := fmt.Sprintf(`{"url": %q, "secret": %q, "event_types": %s}`, url, testWebhookSecret, types)
+	recorder := serveJSON(server, http.MethodPost, "/v1/webhooks", auth, body)
+	require.Equal(t, http.StatusCreated, recorder.Code, recorder.Body.String())
+
+	var subscription WebhookSubscription
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &subscription))
+	return subscription
+}
+
+// dispatchTestOutbox queues the webhook deliveries of the pending events of the outbox.
+func dispatchTestOutbox(t *testing.T, store *Store) {
+	t.Helper()
+	_, err := store.DispatchOutbox(context.Background(), []OutboxSink{store.WebhookSink()})
+	require.NoError(t, err)
+}
+
+// listTestDeliveries returns the delivery log of a subscription.
+func listTestDeliveries(t *testing.T, server *Server, auth http.Header, path string) []WebhookDelivery {
+	t.Helper()
+	recorder := serve(server, http.MethodGet, path, auth)
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+	var deliveries []WebhookDelivery
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &deliveries))
+	return deliveries
+}
+
+func TestWebhookSubscriptions(t *testing.T) {
+	server, store := newTestServer(t)
+	auth := createTestAPIKey(t, store, "integrations")
+
+	body := `{"url": "https://example.com/hook", "event_types": ["rate.changed", "availability.changed", "rate.changed"]}`
+	requireProblem(t, serveJSON(server, http.MethodPost, "/v1/webhooks", nil, body), http.StatusUnauthorized, "unauthorized")
+
+	// A secret is generated when none is given, and only returned on creation.
+	recorder := serveJSON(server, http.MethodPost, "/v1/webhooks", auth, body)
+	require.Equal(t, http.StatusCreated, recorder.Code, recorder.Body.String())
+	var subscription WebhookSubscription
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &subscription))
+	require.True(t, strings.HasPrefix(subscription.Secret, "whsec_"))
+	require.Equal(t, []string{"availability.changed", "rate.changed"}, subscription.EventTypes)
+	require.Equal(t, fmt.Sprintf("/v1/webhooks/%d", subscription.ID), recorder.Header().Get("Location"))
+
+	recorder = serve(server, http.MethodGet, "/v1/webhooks", auth)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.NotContains(t, recorder.Body.String(), "secret")
+
+	recorder = serve(server, http.MethodGet, fmt.Sprintf("/v1/webhooks/%d", subscription.ID), auth)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.NotContains(t, recorder.Body.String(), "secret")
+
+	for name, body := range map[string]string{
+		"scheme":     `{"url": "ftp://example.com/hook", "event_types": ["rate.changed"]}`,
+		"type":       `{"url": "https://example.com/hook", "event_types": ["room.renamed"]}`,
+		"no types":   `{"url": "https://example.com/hook", "event_types": []}`,
+		"short":      `{"url": "https://example.com/hook", "secret": "short", "event_types": ["rate.changed"]}`,
+		"no url":     `{"event_types": ["rate.changed"]}`,
+		"relative":   `{"url": "/hook", "event_types": ["rate.changed"]}`,
+		"loopback":   `{"url": "http://127.0.0.1:8080/hook", "event_types": ["rate.changed"]}`,
+		"localhost":  `{"url": "http://localhost/hook", "event_types": ["rate.changed"]}`,
+		"ipv6":       `{"url": "http://[::1]/hook", "event_types": ["rate.changed"]}`,
+		"mapped":     `{"url": "http://[::ffff:127.0.0.1]/hook", "event_types": ["rate.changed"]}`,
+		"metadata":   `{"url": "http://169.254.169.254/latest/meta-data", "event_types": ["rate.changed"]}`,
+		"private":    `{"url": "https://10.0.0.1/hook", "event_types": ["rate.changed"]}`,
+		"local":      `{"url": "https://[fd00::1]/hook", "event_types": ["rate.changed"]}`,
+		"any":        `{"url": "http://0.0.0.0/hook", "event_types": ["rate.changed"]}`,
+		"not json":   `{"url":`,
+		"wrong type": `{"url": "https://example.com/hook", "event_types": "rate.changed"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			requireProblem(t, serveJSON(server, http.MethodPost, "/v1/webhooks", auth, body), http.StatusBadRequest, "invalid_request")
+		})
+	}
+
+	recorder = serve(server, http.MethodDelete, fmt.Sprintf("/v1/webhooks/%d", subscription.ID), auth)
+	require.Equal(t, http.StatusNoContent, recorder.Code)
+	requireProblem(t, serve(server, http.MethodGet, fmt.Sprintf("/v1/webhooks/%d", subscription.ID), auth), http.StatusNotFound, "webhook_not_found")
+	requireProblem(t, serve(server, http.MethodDelete, fmt.Sprintf("/v1/webhooks/%d", subscription.ID), auth), http.StatusNotFound, "webhook_not_found")
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	server, store, advance := newWebhookTestServer(t)
+	auth := createTestAPIKey(t, store, "integrations")
+	receiver := newWebhookReceiver(t)
+	ctx := context.Background()
+	options := DefaultWebhookOptions()
+
+	// Nights written before the subscription are not notified.
+	createTestRoom(t, store, 1, 5000)
+	subscription := createTestWebhook(t, server, auth, receiver.URL, eventReservationCreated, eventRateChanged)
+
+	// Booking the night at a new rate is both a reservation and a rate change, but the availability
+	// change is not subscribed to.
+	path := "/v1/rooms/1/availability/" + testNight(0).Time.Format(dateLayout)
+	recorder := serveJSON(server, http.MethodPut, path, withIfMatch(auth, `"1"`), `{"is_available": false, "night_rate": 6000}`)
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	dispatchTestOutbox(t, store)
+
+	deliveriesPath := fmt.Sprintf("/v1/webhooks/%d/deliveries", subscription.ID)
+	deliveries := listTestDeliveries(t, server, auth, deliveriesPath)
+	require.Len(t, deliveries, 2)
+	require.Equal(t, eventRateChanged, deliveries[0].EventType)
+	require.Equal(t, eventReservationCreated, deliveries[1].EventType)
+	require.Equal(t, "pending", deliveries[0].Status)
+
+	// A failed attempt is retried after the retry delay.
+	receiver.respond(http.StatusInternalServerError)
+	result, err := store.DeliverWebhooks(ctx, options)
+	require.NoError(t, err)
+	require.Equal(t, WebhookResult{Retried: 2}, result)
+
+	deliveries = listTestDeliveries(t, server, auth, deliveriesPath)
+	require.Equal(t, int32(1), deliveries[0].Attempts)
+	require.Equal(t, int32(http.StatusInternalServerError), *deliveries[0].ResponseStatus)
+	require.Equal(t, "unexpected response status 500", *deliveries[0].LastError)
+	require.Equal(t, deliveries[0].LastAttemptAt.Add(options.RetryDelay), *deliveries[0].NextAttemptAt)
+
+	result, err = store.DeliverWebhooks(ctx, options)
+	require.NoError(t, err)
+	require.Zero(t, result)
+
+	receiver.respond(http.StatusNoContent)
+	advance(options.RetryDelay)
+	result, err = store.DeliverWebhooks(ctx, options)
+	require.NoError(t, err)
+	require.Equal(t, WebhookResult{Delivered: 2}, result)
+	require.Equal(t, 4, receiver.received())
+
+	// Deliveries are signed with the secret of the subscription.
+	request, body := receiver.requests[2], receiver.bodies[2]
+	require.Equal(t, "application/json", request.Header.Get("Content-Type"))
+	require.Equal(t, eventReservationCreated, request.Header.Get(webhookEventHeader))
+	require.Equal(t, strconv.FormatInt(deliveries[1].ID, 10), request.Header.Get(webhookDeliveryHeader))
+	signature := request.Header.Get(webhookSignatureHeader)
+	timestamp, err := strconv.ParseInt(strings.TrimPrefix(strings.Split(signature, ",")[0], "t="), 10, 64)
+	require.NoError(t, err)
+	require.Equal(t, webhookSignature(testWebhookSecret, timestamp, body), signature)
+	require.NotEqual(t, webhookSignature("another secret", timestamp, body), signature)
+
+	var event NightEvent
+	require.NoError(t, json.Unmarshal(body, &event))
+	require.Equal(t, eventReservationCreated, event.Type)
+	require.Equal(t, request.Header.Get(webhookEventIDHeader), event.ID.String())
+	require.Equal(t, int32(1), event.RoomID)
+	require.True(t, event.Before.IsAvailable)
+	require.Equal(t, int32(5000), event.Before.NightRate)
+	require.False(t, event.After.IsAvailable)
+	require.Equal(t, int32(6000), event.After.NightRate)
+
+	delivered := listTestDeliveries(t, server, auth, deliveriesPath+"?status=delivered")
+	require.Len(t, delivered, 2)
+	require.Equal(t, int32(2), delivered[0].Attempts)
+	require.Nil(t, delivered[0].NextAttemptAt)
+	require.Empty(t, listTestDeliveries(t, server, auth, deliveriesPath+"?status=pending"))
+
+	// A replay delivers the same event again.
+	recorder = serve(server, http.MethodPost, fmt.Sprintf("%s/%d/replay", deliveriesPath, delivered[1].ID), auth)
+	require.Equal(t, http.StatusAccepted, recorder.Code, recorder.Body.String())
+	var replay WebhookDelivery
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &replay))
+	require.Equal(t, delivered[1].ID, *replay.ReplayOf)
+	require.Equal(t, delivered[1].EventID, replay.EventID)
+	require.Equal(t, "pending", replay.Status)
+
+	result, err = store.DeliverWebhooks(ctx, options)
+	require.NoError(t, err)
+	require.Equal(t, WebhookResult{Delivered: 1}, result)
+	require.Equal(t, delivered[1].EventID.String(), receiver.requests[4].Header.Get(webhookEventIDHeader))
+
+	// Writes changing nothing are not notified.
+	recorder = serveJSON(server, http.MethodPut, path, withIfMatch(auth, `"2"`), `{"is_available": false, "night_rate": 6000}`)
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	dispatchTestOutbox(t, store)
+	require.Len(t, listTestDeliveries(t, server, auth, deliveriesPath), 3)
+
+	requireProblem(t, serve(server, http.MethodGet, deliveriesPath+"?status=lost", auth), http.StatusBadRequest, "invalid_request")
+	requireProblem(t, serve(server, http.MethodGet, "/v1/webhooks/99/deliveries", auth), http.StatusNotFound, "webhook_not_found")
+}
+
+func TestWebhookDeliveryFailure(t *testing.T) {
+	server, store, advance := newWebhookTestServer(t)
+	auth := createTestAPIKey(t, store, "integrations")
+	receiver := newWebhookReceiver(t)
+	receiver.respond(http.StatusGone)
+	ctx := context.Background()
+	options := DefaultWebhookOptions()
+	options.MaxAttempts = 2
+
+	createTestRoom(t, store, 1)
+	subscription := createTestWebhook(t, server, auth, receiver.URL, eventAvailabilityChanged)
+	other := createTestWebhook(t, server, auth, receiver.URL, eventRateChanged)
+
+	// Importing a new night changes the availability of the room.
+	body := "room_id,date,is_available,night_rate\n1,2024-06-28,true,5000\n"
+	readImportReport(t, serveCSV(server, "/v1/import/calendar", auth, body), http.StatusOK)
+	dispatchTestOutbox(t, store)
+
+	result, err := store.DeliverWebhooks(ctx, options)
+	require.NoError(t, err)
+	require.Equal(t, WebhookResult{Retried: 1}, result)
+
+	advance(options.RetryDelay)
+	result, err = store.DeliverWebhooks(ctx, options)
+	require.NoError(t, err)
+	require.Equal(t, WebhookResult{Failed: 1}, result)
+
+	deliveriesPath := fmt.Sprintf("/v1/webhooks/%d/deliveries", subscription.ID)
+	deliveries := listTestDeliveries(t, server, auth, deliveriesPath+"?status=failed")
+	require.Len(t, deliveries, 1)
+	require.Equal(t, int32(2), deliveries[0].Attempts)
+	require.Equal(t, int32(http.StatusGone), *deliveries[0].ResponseStatus)
+
+	var event NightEvent
+	require.NoError(t, json.Unmarshal(deliveries[0].Payload, &event))
+	require.Nil(t, event.Before)
+
+	// Deliveries are only replayed through their own subscription.
+	replayPath := fmt.Sprintf("/v1/webhooks/%d/deliveries/%d/replay", other.ID, deliveries[0].ID)
+	requireProblem(t, serve(server, http.MethodPost, replayPath, auth), http.StatusNotFound, "webhook_delivery_not_found")
+
+	// Deleting the subscription drops its delivery log.
+	recorder := serve(server, http.MethodDelete, fmt.Sprintf("/v1/webhooks/%d", subscription.ID), auth)
+	require.Equal(t, http.StatusNoContent, recorder.Code)
+	_, err = store.GetWebhookDelivery(ctx, deliveries[0].ID)
+	require.Error(t, err)
+}
+
+func TestWebhookDeliveryPrivateAddress(t *testing.T) {
+	server, store, _ := newWebhookTestServer(t)
+	auth := createTestAPIKey(t, store, "integrations")
+	receiver := newWebhookReceiver(t)
+
+	// The host of the subscription resolves to a loopback address once subscribed, as after DNS rebinding.
+	subscription := createTestWebhook(t, server, auth, receiver.URL, eventAvailabilityChanged)
+	store.privateWebhooks = false
+	createTestRoom(t, store, 1, 5000)
+	dispatchTestOutbox(t, store)
+
+	result, err := store.DeliverWebhooks(context.Background(), DefaultWebhookOptions())
+	require.NoError(t, err)
+	require.Equal(t, WebhookResult{Retried: 1}, result)
+	require.Zero(t, receiver.received())
+
+	deliveries := listTestDeliveries(t, server, auth, fmt.Sprintf("/v1/webhooks/%d/deliveries", subscription.ID))
+	require.Len(t, deliveries, 1)
+	require.Nil(t, deliveries[0].ResponseStatus)
+	require.Contains(t, *deliveries[0].LastError, errWebhookAddress.Error())
+}
+
+func TestWebhookDeliveryUnresponsiveReceiver(t *testing.T) {
+	server, store, _ := newWebhookTestServer(t)
+	auth := createTestAPIKey(t, store, "integrations")
+	receiver := newWebhookReceiver(t)
+	options := DefaultWebhookOptions()
+	options.Timeout = 100 * time.Millisecond
+
+	// The unresponsive receiver holds every request until the end of the test.
+	var requests atomic.Int32
+	release := make(chan struct{})
+	unresponsive := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+	}))
+	t.Cleanup(unresponsive.Close)
+	t.Cleanup(func() { close(release) })
+
+	unreachable := createTestWebhook(t, server, auth, unresponsive.URL, eventAvailabilityChanged)
+	createTestWebhook(t, server, auth, receiver.URL, eventAvailabilityChanged)
+	createTestRoom(t, store, 1, 5000, 5000, 5000)
+	dispatchTestOutbox(t, store)
+
+	// The deliveries of the responsive receiver do not wait for the unresponsive one, which is skipped
+	// after its first timeout.
+	result, err := store.DeliverWebhooks(context.Background(), options)
+	require.NoError(t, err)
+	require.Equal(t, WebhookResult{Delivered: 3, Retried: 1}, result)
+	require.Equal(t, 3, receiver.received())
+	require.Equal(t, int32(1), requests.Load())
+
+	deliveries := listTestDeliveries(t, server, auth, fmt.Sprintf("/v1/webhooks/%d/deliveries?status=pending", unreachable.ID))
+	require.Len(t, deliveries, 3)
+	attempts := []int32{deliveries[0].Attempts, deliveries[1].Attempts, deliveries[2].Attempts}
+	require.Equal(t, []int32{0, 0, 1}, attempts)
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	options := WebhookOptions{RetryDelay: time.Minute, MaxRetryDelay: 5 * time.Minute}
+	require.Equal(t, time.Minute, options.retryDelay(1))
+	require.Equal(t, 2*time.Minute, options.retryDelay(2))
+	require.Equal(t, 4*time.Minute, options.retryDelay(3))
+	require.Equal(t, 5*time.Minute, options.retryDelay(4))
+	require.Equal(t, 5*time.Minute, options.retryDelay(40))
+}
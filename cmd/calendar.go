@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/spf13/cobra"
+	"github.com/vivek-344/airbnb-api/api"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+	"github.com/vivek-344/airbnb-api/util"
+)
+
+// calendarCmd groups the availability calendar subcommands.
+var calendarCmd = &cobra.Command{
+	Use:   "calendar",
+	Short: "Edit room availability calendars",
+}
+
+var calendarSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set the availability and nightly rate of a room for a range of dates",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		flags := cmd.Flags()
+		roomID, _ := flags.GetInt32("room")
+		fromFlag, _ := flags.GetString("from")
+		toFlag, _ := flags.GetString("to")
+		rate, _ := flags.GetInt32("rate")
+		available, _ := flags.GetBool("available")
+
+		from, err := time.Parse(dateLayout, fromFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --from date: %w", err)
+		}
+		to := from
+		if toFlag != "" {
+			to, err = time.Parse(dateLayout, toFlag)
+			if err != nil {
+				return fmt.Errorf("invalid --to date: %w", err)
+			}
+		}
+		if to.Before(from) {
+			return fmt.Errorf("--to must not be before --from")
+		}
+		if rate < 1 {
+			return fmt.Errorf("--rate must be positive")
+		}
+
+		conn, err := connect(cmd.Context())
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		// Either every night of the range is set, or none is.
+		nights := 0
+		err = api.NewStore(conn).ExecTx(cmd.Context(), api.TxOptions{}, func(queries db.Querier) error {
+			nights = 0
+			for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+				_, err := queries.UpsertRoomAvailability(cmd.Context(), db.UpsertRoomAvailabilityParams{
+					RoomID:      roomID,
+					Date:        pgtype.Date{Time: date, Valid: true},
+					IsAvailable: available,
+					NightRate:   rate,
+				})
+				if err != nil {
+					return fmt.Errorf("cannot set availability of room %d on %s: %w", roomID, date.Format(dateLayout), err)
+				}
+				nights++
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "updated %d nights of room %d\n", nights, roomID)
+		return nil
+	},
+}
+
+var calendarImportCmd = &cobra.Command{
+	Use:   "import FILE",
+	Short: "Bulk load availability from a CSV file with the header room_id,date,is_available,night_rate",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		batchSize, _ := cmd.Flags().GetInt("batch-size")
+		replace, _ := cmd.Flags().GetBool("replace")
+
+		file, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		conn, err := connect(cmd.Context())
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		options := db.BulkOptions{BatchSize: batchSize, Replace: replace, Progress: reportProgress(cmd)}
+		count, err := api.NewStore(conn).BulkLoadAvailability(cmd.Context(), util.ReadAvailabilityCSV(file), options)
+		if err != nil {
+			return fmt.Errorf("import stopped after %d nights: %w", count, err)
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "imported %d nights\n", count)
+		return nil
+	},
+}
+
+var calendarRollCmd = &cobra.Command{
+	Use:   "roll",
+	Short: "Archive past nights and extend every calendar to the availability horizon once",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		horizon, _ := cmd.Flags().GetInt32("horizon")
+		if !cmd.Flags().Changed("horizon") {
+			horizon = config.AvailabilityHorizonDays
+		}
+		if horizon < 1 {
+			return fmt.Errorf("--horizon must be positive")
+		}
+
+		conn, err := connect(cmd.Context())
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		store := api.NewStore(conn)
+		ran, err := store.RunJobOnce(cmd.Context(), store.HorizonJob(horizon, 0))
+		if err != nil {
+			return err
+		}
+		if !ran {
+			return fmt.Errorf("the availability horizon job is already running on another replica")
+		}
+		return nil
+	},
+}
+
+var calendarPartitionsCmd = &cobra.Command{
+	Use:   "partitions",
+	Short: "List the monthly partitions of the calendars, optionally maintaining them once first",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		maintain, _ := cmd.Flags().GetBool("maintain")
+		months, _ := cmd.Flags().GetInt("months")
+		if !cmd.Flags().Changed("months") {
+			months = config.PartitionMonthsAhead
+		}
+		if months < 0 {
+			return fmt.Errorf("--months must not be negative")
+		}
+
+		conn, err := connect(cmd.Context())
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		store := api.NewStore(conn)
+		if maintain {
+			ran, err := store.RunJobOnce(cmd.Context(), store.PartitionJob(months, 0))
+			if err != nil {
+				return err
+			}
+			if !ran {
+				return fmt.Errorf("the partition maintenance job is already running on another replica")
+			}
+		}
+
+		partitions, err := store.ListRoomAvailabilityPartitions(cmd.Context())
+		if err != nil {
+			return err
+		}
+		for _, partition := range partitions {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\n", partition.Name, partition.Bound)
+		}
+		return nil
+	},
+}
+
+var calendarCheckStatsCmd = &cobra.Command{
+	Use:   "check-stats",
+	Short: "Report the months whose statistics differ from their calendar, optionally repairing them",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repair, _ := cmd.Flags().GetBool("repair")
+
+		conn, err := connect(cmd.Context())
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		inconsistent, err := api.NewStore(conn).CheckMonthStats(cmd.Context(), repair)
+		if err != nil {
+			return err
+		}
+		for _, month := range inconsistent {
+			fmt.Fprintf(cmd.OutOrStdout(), "room %d: %s\n", month.RoomID, month.Month.Time.Format("2006-01"))
+		}
+
+		switch {
+		case len(inconsistent) == 0:
+			fmt.Fprintln(cmd.OutOrStdout(), "monthly statistics are consistent")
+		case repair:
+			fmt.Fprintf(cmd.OutOrStdout(), "repaired %d months\n", len(inconsistent))
+		default:
+			return fmt.Errorf("%d months have inconsistent statistics; run again with --repair to fix them", len(inconsistent))
+		}
+		return nil
+	},
+}
+
+func init() {
+	calendarCheckStatsCmd.Flags().Bool("repair", false, "recompute the statistics of inconsistent months")
+
+	calendarPartitionsCmd.Flags().Bool("maintain", false, "create the partitions of the coming months and archive those of past months first")
+	calendarPartitionsCmd.Flags().Int("months", 0, "number of months ahead to create partitions for (defaults to PARTITION_MONTHS_AHEAD)")
+
+	calendarRollCmd.Flags().Int32("horizon", 0, "number of nights from today to keep in every calendar (defaults to AVAILABILITY_HORIZON_DAYS)")
+
+	calendarImportCmd.Flags().Int("batch-size", db.DefaultBulkBatchSize, "number of nights copied per batch")
+	calendarImportCmd.Flags().Bool("replace", false, "overwrite nights that already exist instead of failing")
+
+	calendarSetCmd.Flags().Int32("room", 0, "ID of the room")
+	calendarSetCmd.Flags().String("from", "", "first night to set, as YYYY-MM-DD")
+	calendarSetCmd.Flags().String("to", "", "last night to set, as YYYY-MM-DD (defaults to --from)")
+	calendarSetCmd.Flags().Int32("rate", 0, "nightly rate")
+	calendarSetCmd.Flags().Bool("available", true, "whether the nights are available for booking")
+	calendarSetCmd.MarkFlagRequired("room")
+	calendarSetCmd.MarkFlagRequired("from")
+	calendarSetCmd.MarkFlagRequired("rate")
+
+	calendarCmd.AddCommand(calendarSetCmd, calendarImportCmd, calendarRollCmd, calendarPartitionsCmd, calendarCheckStatsCmd)
+	rootCmd.AddCommand(calendarCmd)
+}
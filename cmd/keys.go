@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/vivek-344/airbnb-api/api"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// keysCmd groups the API key subcommands.
+var keysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "Manage API keys",
+}
+
+var keysCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an API key and print it once",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("name")
+
+		key, hash, err := api.GenerateAPIKey()
+		if err != nil {
+			return err
+		}
+
+		conn, err := connect(cmd.Context())
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		_, err = api.NewStore(conn).CreateAPIKey(cmd.Context(), db.CreateAPIKeyParams{Name: name, KeyHash: hash})
+		if err != nil {
+			return fmt.Errorf("cannot create api key: %w", err)
+		}
+
+		// Only the hash is stored, so this is the only time the key can be shown.
+		fmt.Fprintln(cmd.OutOrStdout(), key)
+		return nil
+	},
+}
+
+func init() {
+	keysCreateCmd.Flags().String("name", "", "name identifying the owner of the key")
+	keysCreateCmd.MarkFlagRequired("name")
+
+	keysCmd.AddCommand(keysCreateCmd)
+	rootCmd.AddCommand(keysCmd)
+}
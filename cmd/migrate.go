@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/vivek-344/airbnb-api/db/migration"
+)
+
+// migrateCmd groups the schema migration subcommands.
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Apply or inspect the embedded database migrations",
+}
+
+var migrateUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Apply all pending migrations",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withMigrator(func(migrator *migration.Migrator) error {
+			return migrator.Up()
+		})
+	},
+}
+
+var migrateDownCmd = &cobra.Command{
+	Use:   "down [N | --all]",
+	Short: "Roll back the last N migrations, or all of them with --all",
+	Long: "Roll back the last N migrations. Rolling back all of them drops every table, including the append-only\n" +
+		"audit log and calendar history, so it requires --all instead of N.",
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		steps := 0
+		switch {
+		case all && len(args) == 1:
+			return fmt.Errorf("either N or --all must be given, not both")
+		case len(args) == 1:
+			var err error
+			steps, err = strconv.Atoi(args[0])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[0])
+			}
+		case !all:
+			return fmt.Errorf("the number of migrations to roll back is required; use --all to roll back every migration")
+		}
+
+		return withMigrator(func(migrator *migration.Migrator) error {
+			return migrator.Down(steps)
+		})
+	},
+}
+
+var migrateVersionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the current schema version",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withMigrator(func(migrator *migration.Migrator) error {
+			version, dirty, err := migrator.Version()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "version: %d, dirty: %t\n", version, dirty)
+			return nil
+		})
+	},
+}
+
+var migrateForceCmd = &cobra.Command{
+	Use:   "force VERSION",
+	Short: "Set the schema version after fixing a failed migration by hand",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[0])
+		}
+
+		return withMigrator(func(migrator *migration.Migrator) error {
+			return migrator.Force(version)
+		})
+	},
+}
+
+func init() {
+	migrateDownCmd.Flags().Bool("all", false, "roll back every migration, dropping all tables")
+
+	migrateCmd.AddCommand(migrateUpCmd, migrateDownCmd, migrateVersionCmd, migrateForceCmd)
+	rootCmd.AddCommand(migrateCmd)
+}
+
+// withMigrator runs fn with a migrator for the configured database.
+func withMigrator(fn func(migrator *migration.Migrator) error) error {
+	migrator, err := migration.New(config.DBSource)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	return fn(migrator)
+}
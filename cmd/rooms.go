@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/vivek-344/airbnb-api/api"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// roomsCmd groups the room administration subcommands.
+var roomsCmd = &cobra.Command{
+	Use:   "rooms",
+	Short: "List and create rooms",
+}
+
+var roomsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List rooms ordered by ID",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt32("limit")
+		offset, _ := cmd.Flags().GetInt32("offset")
+
+		conn, err := connect(cmd.Context())
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		rooms, err := api.NewStore(conn).ListRooms(cmd.Context(), db.ListRoomsParams{Limit: limit, Offset: offset})
+		if err != nil {
+			return fmt.Errorf("cannot list rooms: %w", err)
+		}
+
+		writer := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+		fmt.Fprintln(writer, "ROOM ID\tMAX GUESTS\tBALCONY\tFRIDGE\tINDOOR POOL\tGAMING CONSOLE\tDEFAULT RATE")
+		for _, room := range rooms {
+			fmt.Fprintf(writer, "%d\t%d\t%t\t%t\t%t\t%t\t%d\n",
+				room.RoomID, room.MaxGuests, room.Balcony, room.Fridge, room.IndoorPool, room.GamingConsole, room.DefaultRate)
+		}
+		return writer.Flush()
+	},
+}
+
+var roomsCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a room",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		flags := cmd.Flags()
+		var arg db.CreateRoomParams
+		arg.RoomID, _ = flags.GetInt32("id")
+		arg.MaxGuests, _ = flags.GetInt32("max-guests")
+		arg.Balcony, _ = flags.GetBool("balcony")
+		arg.Fridge, _ = flags.GetBool("fridge")
+		arg.IndoorPool, _ = flags.GetBool("indoor-pool")
+		arg.GamingConsole, _ = flags.GetBool("gaming-console")
+		arg.DefaultRate, _ = flags.GetInt32("default-rate")
+
+		if arg.RoomID < 1 || arg.MaxGuests < 1 || arg.DefaultRate < 1 {
+			return fmt.Errorf("--id, --max-guests and --default-rate must be positive")
+		}
+
+		conn, err := connect(cmd.Context())
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		room, err := api.NewStore(conn).CreateRoom(cmd.Context(), arg)
+		if err != nil {
+			return fmt.Errorf("cannot create room: %w", err)
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "created room %d\n", room.RoomID)
+		return nil
+	},
+}
+
+func init() {
+	roomsListCmd.Flags().Int32("limit", 50, "maximum number of rooms to list")
+	roomsListCmd.Flags().Int32("offset", 0, "number of rooms to skip")
+
+	roomsCreateCmd.Flags().Int32("id", 0, "ID of the room")
+	roomsCreateCmd.Flags().Int32("max-guests", 0, "maximum number of guests")
+	roomsCreateCmd.Flags().Bool("balcony", false, "the room has a balcony")
+	roomsCreateCmd.Flags().Bool("fridge", false, "the room has a mini fridge")
+	roomsCreateCmd.Flags().Bool("indoor-pool", false, "the room has an indoor pool")
+	roomsCreateCmd.Flags().Bool("gaming-console", false, "the room has a gaming console")
+	roomsCreateCmd.Flags().Int32("default-rate", 5000, "nightly rate of the nights added by the availability horizon job")
+	roomsCreateCmd.MarkFlagRequired("id")
+	roomsCreateCmd.MarkFlagRequired("max-guests")
+
+	roomsCmd.AddCommand(roomsListCmd, roomsCreateCmd)
+	rootCmd.AddCommand(roomsCmd)
+}
@@ -0,0 +1,110 @@
+// Package cmd implements the command line interface of the room service.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"os/user"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/spf13/cobra"
+	"github.com/vivek-344/airbnb-api/api"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+	"github.com/vivek-344/airbnb-api/util"
+)
+
+// dateLayout is the format of dates accepted on the command line.
+const dateLayout = "2006-01-02"
+
+var (
+	// configPath is the directory containing the `app.env` configuration file.
+	configPath string
+
+	// config is loaded before any subcommand runs.
+	config util.Config
+)
+
+// rootCmd is the entry point of the command line interface.
+var rootCmd = &cobra.Command{
+	Use:           "airbnb-api",
+	Short:         "Performance metrics API for Airbnb rooms",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		var err error
+		config, err = util.LoadConfig(configPath)
+		if err != nil {
+			return fmt.Errorf("cannot load config: %w", err)
+		}
+
+		// Route all log output, including the standard `log` package, through the structured logger.
+		logger, err := util.NewLogger(os.Stderr, config.LogLevel, config.LogFormat)
+		if err != nil {
+			return fmt.Errorf("cannot create logger: %w", err)
+		}
+		slog.SetDefault(logger)
+
+		// Gin only prints its route table in debug mode.
+		if config.LogLevel != "debug" {
+			gin.SetMode(gin.ReleaseMode)
+		}
+
+		// Writes made by the command are audited as made by the operator running it.
+		cmd.SetContext(api.WithActor(cmd.Context(), cliActor()))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", ".", "directory containing the app.env file")
+}
+
+// Execute runs the command selected by the command line arguments and exits on failure.
+// Commands are canceled on SIGINT or SIGTERM, so that they can stop cleanly.
+func Execute() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
+	if err != nil {
+		slog.Error("command failed", slog.Any("error", err))
+		os.Exit(1)
+	}
+}
+
+// cliActor returns the actor recorded in the audit log for writes made from the command line.
+func cliActor() string {
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		return "cli:" + current.Username
+	}
+	return "cli"
+}
+
+// connect establishes a connection pool to the configured database, tracing every query.
+func connect(ctx context.Context) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(config.DBSource)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse database source: %w", err)
+	}
+	poolConfig.ConnConfig.Tracer = api.NewQueryTracer()
+
+	conn, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to the database: %w", err)
+	}
+	return conn, nil
+}
+
+// reportProgress returns a bulk load callback printing the progress of the command.
+func reportProgress(cmd *cobra.Command) func(db.BulkProgress) {
+	return func(progress db.BulkProgress) {
+		rate := float64(progress.Rows) / progress.Elapsed.Seconds()
+		fmt.Fprintf(cmd.ErrOrStderr(), "batch %d: %d rows in %s (%.0f rows/s)\n",
+			progress.Batches, progress.Rows, progress.Elapsed.Round(time.Millisecond), rate)
+	}
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vivek-344/airbnb-api/api"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+	"github.com/vivek-344/airbnb-api/util"
+)
+
+// seedOptions holds the flags of the seed command.
+var seedOptions = util.DefaultSeedOptions()
+
+// seedCmd populates the database with generated test data. It is never run implicitly.
+var seedCmd = &cobra.Command{
+	Use:   "seed",
+	Short: "Populate the database with generated test rooms and availability",
+	Long: "Populate the database with generated test rooms and availability.\n" +
+		"The data only depends on the flags, and existing rows are overwritten unless they already hold the same\n" +
+		"values, so reruns with the same flags write nothing. Calendars start today unless --start is given: pass it to reproduce data on another day, as a\n" +
+		"rerun starting on a later day shifts every calendar and leaves the earlier nights in place.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		options := seedOptions
+		start, _ := cmd.Flags().GetString("start")
+		if start != "" {
+			var err error
+			options.StartDate, err = time.Parse(dateLayout, start)
+			if err != nil {
+				return fmt.Errorf("invalid --start date: %w", err)
+			}
+		} else {
+			fmt.Fprintf(cmd.ErrOrStderr(), "calendars start today; pass --start %s to reproduce this data on another day\n",
+				options.StartDate.Format(dateLayout))
+		}
+		months, _ := cmd.Flags().GetIntSlice("high-season")
+		options.HighSeasonMonths = nil
+		for _, month := range months {
+			if month < 1 || month > 12 {
+				return fmt.Errorf("invalid high season month %d", month)
+			}
+			options.HighSeasonMonths = append(options.HighSeasonMonths, time.Month(month))
+		}
+		if err := options.Validate(); err != nil {
+			return err
+		}
+
+		conn, err := connect(cmd.Context())
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		batchSize, _ := cmd.Flags().GetInt("batch-size")
+		bulk := db.BulkOptions{BatchSize: batchSize, Progress: reportProgress(cmd)}
+		result, err := util.Seed(cmd.Context(), api.NewStore(conn), options, bulk)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "seeded %d rooms and %d nights\n", result.Rooms, result.Nights)
+		return nil
+	},
+}
+
+func init() {
+	defaults := util.DefaultSeedOptions()
+	var highSeason []int
+	for _, month := range defaults.HighSeasonMonths {
+		highSeason = append(highSeason, int(month))
+	}
+
+	flags := seedCmd.Flags()
+	flags.Int64Var(&seedOptions.Seed, "seed", defaults.Seed, "seed of the random generator")
+	flags.IntVar(&seedOptions.Rooms, "rooms", defaults.Rooms, "number of rooms")
+	flags.Int32Var(&seedOptions.FirstRoomID, "first-room-id", defaults.FirstRoomID, "ID of the first room")
+	flags.String("start", "", "first night of the calendars, as YYYY-MM-DD (defaults to today, so required for reproducible runs)")
+	flags.IntVar(&seedOptions.HorizonDays, "horizon", defaults.HorizonDays, "number of nights per calendar")
+	flags.Int32Var(&seedOptions.MinRate, "min-rate", defaults.MinRate, "lowest base nightly rate")
+	flags.Int32Var(&seedOptions.MaxRate, "max-rate", defaults.MaxRate, "highest base nightly rate")
+	flags.Float64Var(&seedOptions.Occupancy, "occupancy", defaults.Occupancy, "fraction of booked nights outside high season")
+	flags.Float64Var(&seedOptions.HighSeasonOccupancy, "high-season-occupancy", defaults.HighSeasonOccupancy, "fraction of booked nights during high season")
+	flags.IntSlice("high-season", highSeason, "months of the high season (1-12)")
+	flags.Float64Var(&seedOptions.HighSeasonMarkup, "high-season-markup", defaults.HighSeasonMarkup, "rate increase during high season")
+	flags.Float64Var(&seedOptions.WeekendMarkup, "weekend-markup", defaults.WeekendMarkup, "rate increase on Friday and Saturday nights")
+	flags.Float64Var(&seedOptions.WeekendBooking, "weekend-booking", defaults.WeekendBooking, "chance that a free weekend night gets a short booking")
+	flags.Float64Var(&seedOptions.MeanStayNights, "mean-stay", defaults.MeanStayNights, "average length of a booking in nights")
+	flags.Int("batch-size", db.DefaultBulkBatchSize, "number of nights copied per batch")
+	flags.Float64Var(&seedOptions.LongStayChance, "long-stay-chance", defaults.LongStayChance, "chance that a booking is a long block of several weeks")
+
+	rootCmd.AddCommand(seedCmd)
+}
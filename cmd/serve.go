@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+
+	"github.com/spf13/cobra"
+	"github.com/vivek-344/airbnb-api/api"
+	"github.com/vivek-344/airbnb-api/db/migration"
+)
+
+// serveCmd starts the HTTP server. It never writes test data to the database.
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Start the HTTP server",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+
+		// Apply pending migrations before serving, if enabled.
+		if config.AutoMigrate {
+			if err := migration.AutoMigrate(ctx, config.DBSource); err != nil {
+				return fmt.Errorf("cannot migrate database: %w", err)
+			}
+		}
+
+		// Install the tracer provider so that requests and queries are traced.
+		shutdownTracing, err := api.SetupTracing(config.TracingExporter, config.TracingFile)
+		if err != nil {
+			return fmt.Errorf("cannot setup tracing: %w", err)
+		}
+		defer shutdownTracing(context.Background())
+
+		// Establish a connection pool to the PostgreSQL database.
+		conn, err := connect(ctx)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		// Initialize the database store with the connection pool.
+		store := api.NewStore(conn)
+
+		// Keep the calendar of every room rolling forward in the background.
+		if config.AvailabilityJobInterval > 0 {
+			go store.RunJob(ctx, store.HorizonJob(config.AvailabilityHorizonDays, config.AvailabilityJobInterval))
+		}
+
+		// Create the calendar partitions of the coming months and archive those of the past ones.
+		if config.PartitionJobInterval > 0 {
+			go store.RunJob(ctx, store.PartitionJob(config.PartitionMonthsAhead, config.PartitionJobInterval))
+		}
+
+		// Publish the events written to the outbox by writes, in order per room.
+		if config.OutboxJobInterval > 0 {
+			sinks, closeSinks, err := store.OpenOutboxSinks(config.OutboxSinks, config.OutboxFile)
+			if err != nil {
+				return fmt.Errorf("cannot open outbox sinks: %w", err)
+			}
+			defer closeSinks()
+			go store.RunJob(ctx, store.OutboxJob(sinks, config.OutboxRetention, config.OutboxJobInterval))
+		}
+
+		// Deliver the webhooks queued by the outbox, retrying failed deliveries.
+		if config.WebhookJobInterval > 0 {
+			options := api.DefaultWebhookOptions()
+			options.MaxAttempts = config.WebhookMaxAttempts
+			options.Timeout = config.WebhookTimeout
+			go store.RunJob(ctx, store.WebhookJob(options, config.WebhookJobInterval))
+		}
+
+		// Stream the published events to the clients of this replica.
+		go store.RunEventHub(ctx, config.EventPollInterval)
+
+		// Serve rooms, calendars, search and quotes to internal services over gRPC, if enabled.
+		if config.GRPCServerAddress != "" {
+			listener, err := net.Listen("tcp", config.GRPCServerAddress)
+			if err != nil {
+				return fmt.Errorf("cannot start gRPC server: %w", err)
+			}
+			grpcServer := api.NewGRPCServer(*store)
+			defer grpcServer.Stop()
+			go func() {
+				if err := grpcServer.Serve(listener); err != nil {
+					slog.Error("gRPC server stopped", slog.Any("error", err))
+				}
+			}()
+		}
+
+		// Create a new API server with the initialized store.
+		server := api.NewServer(*store)
+
+		// Serve on the configured address until interrupted, then let the requests in flight complete
+		// before the deferred calls stop the background jobs and close the connections.
+		if err := server.Start(ctx, config.ServerAddress); err != nil {
+			return fmt.Errorf("cannot start server: %w", err)
+		}
+		slog.Info("server stopped")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+}
@@ -0,0 +1,54 @@
+package memdb
+
+import (
+	"bytes"
+	"context"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+func (q *Queries) CreateAPIKey(ctx context.Context, arg db.CreateAPIKeyParams) (db.ApiKey, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, key := range q.apiKeys {
+		if key.Name == arg.Name {
+			return db.ApiKey{}, pgError("23505", "api_key_name_key", `duplicate key value violates unique constraint "api_key_name_key"`)
+		}
+		if bytes.Equal(key.KeyHash, arg.KeyHash) {
+			return db.ApiKey{}, pgError("23505", "api_key_key_hash_key", `duplicate key value violates unique constraint "api_key_key_hash_key"`)
+		}
+	}
+
+	// Like a sequence, the ID is consumed even if the transaction is rolled back.
+	key := db.ApiKey{
+		ID:        q.nextAPIKeyID,
+		Name:      arg.Name,
+		KeyHash:   slices.Clone(arg.KeyHash),
+		CreatedAt: q.now(),
+	}
+	q.nextAPIKeyID++
+	q.apiKeys = append(q.apiKeys, key)
+	return key, nil
+}
+
+func (q *Queries) GetAPIKeyByHash(ctx context.Context, keyHash []byte) (db.ApiKey, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, key := range q.apiKeys {
+		if bytes.Equal(key.KeyHash, keyHash) {
+			return key, nil
+		}
+	}
+	return db.ApiKey{}, pgx.ErrNoRows
+}
+
+func (q *Queries) ListAPIKeys(ctx context.Context) ([]db.ApiKey, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return append([]db.ApiKey{}, q.apiKeys...), nil
+}
@@ -0,0 +1,73 @@
+package memdb
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// checkJSON enforces the jsonb type of a column. NULL is represented by a nil slice.
+func checkJSON(value []byte) error {
+	if value != nil && !json.Valid(value) {
+		return pgError("22P02", "", "invalid input syntax for type json")
+	}
+	return nil
+}
+
+func (q *Queries) CreateAuditEvent(ctx context.Context, arg db.CreateAuditEventParams) (db.AuditEvent, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if err := checkJSON(arg.Before); err != nil {
+		return db.AuditEvent{}, err
+	}
+	if err := checkJSON(arg.After); err != nil {
+		return db.AuditEvent{}, err
+	}
+
+	// Like a sequence, the ID is consumed even if the transaction is rolled back.
+	event := db.AuditEvent{
+		ID:         q.nextAuditEventID,
+		OccurredAt: q.now(),
+		Actor:      arg.Actor,
+		Action:     arg.Action,
+		RoomID:     arg.RoomID,
+		Date:       arg.Date,
+		Before:     slices.Clone(arg.Before),
+		After:      slices.Clone(arg.After),
+		RequestID:  arg.RequestID,
+	}
+	q.nextAuditEventID++
+	q.auditEvents = append(q.auditEvents, event)
+	return event, nil
+}
+
+func (q *Queries) ListRoomAuditEvents(ctx context.Context, arg db.ListRoomAuditEventsParams) ([]db.AuditEvent, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if arg.RowLimit < 0 {
+		return nil, pgError("2201W", "", "LIMIT must not be negative")
+	}
+	if arg.RowOffset < 0 {
+		return nil, pgError("2201X", "", "OFFSET must not be negative")
+	}
+
+	items := []db.AuditEvent{}
+	for _, event := range slices.Backward(q.auditEvents) {
+		switch {
+		case !event.RoomID.Valid || event.RoomID.Int32 != arg.RoomID:
+		case arg.Date.Valid && (!event.Date.Valid || dateKey(event.Date) != dateKey(arg.Date)):
+		case arg.Actor.Valid && event.Actor != arg.Actor.String:
+		case arg.Since.Valid && event.OccurredAt.Before(arg.Since.Time):
+		case arg.Until.Valid && !event.OccurredAt.Before(arg.Until.Time):
+		default:
+			items = append(items, event)
+		}
+	}
+	start := min(int(arg.RowOffset), len(items))
+	end := min(start+int(arg.RowLimit), len(items))
+	return items[start:end], nil
+}
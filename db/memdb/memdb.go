@@ -0,0 +1,139 @@
+// Package memdb implements the sqlc Querier in memory, for tests that must not depend on PostgreSQL.
+//
+// Every query follows the semantics of its SQL counterpart in `db/query`: the same rows are returned,
+// constraint violations fail with the *pgconn.PgError PostgreSQL would raise, and missing rows
+// with pgx.ErrNoRows. Queries without an ORDER BY return rows in key order.
+package memdb
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"math/big"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// dateLayout formats the dates used as keys of the availability calendar. It sorts chronologically.
+const dateLayout = "2006-01-02"
+
+// metricsWindow is the number of nights from today covered by the rate queries.
+const metricsWindow = 30
+
+// listLimit is the number of rows returned by the availability listing queries.
+const listLimit = 30
+
+// Queries holds the tables in memory. It is safe for concurrent use.
+type Queries struct {
+	mu  sync.Mutex
+	now func() time.Time
+
+	// txMu serializes transactions run with ExecTx.
+	txMu sync.Mutex
+
+	rooms        map[int32]db.Room
+	nights       map[int32]map[string]db.RoomAvailability
+	archive      map[int32]map[string]db.RoomAvailabilityHistory
+	partitions   map[string]bool // First nights of the months with their own partition; their nights stay in nights.
+	apiKeys      []db.ApiKey
+	nextAPIKeyID int64
+
+	auditEvents      []db.AuditEvent
+	nextAuditEventID int64
+
+	webhookSubscriptions      []db.WebhookSubscription
+	nextWebhookSubscriptionID int64
+	webhookDeliveries         []db.WebhookDelivery
+	nextWebhookDeliveryID     int64
+
+	outbox             []db.Outbox
+	nextOutboxID       int64
+	nextOutboxPosition int64
+}
+
+var _ db.Querier = (*Queries)(nil)
+
+// New creates empty tables whose CURRENT_DATE is the current UTC date.
+func New() *Queries {
+	return NewWithClock(time.Now)
+}
+
+// NewWithClock creates empty tables whose CURRENT_DATE is the UTC date of now.
+func NewWithClock(now func() time.Time) *Queries {
+	return &Queries{
+		now:          now,
+		rooms:        map[int32]db.Room{},
+		nights:       map[int32]map[string]db.RoomAvailability{},
+		archive:      map[int32]map[string]db.RoomAvailabilityHistory{},
+		partitions:   map[string]bool{},
+		nextAPIKeyID: 1,
+
+		nextAuditEventID: 1,
+
+		nextWebhookSubscriptionID: 1,
+		nextWebhookDeliveryID:     1,
+
+		nextOutboxID:       1,
+		nextOutboxPosition: 1,
+	}
+}
+
+// ExecTx calls fn in a transaction: if fn fails, every change it made is rolled back.
+// Transactions are serialized with each other, but not isolated from queries run outside of them.
+func (q *Queries) ExecTx(ctx context.Context, fn func(db.Querier) error) error {
+	q.txMu.Lock()
+	defer q.txMu.Unlock()
+
+	q.mu.Lock()
+	rooms, nights, apiKeys, auditEvents := maps.Clone(q.rooms), map[int32]map[string]db.RoomAvailability{}, slices.Clone(q.apiKeys), slices.Clone(q.auditEvents)
+	partitions := maps.Clone(q.partitions)
+	webhookSubscriptions, webhookDeliveries := slices.Clone(q.webhookSubscriptions), slices.Clone(q.webhookDeliveries)
+	outbox := slices.Clone(q.outbox)
+	for roomID, calendar := range q.nights {
+		nights[roomID] = maps.Clone(calendar)
+	}
+	archive := map[int32]map[string]db.RoomAvailabilityHistory{}
+	for roomID, calendar := range q.archive {
+		archive[roomID] = maps.Clone(calendar)
+	}
+	q.mu.Unlock()
+
+	err := fn(q)
+	if err != nil {
+		q.mu.Lock()
+		q.rooms, q.nights, q.archive, q.partitions, q.apiKeys, q.auditEvents = rooms, nights, archive, partitions, apiKeys, auditEvents
+		q.webhookSubscriptions, q.webhookDeliveries = webhookSubscriptions, webhookDeliveries
+		q.outbox = outbox
+		q.mu.Unlock()
+	}
+	return err
+}
+
+// today returns CURRENT_DATE.
+func (q *Queries) today() time.Time {
+	now := q.now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// dateKey returns the calendar key of a date.
+func dateKey(date pgtype.Date) string {
+	return date.Time.Format(dateLayout)
+}
+
+// pgError returns the error PostgreSQL raises for a violated constraint.
+func pgError(code, constraint, message string) error {
+	return &pgconn.PgError{Severity: "ERROR", Code: code, ConstraintName: constraint, Message: message}
+}
+
+// errNullAverage is the error returned by pgx when an average over no rows is scanned into a float64.
+var errNullAverage = errors.New("can't scan into dest[0] (col: avg): cannot scan NULL into *float64")
+
+// numeric converts a fixed point value with the given number of decimals to a NUMERIC.
+func numeric(value int64, decimals int32) pgtype.Numeric {
+	return pgtype.Numeric{Int: big.NewInt(value), Exp: -decimals, Valid: true}
+}
@@ -0,0 +1,577 @@
+package memdb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// today is the CURRENT_DATE of the test tables.
+var today = time.Date(2024, 6, 28, 0, 0, 0, 0, time.UTC)
+
+func newTestQueries(t *testing.T) *Queries {
+	t.Helper()
+	queries := NewWithClock(func() time.Time { return today.Add(15 * time.Hour) })
+
+	_, err := queries.CreateRoom(context.Background(), db.CreateRoomParams{RoomID: 1, MaxGuests: 2, DefaultRate: 5000})
+	require.NoError(t, err)
+	return queries
+}
+
+func night(day int) pgtype.Date {
+	return pgtype.Date{Time: today.AddDate(0, 0, day), Valid: true}
+}
+
+func requireCode(t *testing.T, err error, code string) {
+	t.Helper()
+	var pgErr *pgconn.PgError
+	require.ErrorAs(t, err, &pgErr)
+	require.Equal(t, code, pgErr.Code)
+}
+
+func TestRoomConstraints(t *testing.T) {
+	queries := newTestQueries(t)
+	ctx := context.Background()
+
+	_, err := queries.CreateRoom(ctx, db.CreateRoomParams{RoomID: 1, MaxGuests: 4, DefaultRate: 5000})
+	requireCode(t, err, "23505")
+
+	_, err = queries.CreateRoom(ctx, db.CreateRoomParams{RoomID: 2, MaxGuests: 4})
+	requireCode(t, err, "23514")
+
+	_, err = queries.UpdateMaxGuests(ctx, db.UpdateMaxGuestsParams{RoomID: 3, MaxGuests: 4})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	_, err = queries.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{RoomID: 3, Date: night(0), NightRate: 5000})
+	requireCode(t, err, "23503")
+
+	_, err = queries.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{RoomID: 1, Date: night(0), NightRate: 5000})
+	require.NoError(t, err)
+	_, err = queries.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{RoomID: 1, Date: night(0), NightRate: 6000})
+	requireCode(t, err, "23505")
+
+	// The room cannot be deleted while it has nights.
+	requireCode(t, queries.DeleteRoom(ctx, 1), "23503")
+	deleted, err := queries.DeleteAllAvailabilityForRoom(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, []pgtype.Date{night(0)}, deleted)
+	require.NoError(t, queries.DeleteRoom(ctx, 1))
+
+	_, err = queries.GetRoom(ctx, 1)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func TestListRooms(t *testing.T) {
+	queries := newTestQueries(t)
+	ctx := context.Background()
+
+	for _, roomID := range []int32{5, 3, 4, 2} {
+		_, err := queries.UpsertRoom(ctx, db.UpsertRoomParams{RoomID: roomID, MaxGuests: 2, DefaultRate: 5000})
+		require.NoError(t, err)
+	}
+
+	rooms, err := queries.ListRooms(ctx, db.ListRoomsParams{Limit: 2, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, rooms, 2)
+	require.Equal(t, int32(2), rooms[0].RoomID)
+	require.Equal(t, int32(3), rooms[1].RoomID)
+
+	rooms, err = queries.ListRooms(ctx, db.ListRoomsParams{Limit: 10, Offset: 10})
+	require.NoError(t, err)
+	require.Empty(t, rooms)
+
+	_, err = queries.ListRooms(ctx, db.ListRoomsParams{Limit: -1})
+	requireCode(t, err, "2201W")
+}
+
+func TestUpsertRoom(t *testing.T) {
+	queries := newTestQueries(t)
+	ctx := context.Background()
+	room, err := queries.GetRoom(ctx, 1)
+	require.NoError(t, err)
+
+	// Upserting the same fields leaves the room as it is.
+	args := db.UpsertRoomParams{RoomID: 1, MaxGuests: 2, DefaultRate: 5000}
+	unchanged, err := queries.UpsertRoom(ctx, args)
+	require.NoError(t, err)
+	require.Equal(t, room, unchanged)
+
+	args.MaxGuests = 3
+	updated, err := queries.UpsertRoom(ctx, args)
+	require.NoError(t, err)
+	require.Equal(t, room.Version+1, updated.Version)
+}
+
+func TestRates(t *testing.T) {
+	queries := newTestQueries(t)
+	ctx := context.Background()
+
+	// Only nights from today and within 30 days count.
+	for day, rate := range map[int]int32{-1: 1000, 0: 6000, 10: 5000, 29: 8000, 30: 9000} {
+		_, err := queries.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{RoomID: 1, Date: night(day), NightRate: rate})
+		require.NoError(t, err)
+	}
+
+	average, err := queries.GetAverageRate(ctx, 1)
+	require.NoError(t, err)
+	require.InDelta(t, 6333.33, average, 0.01)
+
+	highest, err := queries.GetMaximumRate(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, int32(8000), highest)
+
+	lowest, err := queries.GetMinimumRate(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, int32(5000), lowest)
+
+	// Aggregates over no rows behave like their SQL counterparts.
+	_, err = queries.GetAverageRate(ctx, 2)
+	require.Error(t, err)
+	_, err = queries.GetMaximumRate(ctx, 2)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func TestGetAvailabilityPercentage(t *testing.T) {
+	queries := newTestQueries(t)
+	ctx := context.Background()
+
+	// June 28-30: 2 of 3 available; July 1-2: 1 of 2 available.
+	for day, available := range []bool{true, false, true, true, false} {
+		_, err := queries.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{RoomID: 1, Date: night(day), IsAvailable: available, NightRate: 5000})
+		require.NoError(t, err)
+	}
+
+	rows, err := queries.GetAvailabilityPercentage(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	require.Equal(t, numeric(2024, 0), rows[0].Year)
+	require.Equal(t, numeric(6, 0), rows[0].Month)
+	require.Equal(t, numeric(6667, 2), rows[0].AvailabilityPercentage)
+	require.Equal(t, numeric(7, 0), rows[1].Month)
+	require.Equal(t, numeric(5000, 2), rows[1].AvailabilityPercentage)
+}
+
+func TestListAvailableDates(t *testing.T) {
+	queries := newTestQueries(t)
+	ctx := context.Background()
+
+	for day := range 80 {
+		_, err := queries.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{RoomID: 1, Date: night(day), IsAvailable: day%2 == 0, NightRate: 5000})
+		require.NoError(t, err)
+	}
+
+	nights, err := queries.ListRoomAvailability(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, nights, 30)
+
+	dates, err := queries.ListAvailableDates(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, dates, 30)
+	require.Equal(t, night(58), dates[29])
+}
+
+func TestCopyRoomAvailabilityIsAtomic(t *testing.T) {
+	queries := newTestQueries(t)
+	ctx := context.Background()
+
+	rows := []db.CopyRoomAvailabilityParams{
+		{RoomID: 1, Date: night(0), NightRate: 5000},
+		{RoomID: 1, Date: night(1), NightRate: 5000},
+		{RoomID: 1, Date: night(0), NightRate: 5000},
+	}
+	_, err := queries.CopyRoomAvailability(ctx, rows)
+	requireCode(t, err, "23505")
+
+	count, err := queries.GetDateCount(ctx, 1)
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
+
+func TestUpsertRoomAvailabilityNights(t *testing.T) {
+	queries := newTestQueries(t)
+	ctx := context.Background()
+
+	_, err := queries.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{RoomID: 1, Date: night(0), IsAvailable: true, NightRate: 5000})
+	require.NoError(t, err)
+	booked, err := queries.UpdateRoomAvailability(ctx, db.UpdateRoomAvailabilityParams{RoomID: 1, Date: night(0), NightRate: 5000, Version: 1})
+	require.NoError(t, err)
+	require.True(t, booked.BookedAt.Valid)
+
+	// A night cannot be written twice by the same statement.
+	_, err = queries.UpsertRoomAvailabilityNights(ctx, db.UpsertRoomAvailabilityNightsParams{
+		RoomIds:     []int32{1, 1},
+		Dates:       []pgtype.Date{night(1), night(1)},
+		IsAvailable: []bool{true, true},
+		NightRates:  []int32{5000, 6000},
+	})
+	requireCode(t, err, "21000")
+
+	// The existing night gets a new version and keeps its booking time, the other one is inserted.
+	written, err := queries.UpsertRoomAvailabilityNights(ctx, db.UpsertRoomAvailabilityNightsParams{
+		RoomIds:     []int32{1, 1},
+		Dates:       []pgtype.Date{night(0), night(1)},
+		IsAvailable: []bool{false, true},
+		NightRates:  []int32{6000, 6000},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []db.UpsertRoomAvailabilityNightsRow{{RoomID: 1, Date: night(0)}, {RoomID: 1, Date: night(1)}}, written)
+
+	updated, err := queries.GetRoomAvailabilityByDate(ctx, db.GetRoomAvailabilityByDateParams{RoomID: 1, Date: night(0)})
+	require.NoError(t, err)
+	require.Equal(t, booked.Version+1, updated.Version)
+	require.Equal(t, int32(6000), updated.NightRate)
+	require.Equal(t, booked.BookedAt, updated.BookedAt)
+
+	inserted, err := queries.GetRoomAvailabilityByDate(ctx, db.GetRoomAvailabilityByDateParams{RoomID: 1, Date: night(1)})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), inserted.Version)
+
+	// Unchanged nights are left as they are.
+	written, err = queries.UpsertRoomAvailabilityNights(ctx, db.UpsertRoomAvailabilityNightsParams{
+		RoomIds:     []int32{1, 1},
+		Dates:       []pgtype.Date{night(0), night(1)},
+		IsAvailable: []bool{false, true},
+		NightRates:  []int32{6000, 6500},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []db.UpsertRoomAvailabilityNightsRow{{RoomID: 1, Date: night(1)}}, written)
+	unchanged, err := queries.GetRoomAvailabilityByDate(ctx, db.GetRoomAvailabilityByDateParams{RoomID: 1, Date: night(0)})
+	require.NoError(t, err)
+	require.Equal(t, updated, unchanged)
+}
+
+func TestHorizon(t *testing.T) {
+	queries := newTestQueries(t)
+	ctx := context.Background()
+
+	_, err := queries.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{RoomID: 1, Date: night(-2), NightRate: 7000})
+	require.NoError(t, err)
+	existing, err := queries.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{RoomID: 1, Date: night(1), NightRate: 7000})
+	require.NoError(t, err)
+
+	archived, err := queries.ArchiveRoomPastAvailability(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, []pgtype.Date{night(-2)}, archived)
+
+	added, err := queries.ExtendRoomAvailability(ctx, db.ExtendRoomAvailabilityParams{RoomID: 1, HorizonDays: 5})
+	require.NoError(t, err)
+	require.Equal(t, []pgtype.Date{night(0), night(2), night(3), night(4)}, added)
+
+	kept, err := queries.GetRoomAvailabilityByDate(ctx, db.GetRoomAvailabilityByDateParams{RoomID: 1, Date: night(1)})
+	require.NoError(t, err)
+	require.Equal(t, existing, kept)
+
+	last, err := queries.GetMaxDate(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, night(4), last)
+
+	added, err = queries.ExtendRoomAvailability(ctx, db.ExtendRoomAvailabilityParams{RoomID: 9, HorizonDays: 5})
+	require.NoError(t, err)
+	require.Empty(t, added)
+}
+
+func TestExecTxRollback(t *testing.T) {
+	queries := newTestQueries(t)
+	ctx := context.Background()
+	failure := errors.New("failure")
+
+	err := queries.ExecTx(ctx, func(tx db.Querier) error {
+		_, err := tx.UpsertRoom(ctx, db.UpsertRoomParams{RoomID: 2, MaxGuests: 2, DefaultRate: 5000})
+		require.NoError(t, err)
+		_, err = tx.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{RoomID: 1, Date: night(0), NightRate: 5000})
+		require.NoError(t, err)
+		return failure
+	})
+	require.ErrorIs(t, err, failure)
+
+	count, err := queries.GetRoomCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	_, err = queries.GetRoomAvailabilityByDate(ctx, db.GetRoomAvailabilityByDateParams{RoomID: 1, Date: night(0)})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	err = queries.ExecTx(ctx, func(tx db.Querier) error {
+		_, err := tx.UpsertRoom(ctx, db.UpsertRoomParams{RoomID: 2, MaxGuests: 2, DefaultRate: 5000})
+		return err
+	})
+	require.NoError(t, err)
+
+	count, err = queries.GetRoomCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+}
+
+func TestAPIKeys(t *testing.T) {
+	queries := newTestQueries(t)
+	ctx := context.Background()
+
+	key, err := queries.CreateAPIKey(ctx, db.CreateAPIKeyParams{Name: "ci", KeyHash: []byte{1}})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), key.ID)
+
+	_, err = queries.CreateAPIKey(ctx, db.CreateAPIKeyParams{Name: "ci", KeyHash: []byte{2}})
+	requireCode(t, err, "23505")
+
+	found, err := queries.GetAPIKeyByHash(ctx, []byte{1})
+	require.NoError(t, err)
+	require.Equal(t, key, found)
+
+	_, err = queries.GetAPIKeyByHash(ctx, []byte{3})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func TestAuditEvents(t *testing.T) {
+	queries := newTestQueries(t)
+	ctx := context.Background()
+
+	for _, arg := range []db.CreateAuditEventParams{
+		{Actor: "host", Action: "room.update", RoomID: pgtype.Int4{Int32: 1, Valid: true}, After: []byte(`{"max_guests": 4}`)},
+		{Actor: "ops", Action: "availability.update", RoomID: pgtype.Int4{Int32: 1, Valid: true}, Date: night(1)},
+		{Actor: "host", Action: "availability.update", RoomID: pgtype.Int4{Int32: 1, Valid: true}, Date: night(1)},
+		{Actor: "host", Action: "room.update", RoomID: pgtype.Int4{Int32: 2, Valid: true}},
+		{Actor: "host", Action: "api_key.create"},
+	} {
+		_, err := queries.CreateAuditEvent(ctx, arg)
+		require.NoError(t, err)
+	}
+
+	_, err := queries.CreateAuditEvent(ctx, db.CreateAuditEventParams{Actor: "host", Action: "room.update", Before: []byte("{")})
+	requireCode(t, err, "22P02")
+
+	events, err := queries.ListRoomAuditEvents(ctx, db.ListRoomAuditEventsParams{RoomID: 1, RowLimit: 10})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	require.Equal(t, []int64{3, 2, 1}, []int64{events[0].ID, events[1].ID, events[2].ID})
+
+	events, err = queries.ListRoomAuditEvents(ctx, db.ListRoomAuditEventsParams{
+		RoomID:   1,
+		Date:     night(1),
+		Actor:    pgtype.Text{String: "host", Valid: true},
+		RowLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, int64(3), events[0].ID)
+
+	events, err = queries.ListRoomAuditEvents(ctx, db.ListRoomAuditEventsParams{
+		RoomID:   1,
+		Until:    pgtype.Timestamptz{Time: today, Valid: true},
+		RowLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
+
+func TestPeriodStats(t *testing.T) {
+	queries := newTestQueries(t)
+	ctx := context.Background()
+
+	_, err := queries.CreateRoom(ctx, db.CreateRoomParams{RoomID: 2, MaxGuests: 2, DefaultRate: 5000})
+	require.NoError(t, err)
+	for day, rate := range map[int]int32{-3: 4000, -2: 6000, 0: 8000} {
+		_, err := queries.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{RoomID: 1, Date: night(day), NightRate: rate})
+		require.NoError(t, err)
+	}
+	_, err = queries.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{RoomID: 2, Date: night(-1), IsAvailable: true, NightRate: 5000})
+	require.NoError(t, err)
+	require.NoError(t, queries.ArchiveOldRoomAvailabilityData(ctx))
+
+	// A past night imported again is counted once, from the current calendar.
+	_, err = queries.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{RoomID: 1, Date: night(-2), IsAvailable: true, NightRate: 7000})
+	require.NoError(t, err)
+
+	stats, err := queries.GetRoomPeriodStats(ctx, db.GetRoomPeriodStatsParams{RoomID: 1, FromDate: night(-3), ToDate: night(0)})
+	require.NoError(t, err)
+	require.Equal(t, db.GetRoomPeriodStatsRow{
+		Nights:       3,
+		BookedNights: 2,
+		Revenue:      12000,
+		AverageRate:  19000.0 / 3,
+		LowestRate:   4000,
+		HighestRate:  8000,
+	}, stats)
+
+	portfolio, err := queries.GetPortfolioPeriodStats(ctx, db.GetPortfolioPeriodStatsParams{FromDate: night(-3), ToDate: night(-1)})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), portfolio.Rooms)
+	require.Equal(t, int64(3), portfolio.Nights)
+	require.Equal(t, int64(1), portfolio.BookedNights)
+
+	stats, err = queries.GetRoomPeriodStats(ctx, db.GetRoomPeriodStatsParams{RoomID: 1, FromDate: night(5), ToDate: night(9)})
+	require.NoError(t, err)
+	require.Zero(t, stats)
+}
+
+func TestPartitions(t *testing.T) {
+	queries := newTestQueries(t)
+	ctx := context.Background()
+
+	created, err := queries.CreateRoomAvailabilityPartition(ctx, night(0))
+	require.NoError(t, err)
+	require.True(t, created)
+	created, err = queries.CreateRoomAvailabilityPartition(ctx, night(-27))
+	require.NoError(t, err)
+	require.False(t, created, "the first night of the month has the same partition")
+	created, err = queries.CreateRoomAvailabilityPartition(ctx, night(-70))
+	require.NoError(t, err)
+	require.True(t, created)
+
+	// Nights of April and June have their own partition, those of May are in the default one.
+	for _, day := range []int{-70, -40, -2, 1} {
+		_, err := queries.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{RoomID: 1, Date: night(day), NightRate: 5000})
+		require.NoError(t, err)
+	}
+
+	archived, err := queries.ArchiveRoomAvailabilityPartitions(ctx, pgtype.Date{Time: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), Valid: true})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), archived)
+
+	count, err := queries.GetDateCount(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+	require.Len(t, queries.archive[1], 2)
+
+	partitions, err := queries.ListRoomAvailabilityPartitions(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []db.ListRoomAvailabilityPartitionsRow{
+		{Name: "room_availability_2024_06", Bound: "FOR VALUES FROM ('2024-06-01') TO ('2024-07-01')"},
+		{Name: "room_availability_default", Bound: "DEFAULT"},
+	}, partitions)
+}
+
+func TestRoomMonthStats(t *testing.T) {
+	queries := newTestQueries(t)
+	ctx := context.Background()
+
+	for day, rate := range []int32{5000, 6000, 7000, 8000} {
+		_, err := queries.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{RoomID: 1, Date: night(day), IsAvailable: day%2 == 0, NightRate: rate})
+		require.NoError(t, err)
+	}
+
+	stats, err := queries.ListRoomMonthStats(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+	require.Equal(t, pgtype.Date{Time: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), Valid: true}, stats[0].Month)
+	require.Equal(t, int32(3), stats[0].Nights)
+	require.Equal(t, int32(1), stats[0].BookedNights)
+	require.Equal(t, int64(6000), stats[0].Revenue)
+	require.Equal(t, int64(18000), stats[0].RateSum)
+	require.Equal(t, int32(5000), stats[0].LowestRate)
+	require.Equal(t, int32(7000), stats[0].HighestRate)
+	require.Equal(t, int32(1), stats[1].Nights)
+
+	inconsistent, err := queries.ListInconsistentRoomMonthStats(ctx)
+	require.NoError(t, err)
+	require.Empty(t, inconsistent)
+}
+
+func TestSearchAvailableRooms(t *testing.T) {
+	queries := newTestQueries(t)
+	ctx := context.Background()
+
+	_, err := queries.CreateRoom(ctx, db.CreateRoomParams{RoomID: 2, MaxGuests: 4, Balcony: true, DefaultRate: 5000})
+	require.NoError(t, err)
+	for day, rate := range []int32{5000, 6000, 7000} {
+		_, err := queries.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{RoomID: 1, Date: night(day), IsAvailable: true, NightRate: rate})
+		require.NoError(t, err)
+		_, err = queries.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{RoomID: 2, Date: night(day), IsAvailable: day != 2, NightRate: rate - 1000})
+		require.NoError(t, err)
+	}
+
+	nights, err := queries.ListRoomNights(ctx, db.ListRoomNightsParams{RoomID: 2, StartDate: night(1), EndDate: night(5)})
+	require.NoError(t, err)
+	require.Len(t, nights, 2)
+	require.Equal(t, night(1), nights[0].Date)
+	require.False(t, nights[1].IsAvailable)
+
+	// Stays are the cheapest first.
+	rows, err := queries.SearchAvailableRooms(ctx, db.SearchAvailableRoomsParams{Guests: 1, CheckIn: night(0), CheckOut: night(2), RowLimit: 10})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.Equal(t, int32(2), rows[0].Room.RoomID)
+	require.Equal(t, int64(9000), rows[0].Total)
+	require.Equal(t, int64(11000), rows[1].Total)
+
+	// Every night of the stay must be available, and nights missing from the calendar are not.
+	rows, err = queries.SearchAvailableRooms(ctx, db.SearchAvailableRoomsParams{Guests: 1, CheckIn: night(0), CheckOut: night(3), RowLimit: 10})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, int32(1), rows[0].Room.RoomID)
+	rows, err = queries.SearchAvailableRooms(ctx, db.SearchAvailableRoomsParams{Guests: 1, CheckIn: night(2), CheckOut: night(4), RowLimit: 10})
+	require.NoError(t, err)
+	require.Empty(t, rows)
+
+	rows, err = queries.SearchAvailableRooms(ctx, db.SearchAvailableRoomsParams{Guests: 3, Balcony: true, CheckIn: night(0), CheckOut: night(1), RowLimit: 10})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, int32(2), rows[0].Room.RoomID)
+	rows, err = queries.SearchAvailableRooms(ctx, db.SearchAvailableRoomsParams{Guests: 1, Fridge: true, CheckIn: night(0), CheckOut: night(1), RowLimit: 10})
+	require.NoError(t, err)
+	require.Empty(t, rows)
+
+	rows, err = queries.SearchAvailableRooms(ctx, db.SearchAvailableRoomsParams{Guests: 1, CheckIn: night(0), CheckOut: night(1), RowLimit: 1})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+}
+
+func TestListForRooms(t *testing.T) {
+	queries := newTestQueries(t)
+	ctx := context.Background()
+
+	for _, roomID := range []int32{2, 3} {
+		_, err := queries.CreateRoom(ctx, db.CreateRoomParams{RoomID: roomID, MaxGuests: 2, DefaultRate: 5000})
+		require.NoError(t, err)
+	}
+	// Both rooms have nights at the turn of the month, and room 2 has a night beyond the next 30 days.
+	for _, day := range []int{2, 3, 30} {
+		_, err := queries.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{RoomID: 2, Date: night(day), IsAvailable: day != 3, NightRate: 6000 + int32(day)})
+		require.NoError(t, err)
+		if day < 30 {
+			_, err = queries.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{RoomID: 1, Date: night(day), IsAvailable: true, NightRate: 5000})
+			require.NoError(t, err)
+		}
+	}
+
+	rooms, err := queries.ListRoomsByIDs(ctx, []int32{3, 1, 404})
+	require.NoError(t, err)
+	require.Len(t, rooms, 2)
+	require.Equal(t, int32(1), rooms[0].RoomID)
+	require.Equal(t, int32(3), rooms[1].RoomID)
+
+	nights, err := queries.ListNightsForRooms(ctx, db.ListNightsForRoomsParams{RoomIds: []int32{2, 1}, StartDate: night(3), EndDate: night(31)})
+	require.NoError(t, err)
+	require.Len(t, nights, 3)
+	require.Equal(t, int32(1), nights[0].RoomID)
+	require.Equal(t, int32(2), nights[1].RoomID)
+	require.Equal(t, night(3), nights[1].Date)
+	require.Equal(t, night(30), nights[2].Date)
+
+	rates, err := queries.ListUpcomingRates(ctx, []int32{1, 2, 3})
+	require.NoError(t, err)
+	require.Equal(t, []db.ListUpcomingRatesRow{
+		{RoomID: 1, AverageRate: 5000, HighestRate: 5000, LowestRate: 5000},
+		{RoomID: 2, AverageRate: 6002.5, HighestRate: 6003, LowestRate: 6002},
+	}, rates)
+
+	// Months are listed room by room.
+	stats, err := queries.ListMonthStatsForRooms(ctx, []int32{1, 2})
+	require.NoError(t, err)
+	require.Len(t, stats, 4)
+	require.Equal(t, int32(1), stats[0].RoomID)
+	require.Equal(t, int32(1), stats[1].RoomID)
+	require.Equal(t, int32(2), stats[2].RoomID)
+	require.Equal(t, int32(1), stats[2].Nights)
+	require.Equal(t, int32(0), stats[2].BookedNights)
+	require.Equal(t, int32(2), stats[3].Nights)
+	require.Equal(t, int32(1), stats[3].BookedNights)
+	require.Equal(t, int64(6003), stats[3].Revenue)
+}
@@ -0,0 +1,117 @@
+package memdb
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// CreateOutboxEvent needs no room lock: transactions are serialized, so events are committed in ID order.
+func (q *Queries) CreateOutboxEvent(ctx context.Context, arg db.CreateOutboxEventParams) (db.Outbox, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if err := checkJSON(arg.Payload); err != nil {
+		return db.Outbox{}, err
+	}
+
+	// Like a sequence, the ID is consumed even if the insert fails.
+	id := q.nextOutboxID
+	q.nextOutboxID++
+	if slices.ContainsFunc(q.outbox, func(event db.Outbox) bool { return event.EventID == arg.EventID }) {
+		return db.Outbox{}, pgError("23505", "outbox_event_id_key", `duplicate key value violates unique constraint "outbox_event_id_key"`)
+	}
+
+	event := db.Outbox{
+		ID:        id,
+		EventID:   arg.EventID,
+		EventType: arg.EventType,
+		RoomID:    arg.RoomID,
+		Payload:   slices.Clone(arg.Payload),
+		CreatedAt: q.now(),
+	}
+	q.outbox = append(q.outbox, event)
+	return event, nil
+}
+
+func (q *Queries) ListPendingOutboxEvents(ctx context.Context, arg db.ListPendingOutboxEventsParams) ([]db.Outbox, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	// Events are appended in ID order.
+	events := []db.Outbox{}
+	for _, event := range q.outbox {
+		if len(events) == int(arg.RowLimit) {
+			break
+		}
+		if !event.PublishedAt.Valid && event.ID > arg.AfterID {
+			event.Payload = slices.Clone(event.Payload)
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (q *Queries) MarkOutboxEventPublished(ctx context.Context, id int64) (int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	i := slices.IndexFunc(q.outbox, func(event db.Outbox) bool { return event.ID == id })
+	if i < 0 || q.outbox[i].PublishedAt.Valid {
+		return 0, nil
+	}
+	q.outbox[i].PublishedAt = pgtype.Timestamptz{Time: q.now(), Valid: true}
+	q.outbox[i].Position = pgtype.Int8{Int64: q.nextOutboxPosition, Valid: true}
+	q.nextOutboxPosition++
+	return 1, nil
+}
+
+func (q *Queries) ListPublishedOutboxEvents(ctx context.Context, arg db.ListPublishedOutboxEventsParams) ([]db.Outbox, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	events := []db.Outbox{}
+	for _, event := range q.outbox {
+		if event.Position.Valid && event.Position.Int64 > arg.AfterPosition && (!arg.RoomID.Valid || event.RoomID == arg.RoomID.Int32) {
+			event.Payload = slices.Clone(event.Payload)
+			events = append(events, event)
+		}
+	}
+	slices.SortFunc(events, func(a, b db.Outbox) int { return cmp.Compare(a.Position.Int64, b.Position.Int64) })
+	if len(events) > int(arg.RowLimit) {
+		events = events[:arg.RowLimit]
+	}
+	return events, nil
+}
+
+func (q *Queries) GetOutboxPosition(ctx context.Context) (int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var position int64
+	for _, event := range q.outbox {
+		if event.Position.Valid {
+			position = max(position, event.Position.Int64)
+		}
+	}
+	return position, nil
+}
+
+func (q *Queries) DeletePublishedOutboxEvents(ctx context.Context, retention pgtype.Interval) (int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	cutoff := addInterval(q.now(), pgtype.Interval{
+		Months:       -retention.Months,
+		Days:         -retention.Days,
+		Microseconds: -retention.Microseconds,
+	})
+	count := len(q.outbox)
+	q.outbox = slices.DeleteFunc(q.outbox, func(event db.Outbox) bool {
+		return event.PublishedAt.Valid && event.PublishedAt.Time.Before(cutoff)
+	})
+	return int64(count - len(q.outbox)), nil
+}
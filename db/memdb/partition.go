@@ -0,0 +1,85 @@
+package memdb
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// defaultPartition is the partition holding the nights of months without their own partition.
+const defaultPartition = "room_availability_default"
+
+// monthStart returns the first night of the month of a date.
+func monthStart(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// partitionName returns the name of the partition of the month starting on first.
+func partitionName(first time.Time) string {
+	return "room_availability_" + first.Format("2006_01")
+}
+
+func (q *Queries) CreateRoomAvailabilityPartition(ctx context.Context, night pgtype.Date) (bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	key := monthStart(night.Time).Format(dateLayout)
+	if q.partitions[key] {
+		return false, nil
+	}
+	q.partitions[key] = true
+	return true, nil
+}
+
+func (q *Queries) ArchiveRoomAvailabilityPartitions(ctx context.Context, before pgtype.Date) (int32, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	// Monthly partitions ending on or before the date are dropped, along with the nights of the
+	// default partition before it.
+	dropped := map[string]bool{}
+	for key := range q.partitions {
+		first, _ := time.Parse(dateLayout, key)
+		if !first.AddDate(0, 1, 0).After(before.Time) {
+			dropped[key] = true
+		}
+	}
+
+	for roomID, calendar := range q.nights {
+		for key, night := range calendar {
+			month := monthStart(night.Date.Time).Format(dateLayout)
+			if dropped[month] || (!q.partitions[month] && night.Date.Time.Before(before.Time)) {
+				q.archiveNight(roomID, key)
+			}
+		}
+	}
+	for key := range dropped {
+		delete(q.partitions, key)
+	}
+	return int32(len(dropped)), nil
+}
+
+func (q *Queries) ListRoomAvailabilityPartitions(ctx context.Context) ([]db.ListRoomAvailabilityPartitionsRow, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	keys := make([]string, 0, len(q.partitions))
+	for key := range q.partitions {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	partitions := make([]db.ListRoomAvailabilityPartitionsRow, 0, len(keys)+1)
+	for _, key := range keys {
+		first, _ := time.Parse(dateLayout, key)
+		partitions = append(partitions, db.ListRoomAvailabilityPartitionsRow{
+			Name:  partitionName(first),
+			Bound: fmt.Sprintf("FOR VALUES FROM ('%s') TO ('%s')", key, first.AddDate(0, 1, 0).Format(dateLayout)),
+		})
+	}
+	return append(partitions, db.ListRoomAvailabilityPartitionsRow{Name: defaultPartition, Bound: "DEFAULT"}), nil
+}
@@ -0,0 +1,262 @@
+package memdb
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// checkRoom enforces the constraints of the room table.
+func checkRoom(room db.Room) error {
+	if room.DefaultRate <= 0 {
+		return pgError("23514", "room_default_rate_check",
+			`new row for relation "room" violates check constraint "room_default_rate_check"`)
+	}
+	return nil
+}
+
+func (q *Queries) CreateRoom(ctx context.Context, arg db.CreateRoomParams) (db.Room, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	room := q.newRoom(db.UpsertRoomParams(arg))
+	if err := checkRoom(room); err != nil {
+		return db.Room{}, err
+	}
+	if _, ok := q.rooms[room.RoomID]; ok {
+		return db.Room{}, pgError("23505", "room_pkey", `duplicate key value violates unique constraint "room_pkey"`)
+	}
+	q.rooms[room.RoomID] = room
+	return room, nil
+}
+
+func (q *Queries) UpsertRoom(ctx context.Context, arg db.UpsertRoomParams) (db.Room, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	room := q.newRoom(arg)
+	if err := checkRoom(room); err != nil {
+		return db.Room{}, err
+	}
+	if existing, ok := q.rooms[room.RoomID]; ok {
+		unchanged := db.UpsertRoomParams{
+			RoomID:        existing.RoomID,
+			MaxGuests:     existing.MaxGuests,
+			Balcony:       existing.Balcony,
+			Fridge:        existing.Fridge,
+			IndoorPool:    existing.IndoorPool,
+			GamingConsole: existing.GamingConsole,
+			DefaultRate:   existing.DefaultRate,
+		}
+		if arg == unchanged {
+			return existing, nil
+		}
+		room.Version = existing.Version + 1
+	}
+	q.rooms[room.RoomID] = room
+	return room, nil
+}
+
+// newRoom returns the row inserted for a room, at its first version.
+func (q *Queries) newRoom(arg db.UpsertRoomParams) db.Room {
+	return db.Room{
+		RoomID:        arg.RoomID,
+		MaxGuests:     arg.MaxGuests,
+		Balcony:       arg.Balcony,
+		Fridge:        arg.Fridge,
+		IndoorPool:    arg.IndoorPool,
+		GamingConsole: arg.GamingConsole,
+		DefaultRate:   arg.DefaultRate,
+		Version:       1,
+		UpdatedAt:     q.now(),
+	}
+}
+
+func (q *Queries) DeleteRoom(ctx context.Context, roomID int32) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.nights[roomID]) > 0 {
+		return pgError("23503", "room_availability_room_id_fkey",
+			`update or delete on table "room" violates foreign key constraint "room_availability_room_id_fkey" on table "room_availability"`)
+	}
+	delete(q.rooms, roomID)
+	return nil
+}
+
+func (q *Queries) GetRoom(ctx context.Context, roomID int32) (db.Room, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	room, ok := q.rooms[roomID]
+	if !ok {
+		return db.Room{}, pgx.ErrNoRows
+	}
+	return room, nil
+}
+
+func (q *Queries) GetRoomCount(ctx context.Context) (int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return int64(len(q.rooms)), nil
+}
+
+func (q *Queries) ListAllRoomIDs(ctx context.Context) ([]int32, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.roomIDs(), nil
+}
+
+func (q *Queries) ListRooms(ctx context.Context, arg db.ListRoomsParams) ([]db.Room, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if arg.Limit < 0 {
+		return nil, pgError("2201W", "", "LIMIT must not be negative")
+	}
+	if arg.Offset < 0 {
+		return nil, pgError("2201X", "", "OFFSET must not be negative")
+	}
+
+	items := []db.Room{}
+	for _, roomID := range q.roomIDs() {
+		items = append(items, q.rooms[roomID])
+	}
+	start := min(int(arg.Offset), len(items))
+	end := min(start+int(arg.Limit), len(items))
+	return items[start:end], nil
+}
+
+func (q *Queries) ListRoomsByIDs(ctx context.Context, roomIds []int32) ([]db.Room, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	items := []db.Room{}
+	for _, roomID := range q.roomIDs() {
+		if slices.Contains(roomIds, roomID) {
+			items = append(items, q.rooms[roomID])
+		}
+	}
+	return items, nil
+}
+
+func (q *Queries) SearchAvailableRooms(ctx context.Context, arg db.SearchAvailableRoomsParams) ([]db.SearchAvailableRoomsRow, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if arg.RowLimit < 0 {
+		return nil, pgError("2201W", "", "LIMIT must not be negative")
+	}
+	items := []db.SearchAvailableRoomsRow{}
+	if !arg.CheckIn.Valid || !arg.CheckOut.Valid {
+		return items, nil
+	}
+	checkIn, checkOut := dateKey(arg.CheckIn), dateKey(arg.CheckOut)
+	nights := daysBetween(arg.CheckIn.Time, arg.CheckOut.Time)
+
+	for _, roomID := range q.roomIDs() {
+		room := q.rooms[roomID]
+		if room.MaxGuests < arg.Guests ||
+			(arg.Balcony && !room.Balcony) || (arg.Fridge && !room.Fridge) ||
+			(arg.IndoorPool && !room.IndoorPool) || (arg.GamingConsole && !room.GamingConsole) {
+			continue
+		}
+
+		var available int32
+		var total int64
+		for _, night := range q.calendar(roomID) {
+			if key := dateKey(night.Date); key >= checkIn && key < checkOut && night.IsAvailable {
+				available++
+				total += int64(night.NightRate)
+			}
+		}
+		if available > 0 && available == nights {
+			items = append(items, db.SearchAvailableRoomsRow{Room: room, Total: total})
+		}
+	}
+
+	slices.SortStableFunc(items, func(a, b db.SearchAvailableRoomsRow) int { return cmp.Compare(a.Total, b.Total) })
+	return items[:min(len(items), int(arg.RowLimit))], nil
+}
+
+func (q *Queries) UpdateMaxGuests(ctx context.Context, arg db.UpdateMaxGuestsParams) (db.Room, error) {
+	return q.updateRoom(arg.RoomID, anyVersion, func(room *db.Room) { room.MaxGuests = arg.MaxGuests })
+}
+
+func (q *Queries) UpdateRoomConsole(ctx context.Context, arg db.UpdateRoomConsoleParams) (db.Room, error) {
+	return q.updateRoom(arg.RoomID, anyVersion, func(room *db.Room) { room.GamingConsole = arg.GamingConsole })
+}
+
+func (q *Queries) UpdateRoomFridge(ctx context.Context, arg db.UpdateRoomFridgeParams) (db.Room, error) {
+	return q.updateRoom(arg.RoomID, anyVersion, func(room *db.Room) { room.Fridge = arg.Fridge })
+}
+
+func (q *Queries) UpdateRoom(ctx context.Context, arg db.UpdateRoomParams) (db.Room, error) {
+	return q.updateRoom(arg.RoomID, arg.Version, func(room *db.Room) {
+		if arg.MaxGuests.Valid {
+			room.MaxGuests = arg.MaxGuests.Int32
+		}
+		if arg.Balcony.Valid {
+			room.Balcony = arg.Balcony.Bool
+		}
+		if arg.Fridge.Valid {
+			room.Fridge = arg.Fridge.Bool
+		}
+		if arg.IndoorPool.Valid {
+			room.IndoorPool = arg.IndoorPool.Bool
+		}
+		if arg.GamingConsole.Valid {
+			room.GamingConsole = arg.GamingConsole.Bool
+		}
+		if arg.DefaultRate.Valid {
+			room.DefaultRate = arg.DefaultRate.Int32
+		}
+	})
+}
+
+// anyVersion lets updateRoom update a room whatever its version.
+const anyVersion = 0
+
+// updateRoom applies update to an existing room at the given version and returns the updated row at its next version.
+func (q *Queries) updateRoom(roomID int32, version int32, update func(room *db.Room)) (db.Room, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	room, ok := q.rooms[roomID]
+	if !ok || (version != anyVersion && room.Version != version) {
+		return db.Room{}, pgx.ErrNoRows
+	}
+	update(&room)
+	room.Version++
+	room.UpdatedAt = q.now()
+	if err := checkRoom(room); err != nil {
+		return db.Room{}, err
+	}
+	q.rooms[roomID] = room
+	return room, nil
+}
+
+// roomIDs returns the IDs of all rooms in ascending order. The caller must hold the lock.
+func (q *Queries) roomIDs() []int32 {
+	roomIDs := make([]int32, 0, len(q.rooms))
+	for roomID := range q.rooms {
+		roomIDs = append(roomIDs, roomID)
+	}
+	slices.Sort(roomIDs)
+	return roomIDs
+}
+
+// requireRoom enforces the foreign key from room_availability to room. The caller must hold the lock.
+func (q *Queries) requireRoom(roomID int32) error {
+	if _, ok := q.rooms[roomID]; !ok {
+		return pgError("23503", "room_availability_room_id_fkey",
+			fmt.Sprintf(`insert or update on table "room_availability" violates foreign key constraint "room_availability_room_id_fkey": room %d`, roomID))
+	}
+	return nil
+}
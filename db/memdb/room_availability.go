@@ -0,0 +1,544 @@
+package memdb
+
+import (
+	"context"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// errDuplicateNight is raised when a room already has a night on the same date.
+var errDuplicateNight = pgError("23505", "room_availability_room_id_date_idx",
+	`duplicate key value violates unique constraint "room_availability_room_id_date_idx"`)
+
+// newNight returns the row inserted for a night, at its first version.
+func (q *Queries) newNight(roomID int32, date pgtype.Date, isAvailable bool, nightRate int32) db.RoomAvailability {
+	night := db.RoomAvailability{
+		RoomID:      roomID,
+		Date:        date,
+		IsAvailable: isAvailable,
+		NightRate:   nightRate,
+		Version:     1,
+		UpdatedAt:   q.now(),
+	}
+	q.setBookedAt(&night, nil)
+	return night
+}
+
+// setBookedAt sets the booking time of a night written over previous, or inserted if previous is nil,
+// like the trigger of the room_availability table: it is set when the night gets booked and kept
+// while it stays booked.
+func (q *Queries) setBookedAt(night *db.RoomAvailability, previous *db.RoomAvailability) {
+	switch {
+	case night.IsAvailable:
+		night.BookedAt = pgtype.Timestamptz{}
+	case previous == nil || previous.IsAvailable:
+		night.BookedAt = pgtype.Timestamptz{Time: q.now(), Valid: true}
+	default:
+		night.BookedAt = previous.BookedAt
+	}
+}
+
+// checkNight enforces the constraints of the room_availability table. The caller must hold the lock.
+func (q *Queries) checkNight(night db.RoomAvailability) error {
+	if !night.Date.Valid {
+		return pgError("23502", "", `null value in column "date" of relation "room_availability" violates not-null constraint`)
+	}
+	return q.requireRoom(night.RoomID)
+}
+
+// insertNight adds a night to the calendar of its room. The caller must hold the lock.
+func (q *Queries) insertNight(night db.RoomAvailability) error {
+	if err := q.checkNight(night); err != nil {
+		return err
+	}
+	calendar := q.nights[night.RoomID]
+	if calendar == nil {
+		calendar = map[string]db.RoomAvailability{}
+		q.nights[night.RoomID] = calendar
+	}
+	if _, ok := calendar[dateKey(night.Date)]; ok {
+		return errDuplicateNight
+	}
+	calendar[dateKey(night.Date)] = night
+	return nil
+}
+
+// calendar returns the nights of a room in date order. The caller must hold the lock.
+func (q *Queries) calendar(roomID int32) []db.RoomAvailability {
+	calendar := q.nights[roomID]
+	keys := make([]string, 0, len(calendar))
+	for key := range calendar {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	nights := make([]db.RoomAvailability, len(keys))
+	for i, key := range keys {
+		nights[i] = calendar[key]
+	}
+	return nights
+}
+
+// upcoming returns the nights of a room within the metrics window starting today. The caller must hold the lock.
+func (q *Queries) upcoming(roomID int32) []db.RoomAvailability {
+	today := q.today()
+	from, to := today.Format(dateLayout), today.AddDate(0, 0, metricsWindow).Format(dateLayout)
+
+	var nights []db.RoomAvailability
+	for _, night := range q.calendar(roomID) {
+		if key := dateKey(night.Date); key >= from && key < to {
+			nights = append(nights, night)
+		}
+	}
+	return nights
+}
+
+func (q *Queries) CopyRoomAvailability(ctx context.Context, arg []db.CopyRoomAvailabilityParams) (int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	// COPY is a single statement: validate every row before inserting any.
+	copied := map[int32]map[string]bool{}
+	for _, row := range arg {
+		if err := q.checkNight(q.newNight(row.RoomID, row.Date, row.IsAvailable, row.NightRate)); err != nil {
+			return 0, err
+		}
+		if copied[row.RoomID] == nil {
+			copied[row.RoomID] = map[string]bool{}
+		}
+		_, exists := q.nights[row.RoomID][dateKey(row.Date)]
+		if exists || copied[row.RoomID][dateKey(row.Date)] {
+			return 0, errDuplicateNight
+		}
+		copied[row.RoomID][dateKey(row.Date)] = true
+	}
+
+	for _, row := range arg {
+		if err := q.insertNight(q.newNight(row.RoomID, row.Date, row.IsAvailable, row.NightRate)); err != nil {
+			return 0, err
+		}
+	}
+	return int64(len(arg)), nil
+}
+
+func (q *Queries) CreateRoomAvailability(ctx context.Context, arg db.CreateRoomAvailabilityParams) (db.RoomAvailability, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	night := q.newNight(arg.RoomID, arg.Date, arg.IsAvailable, arg.NightRate)
+	if err := q.insertNight(night); err != nil {
+		return db.RoomAvailability{}, err
+	}
+	return night, nil
+}
+
+func (q *Queries) UpdateRoomAvailability(ctx context.Context, arg db.UpdateRoomAvailabilityParams) (db.RoomAvailability, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	night, ok := q.nights[arg.RoomID][dateKey(arg.Date)]
+	if !ok || !arg.Date.Valid || night.Version != arg.Version {
+		return db.RoomAvailability{}, pgx.ErrNoRows
+	}
+	previous := night
+	night.IsAvailable = arg.IsAvailable
+	night.NightRate = arg.NightRate
+	night.Version++
+	night.UpdatedAt = q.now()
+	q.setBookedAt(&night, &previous)
+	q.nights[arg.RoomID][dateKey(arg.Date)] = night
+	return night, nil
+}
+
+func (q *Queries) UpsertRoomAvailability(ctx context.Context, arg db.UpsertRoomAvailabilityParams) (db.RoomAvailability, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	night := q.newNight(arg.RoomID, arg.Date, arg.IsAvailable, arg.NightRate)
+	if err := q.checkNight(night); err != nil {
+		return db.RoomAvailability{}, err
+	}
+	if existing, ok := q.nights[arg.RoomID][dateKey(arg.Date)]; ok {
+		night.Version = existing.Version + 1
+		q.setBookedAt(&night, &existing)
+	}
+	delete(q.nights[arg.RoomID], dateKey(arg.Date))
+	return night, q.insertNight(night)
+}
+
+func (q *Queries) UpsertRoomAvailabilityNights(ctx context.Context, arg db.UpsertRoomAvailabilityNightsParams) ([]db.UpsertRoomAvailabilityNightsRow, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	// unnest pads the shorter arrays with NULLs, which the columns reject.
+	n := len(arg.RoomIds)
+	if len(arg.Dates) != n || len(arg.IsAvailable) != n || len(arg.NightRates) != n {
+		return nil, pgError("23502", "", `null value in column of relation "room_availability" violates not-null constraint`)
+	}
+
+	// The statement is atomic: validate every night before writing any.
+	nights := make([]db.RoomAvailability, n)
+	written := map[int32]map[string]bool{}
+	for i := range n {
+		nights[i] = q.newNight(arg.RoomIds[i], arg.Dates[i], arg.IsAvailable[i], arg.NightRates[i])
+		if err := q.checkNight(nights[i]); err != nil {
+			return nil, err
+		}
+		if written[arg.RoomIds[i]] == nil {
+			written[arg.RoomIds[i]] = map[string]bool{}
+		}
+		if written[arg.RoomIds[i]][dateKey(arg.Dates[i])] {
+			return nil, pgError("21000", "", "ON CONFLICT DO UPDATE command cannot affect row a second time")
+		}
+		written[arg.RoomIds[i]][dateKey(arg.Dates[i])] = true
+	}
+
+	items := []db.UpsertRoomAvailabilityNightsRow{}
+	for _, night := range nights {
+		if existing, ok := q.nights[night.RoomID][dateKey(night.Date)]; ok {
+			if existing.IsAvailable == night.IsAvailable && existing.NightRate == night.NightRate {
+				continue
+			}
+			night.Version = existing.Version + 1
+			q.setBookedAt(&night, &existing)
+			delete(q.nights[night.RoomID], dateKey(night.Date))
+		}
+		if err := q.insertNight(night); err != nil {
+			return nil, err
+		}
+		items = append(items, db.UpsertRoomAvailabilityNightsRow{RoomID: night.RoomID, Date: night.Date})
+	}
+	return items, nil
+}
+
+func (q *Queries) GetRoomAvailabilityByDate(ctx context.Context, arg db.GetRoomAvailabilityByDateParams) (db.RoomAvailability, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	night, ok := q.nights[arg.RoomID][dateKey(arg.Date)]
+	if !ok || !arg.Date.Valid {
+		return db.RoomAvailability{}, pgx.ErrNoRows
+	}
+	return night, nil
+}
+
+func (q *Queries) ListRoomAvailability(ctx context.Context, roomID int32) ([]db.ListRoomAvailabilityRow, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	items := []db.ListRoomAvailabilityRow{}
+	for _, night := range q.upcoming(roomID) {
+		items = append(items, db.ListRoomAvailabilityRow{Date: night.Date, IsAvailable: night.IsAvailable, NightRate: night.NightRate})
+	}
+	return items, nil
+}
+
+func (q *Queries) ListUpcomingNightMetrics(ctx context.Context, roomIds []int32) ([]db.ListUpcomingNightMetricsRow, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	items := []db.ListUpcomingNightMetricsRow{}
+	for _, roomID := range q.roomIDs() {
+		if !slices.Contains(roomIds, roomID) {
+			continue
+		}
+		percentages := map[string]pgtype.Numeric{}
+		for _, month := range q.monthStats(nil, roomID) {
+			// CAST(... AS DECIMAL(10,2)) rounds half away from zero to hundredths of a percent.
+			available, total := int64(month.Nights-month.BookedNights), int64(month.Nights)
+			percentages[month.Month.Time.Format("2006-01")] = numeric((available*10000*2+total)/(2*total), 2)
+		}
+		for _, night := range q.upcoming(roomID) {
+			items = append(items, db.ListUpcomingNightMetricsRow{
+				RoomID:                 roomID,
+				Date:                   night.Date,
+				IsAvailable:            night.IsAvailable,
+				NightRate:              night.NightRate,
+				AvailabilityPercentage: percentages[night.Date.Time.Format("2006-01")],
+			})
+		}
+	}
+	return items, nil
+}
+
+func (q *Queries) ListRoomNights(ctx context.Context, arg db.ListRoomNightsParams) ([]db.RoomAvailability, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.nightsBetween([]db.RoomAvailability{}, arg.RoomID, arg.StartDate, arg.EndDate), nil
+}
+
+func (q *Queries) ListNightsForRooms(ctx context.Context, arg db.ListNightsForRoomsParams) ([]db.RoomAvailability, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	items := []db.RoomAvailability{}
+	for _, roomID := range q.roomIDs() {
+		if slices.Contains(arg.RoomIds, roomID) {
+			items = q.nightsBetween(items, roomID, arg.StartDate, arg.EndDate)
+		}
+	}
+	return items, nil
+}
+
+// nightsBetween appends the nights of a room from start to the day before end to items, in date order.
+// The caller must hold the lock.
+func (q *Queries) nightsBetween(items []db.RoomAvailability, roomID int32, start, end pgtype.Date) []db.RoomAvailability {
+	if !start.Valid || !end.Valid {
+		return items
+	}
+	from, to := dateKey(start), dateKey(end)
+	for _, night := range q.calendar(roomID) {
+		if key := dateKey(night.Date); key >= from && key < to {
+			items = append(items, night)
+		}
+	}
+	return items
+}
+
+func (q *Queries) ListAvailableDates(ctx context.Context, roomID int32) ([]pgtype.Date, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	items := []pgtype.Date{}
+	for _, night := range q.calendar(roomID) {
+		if len(items) == listLimit {
+			break
+		}
+		if night.IsAvailable {
+			items = append(items, night.Date)
+		}
+	}
+	return items, nil
+}
+
+func (q *Queries) GetAverageRate(ctx context.Context, roomID int32) (float64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	nights := q.upcoming(roomID)
+	if len(nights) == 0 {
+		return 0, errNullAverage
+	}
+	var sum int64
+	for _, night := range nights {
+		sum += int64(night.NightRate)
+	}
+	return float64(sum) / float64(len(nights)), nil
+}
+
+func (q *Queries) GetMaximumRate(ctx context.Context, roomID int32) (int32, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	nights := q.upcoming(roomID)
+	if len(nights) == 0 {
+		return 0, pgx.ErrNoRows
+	}
+	return slices.MaxFunc(nights, compareRates).NightRate, nil
+}
+
+func (q *Queries) GetMinimumRate(ctx context.Context, roomID int32) (int32, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	nights := q.upcoming(roomID)
+	if len(nights) == 0 {
+		return 0, pgx.ErrNoRows
+	}
+	return slices.MinFunc(nights, compareRates).NightRate, nil
+}
+
+// compareRates orders nights by nightly rate.
+func (q *Queries) ListUpcomingRates(ctx context.Context, roomIds []int32) ([]db.ListUpcomingRatesRow, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	items := []db.ListUpcomingRatesRow{}
+	for _, roomID := range q.roomIDs() {
+		nights := q.upcoming(roomID)
+		if !slices.Contains(roomIds, roomID) || len(nights) == 0 {
+			continue
+		}
+		row := db.ListUpcomingRatesRow{RoomID: roomID, HighestRate: nights[0].NightRate, LowestRate: nights[0].NightRate}
+		var sum int64
+		for _, night := range nights {
+			sum += int64(night.NightRate)
+			row.HighestRate = max(row.HighestRate, night.NightRate)
+			row.LowestRate = min(row.LowestRate, night.NightRate)
+		}
+		row.AverageRate = float64(sum) / float64(len(nights))
+		items = append(items, row)
+	}
+	return items, nil
+}
+
+func compareRates(a, b db.RoomAvailability) int {
+	return int(a.NightRate) - int(b.NightRate)
+}
+
+func (q *Queries) GetMaxDate(ctx context.Context, roomID int32) (pgtype.Date, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	nights := q.calendar(roomID)
+	if len(nights) == 0 {
+		return pgtype.Date{}, pgx.ErrNoRows
+	}
+	return nights[len(nights)-1].Date, nil
+}
+
+func (q *Queries) GetCurrentDate(ctx context.Context) (pgtype.Date, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return pgtype.Date{Time: q.today(), Valid: true}, nil
+}
+
+func (q *Queries) GetDateCount(ctx context.Context, roomID int32) (int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return int64(len(q.nights[roomID])), nil
+}
+
+func (q *Queries) GetAvailabilityPercentage(ctx context.Context, roomID int32) ([]db.GetAvailabilityPercentageRow, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	type month struct {
+		year, month      int
+		available, total int64
+	}
+	var months []month
+	for _, night := range q.calendar(roomID) {
+		year, m, _ := night.Date.Time.Date()
+		if len(months) == 0 || months[len(months)-1].year != year || months[len(months)-1].month != int(m) {
+			months = append(months, month{year: year, month: int(m)})
+		}
+		current := &months[len(months)-1]
+		current.total++
+		if night.IsAvailable {
+			current.available++
+		}
+	}
+
+	items := []db.GetAvailabilityPercentageRow{}
+	for _, m := range months {
+		// CAST(... AS DECIMAL(10,2)) rounds half away from zero to hundredths of a percent.
+		hundredths := (m.available*10000*2 + m.total) / (2 * m.total)
+		items = append(items, db.GetAvailabilityPercentageRow{
+			Year:                   numeric(int64(m.year), 0),
+			Month:                  numeric(int64(m.month), 0),
+			AvailabilityPercentage: numeric(hundredths, 2),
+		})
+	}
+	return items, nil
+}
+
+func (q *Queries) ArchiveOldRoomAvailabilityData(ctx context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for roomID := range q.nights {
+		q.archivePast(roomID)
+	}
+	return nil
+}
+
+func (q *Queries) ArchiveRoomPastAvailability(ctx context.Context, roomID int32) ([]pgtype.Date, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.archivePast(roomID), nil
+}
+
+// archivePast moves the nights of a room before today to the archive, replacing archived nights
+// on the same dates, and returns their dates. The caller must hold the lock.
+func (q *Queries) archivePast(roomID int32) []pgtype.Date {
+	today := q.today().Format(dateLayout)
+	archived := []pgtype.Date{}
+	for _, night := range q.calendar(roomID) {
+		if key := dateKey(night.Date); key < today {
+			archived = append(archived, night.Date)
+			q.archiveNight(roomID, key)
+		}
+	}
+	return archived
+}
+
+// archiveNight moves a night to the archive, replacing any archived night on the same date.
+// The caller must hold the lock.
+func (q *Queries) archiveNight(roomID int32, key string) {
+	night := q.nights[roomID][key]
+	if q.archive[roomID] == nil {
+		q.archive[roomID] = map[string]db.RoomAvailabilityHistory{}
+	}
+	q.archive[roomID][key] = db.RoomAvailabilityHistory{
+		RoomID:      night.RoomID,
+		Date:        night.Date,
+		IsAvailable: night.IsAvailable,
+		NightRate:   night.NightRate,
+		ArchivedAt:  q.now(),
+		BookedAt:    night.BookedAt,
+	}
+	delete(q.nights[roomID], key)
+}
+
+func (q *Queries) DeleteAllAvailabilityForRoom(ctx context.Context, roomID int32) ([]pgtype.Date, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	deleted := []pgtype.Date{}
+	for _, night := range q.calendar(roomID) {
+		deleted = append(deleted, night.Date)
+	}
+	delete(q.nights, roomID)
+	return deleted, nil
+}
+
+func (q *Queries) DeleteRoomAvailabilityNights(ctx context.Context, arg db.DeleteRoomAvailabilityNightsParams) ([]db.DeleteRoomAvailabilityNightsRow, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	// unnest pads the shorter array with NULLs, which match no night.
+	deleted := []db.DeleteRoomAvailabilityNightsRow{}
+	for i := range min(len(arg.RoomIds), len(arg.Dates)) {
+		if !arg.Dates[i].Valid {
+			continue
+		}
+		key := dateKey(arg.Dates[i])
+		if _, ok := q.nights[arg.RoomIds[i]][key]; ok {
+			deleted = append(deleted, db.DeleteRoomAvailabilityNightsRow{RoomID: arg.RoomIds[i], Date: arg.Dates[i]})
+			delete(q.nights[arg.RoomIds[i]], key)
+		}
+	}
+	return deleted, nil
+}
+
+func (q *Queries) ExtendRoomAvailability(ctx context.Context, arg db.ExtendRoomAvailabilityParams) ([]pgtype.Date, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	added := []pgtype.Date{}
+	room, ok := q.rooms[arg.RoomID]
+	if !ok {
+		return added, nil
+	}
+
+	today := q.today()
+	for day := range max(arg.HorizonDays, 0) {
+		date := pgtype.Date{Time: today.AddDate(0, 0, int(day)), Valid: true}
+		if _, ok := q.nights[room.RoomID][dateKey(date)]; ok {
+			continue // ON CONFLICT DO NOTHING
+		}
+		if err := q.insertNight(q.newNight(room.RoomID, date, true, room.DefaultRate)); err != nil {
+			return nil, err
+		}
+		added = append(added, date)
+	}
+	return added, nil
+}
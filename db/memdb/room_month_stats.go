@@ -0,0 +1,68 @@
+package memdb
+
+import (
+	"context"
+	"slices"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// The monthly statistics are computed from the calendar when they are read, which is what the triggers
+// maintaining them in PostgreSQL guarantee. They are therefore never inconsistent, and refreshing them is a no-op.
+
+func (q *Queries) ListRoomMonthStats(ctx context.Context, roomID int32) ([]db.RoomMonthStat, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.monthStats([]db.RoomMonthStat{}, roomID), nil
+}
+
+func (q *Queries) ListMonthStatsForRooms(ctx context.Context, roomIds []int32) ([]db.RoomMonthStat, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	items := []db.RoomMonthStat{}
+	for _, roomID := range q.roomIDs() {
+		if slices.Contains(roomIds, roomID) {
+			items = q.monthStats(items, roomID)
+		}
+	}
+	return items, nil
+}
+
+// monthStats appends the statistics of every month of the calendar of a room to items, in month order.
+// The caller must hold the lock.
+func (q *Queries) monthStats(items []db.RoomMonthStat, roomID int32) []db.RoomMonthStat {
+	first := len(items)
+	for _, night := range q.calendar(roomID) {
+		month := pgtype.Date{Time: monthStart(night.Date.Time), Valid: true}
+		if len(items) == first || items[len(items)-1].Month != month {
+			items = append(items, db.RoomMonthStat{
+				RoomID:      roomID,
+				Month:       month,
+				LowestRate:  night.NightRate,
+				HighestRate: night.NightRate,
+				RefreshedAt: q.now(),
+			})
+		}
+		stats := &items[len(items)-1]
+		stats.Nights++
+		stats.RateSum += int64(night.NightRate)
+		stats.LowestRate = min(stats.LowestRate, night.NightRate)
+		stats.HighestRate = max(stats.HighestRate, night.NightRate)
+		if !night.IsAvailable {
+			stats.BookedNights++
+			stats.Revenue += int64(night.NightRate)
+		}
+	}
+	return items
+}
+
+func (q *Queries) ListInconsistentRoomMonthStats(ctx context.Context) ([]db.ListInconsistentRoomMonthStatsRow, error) {
+	return []db.ListInconsistentRoomMonthStatsRow{}, nil
+}
+
+func (q *Queries) RefreshRoomMonthStats(ctx context.Context, arg db.RefreshRoomMonthStatsParams) error {
+	return nil
+}
@@ -0,0 +1,228 @@
+package memdb
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// periodStats aggregates nights like the period statistics queries.
+type periodStats struct {
+	rooms                              map[int32]bool
+	nights, bookedNights, revenue, sum int64
+	lowestRate, highestRate            int32
+}
+
+// add counts a night in the statistics.
+func (stats *periodStats) add(roomID int32, isAvailable bool, nightRate int32) {
+	if stats.nights == 0 || nightRate < stats.lowestRate {
+		stats.lowestRate = nightRate
+	}
+	if stats.nights == 0 || nightRate > stats.highestRate {
+		stats.highestRate = nightRate
+	}
+	stats.rooms[roomID] = true
+	stats.nights++
+	stats.sum += int64(nightRate)
+	if !isAvailable {
+		stats.bookedNights++
+		stats.revenue += int64(nightRate)
+	}
+}
+
+// averageRate returns the average nightly rate, or 0 without nights like COALESCE(AVG(...), 0).
+func (stats *periodStats) averageRate() float64 {
+	if stats.nights == 0 {
+		return 0
+	}
+	return float64(stats.sum) / float64(stats.nights)
+}
+
+// periodStats aggregates the nights of the room_night view between two dates, inclusive, for the given
+// rooms, or for all rooms if roomIDs is nil. The caller must hold the lock.
+func (q *Queries) periodStats(roomIDs []int32, from, to pgtype.Date) periodStats {
+	stats := periodStats{rooms: map[int32]bool{}}
+	if !from.Valid || !to.Valid {
+		return stats // BETWEEN NULL matches no night.
+	}
+	first, last := dateKey(from), dateKey(to)
+
+	if roomIDs == nil {
+		seen := map[int32]bool{}
+		for roomID := range q.nights {
+			seen[roomID] = true
+		}
+		for roomID := range q.archive {
+			seen[roomID] = true
+		}
+		for roomID := range seen {
+			roomIDs = append(roomIDs, roomID)
+		}
+	}
+
+	for _, roomID := range roomIDs {
+		for key, night := range q.nights[roomID] {
+			if key >= first && key <= last {
+				stats.add(roomID, night.IsAvailable, night.NightRate)
+			}
+		}
+		for key, night := range q.archive[roomID] {
+			if _, current := q.nights[roomID][key]; !current && key >= first && key <= last {
+				stats.add(roomID, night.IsAvailable, night.NightRate)
+			}
+		}
+	}
+	return stats
+}
+
+func (q *Queries) GetRoomPeriodStats(ctx context.Context, arg db.GetRoomPeriodStatsParams) (db.GetRoomPeriodStatsRow, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stats := q.periodStats([]int32{arg.RoomID}, arg.FromDate, arg.ToDate)
+	return db.GetRoomPeriodStatsRow{
+		Nights:       stats.nights,
+		BookedNights: stats.bookedNights,
+		Revenue:      stats.revenue,
+		AverageRate:  stats.averageRate(),
+		LowestRate:   stats.lowestRate,
+		HighestRate:  stats.highestRate,
+	}, nil
+}
+
+func (q *Queries) GetPortfolioPeriodStats(ctx context.Context, arg db.GetPortfolioPeriodStatsParams) (db.GetPortfolioPeriodStatsRow, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stats := q.periodStats(nil, arg.FromDate, arg.ToDate)
+	return db.GetPortfolioPeriodStatsRow{
+		Rooms:        int64(len(stats.rooms)),
+		Nights:       stats.nights,
+		BookedNights: stats.bookedNights,
+		Revenue:      stats.revenue,
+		AverageRate:  stats.averageRate(),
+		LowestRate:   stats.lowestRate,
+		HighestRate:  stats.highestRate,
+	}, nil
+}
+
+// roomNights returns the rows of the room_night view of a room, or of every room if roomID is NULL,
+// in date order. The caller must hold the lock.
+func (q *Queries) roomNights(roomID pgtype.Int4) []db.RoomNight {
+	var nights []db.RoomNight
+	add := func(roomID int32) {
+		for _, night := range q.nights[roomID] {
+			nights = append(nights, db.RoomNight{RoomID: roomID, Date: night.Date, IsAvailable: night.IsAvailable, NightRate: night.NightRate, BookedAt: night.BookedAt})
+		}
+		for key, night := range q.archive[roomID] {
+			if _, current := q.nights[roomID][key]; !current {
+				nights = append(nights, db.RoomNight{RoomID: roomID, Date: night.Date, IsAvailable: night.IsAvailable, NightRate: night.NightRate, BookedAt: night.BookedAt})
+			}
+		}
+	}
+
+	if roomID.Valid {
+		add(roomID.Int32)
+	} else {
+		seen := map[int32]bool{}
+		for id := range q.nights {
+			seen[id] = true
+		}
+		for id := range q.archive {
+			seen[id] = true
+		}
+		for id := range seen {
+			add(id)
+		}
+	}
+	slices.SortFunc(nights, func(a, b db.RoomNight) int { return a.Date.Time.Compare(b.Date.Time) })
+	return nights
+}
+
+// daysBetween returns the number of days from one date to another, like the difference of two DATEs.
+func daysBetween(from, to time.Time) int32 {
+	return int32(to.Sub(from).Round(24*time.Hour) / (24 * time.Hour))
+}
+
+// utcDate returns the date of a timestamp in UTC, like a cast to DATE in a UTC session.
+func utcDate(timestamp time.Time) time.Time {
+	timestamp = timestamp.UTC()
+	return time.Date(timestamp.Year(), timestamp.Month(), timestamp.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func (q *Queries) GetBookingPace(ctx context.Context, arg db.GetBookingPaceParams) ([]db.GetBookingPaceRow, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	today := q.today()
+	first := today.AddDate(0, 0, -int(arg.LookbackDays))
+	var tracked time.Time // Date of the first recorded booking time.
+	for _, night := range q.roomNights(pgtype.Int4{}) {
+		if night.BookedAt.Valid && (tracked.IsZero() || night.BookedAt.Time.Before(tracked)) {
+			tracked = night.BookedAt.Time
+		}
+	}
+	if tracked.IsZero() {
+		return []db.GetBookingPaceRow{}, nil
+	}
+	if tracked = utcDate(tracked); tracked.After(first) {
+		first = tracked
+	}
+	counts := map[int32]int64{}
+	var unbooked int64
+	for _, night := range q.roomNights(arg.RoomID) {
+		if !night.Date.Time.Before(today) || night.Date.Time.Before(first) {
+			continue
+		}
+		if night.IsAvailable {
+			unbooked++
+			continue
+		}
+		if !night.BookedAt.Valid {
+			continue
+		}
+		leadDays := daysBetween(utcDate(night.BookedAt.Time), night.Date.Time)
+		if leadDays >= 0 {
+			counts[leadDays]++
+		}
+	}
+
+	items := []db.GetBookingPaceRow{}
+	if unbooked > 0 {
+		items = append(items, db.GetBookingPaceRow{Nights: unbooked})
+	}
+	leads := slices.Sorted(maps.Keys(counts))
+	for _, leadDays := range leads {
+		items = append(items, db.GetBookingPaceRow{LeadDays: pgtype.Int4{Int32: leadDays, Valid: true}, Nights: counts[leadDays]})
+	}
+	return items, nil
+}
+
+func (q *Queries) GetForecastNights(ctx context.Context, arg db.GetForecastNightsParams) ([]db.GetForecastNightsRow, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	items := []db.GetForecastNightsRow{}
+	if !arg.FromDate.Valid || !arg.ToDate.Valid {
+		return items, nil
+	}
+	today := q.today()
+	for _, night := range q.roomNights(arg.RoomID) {
+		if night.Date.Time.Before(arg.FromDate.Time) || night.Date.Time.After(arg.ToDate.Time) {
+			continue
+		}
+		if len(items) == 0 || items[len(items)-1].Date != night.Date {
+			items = append(items, db.GetForecastNightsRow{Date: night.Date, DaysAhead: daysBetween(today, night.Date.Time)})
+		}
+		row := &items[len(items)-1]
+		row.Nights++
+		if !night.IsAvailable {
+			row.BookedNights++
+		}
+	}
+	return items, nil
+}
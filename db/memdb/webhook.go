@@ -0,0 +1,255 @@
+package memdb
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// webhookDeliveryStatuses are the values allowed by the check constraint of webhook_delivery.status.
+var webhookDeliveryStatuses = []string{"pending", "delivered", "failed"}
+
+// addInterval returns t plus an INTERVAL.
+func addInterval(t time.Time, interval pgtype.Interval) time.Time {
+	return t.AddDate(0, int(interval.Months), int(interval.Days)).Add(time.Duration(interval.Microseconds) * time.Microsecond)
+}
+
+// webhookDelivery returns the index of a delivery, or -1 if it does not exist.
+func (q *Queries) webhookDelivery(id int64) int {
+	return slices.IndexFunc(q.webhookDeliveries, func(delivery db.WebhookDelivery) bool { return delivery.ID == id })
+}
+
+// insertWebhookDelivery appends a pending delivery due now.
+func (q *Queries) insertWebhookDelivery(delivery db.WebhookDelivery) db.WebhookDelivery {
+	// Like a sequence, the ID is consumed even if the transaction is rolled back.
+	delivery.ID = q.nextWebhookDeliveryID
+	q.nextWebhookDeliveryID++
+	delivery.Payload = slices.Clone(delivery.Payload)
+	delivery.Status = "pending"
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = q.now()
+	delivery.LastAttemptAt = pgtype.Timestamptz{}
+	delivery.ResponseStatus = pgtype.Int4{}
+	delivery.LastError = pgtype.Text{}
+	delivery.CreatedAt = q.now()
+	q.webhookDeliveries = append(q.webhookDeliveries, delivery)
+	return delivery
+}
+
+func (q *Queries) CreateWebhookDeliveries(ctx context.Context, arg db.CreateWebhookDeliveriesParams) (int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if err := checkJSON(arg.Payload); err != nil {
+		return 0, err
+	}
+
+	var created int64
+	for _, subscription := range q.webhookSubscriptions {
+		if !slices.Contains(subscription.EventTypes, arg.EventType) {
+			continue
+		}
+		// ON CONFLICT DO NOTHING on the unique index of the first delivery of an event.
+		queued := slices.ContainsFunc(q.webhookDeliveries, func(delivery db.WebhookDelivery) bool {
+			return delivery.SubscriptionID == subscription.ID && delivery.EventID == arg.EventID && !delivery.ReplayOf.Valid
+		})
+		if !queued {
+			q.insertWebhookDelivery(db.WebhookDelivery{
+				SubscriptionID: subscription.ID,
+				EventID:        arg.EventID,
+				EventType:      arg.EventType,
+				RoomID:         arg.RoomID,
+				Payload:        arg.Payload,
+			})
+			created++
+		}
+	}
+	return created, nil
+}
+
+func (q *Queries) CreateWebhookSubscription(ctx context.Context, arg db.CreateWebhookSubscriptionParams) (db.WebhookSubscription, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if arg.EventTypes == nil {
+		return db.WebhookSubscription{}, pgError("23502", "", `null value in column "event_types" of relation "webhook_subscription" violates not-null constraint`)
+	}
+
+	// Like a sequence, the ID is consumed even if the transaction is rolled back.
+	subscription := db.WebhookSubscription{
+		ID:         q.nextWebhookSubscriptionID,
+		Url:        arg.Url,
+		Secret:     arg.Secret,
+		EventTypes: slices.Clone(arg.EventTypes),
+		CreatedAt:  q.now(),
+	}
+	q.nextWebhookSubscriptionID++
+	q.webhookSubscriptions = append(q.webhookSubscriptions, subscription)
+	return subscription, nil
+}
+
+func (q *Queries) DeleteWebhookSubscription(ctx context.Context, id int64) (int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	i := slices.IndexFunc(q.webhookSubscriptions, func(subscription db.WebhookSubscription) bool { return subscription.ID == id })
+	if i < 0 {
+		return 0, nil
+	}
+	q.webhookSubscriptions = slices.Delete(q.webhookSubscriptions, i, i+1)
+
+	// ON DELETE CASCADE.
+	q.webhookDeliveries = slices.DeleteFunc(q.webhookDeliveries, func(delivery db.WebhookDelivery) bool { return delivery.SubscriptionID == id })
+	return 1, nil
+}
+
+func (q *Queries) GetWebhookDelivery(ctx context.Context, id int64) (db.WebhookDelivery, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	i := q.webhookDelivery(id)
+	if i < 0 {
+		return db.WebhookDelivery{}, pgx.ErrNoRows
+	}
+	return q.webhookDeliveries[i], nil
+}
+
+func (q *Queries) GetWebhookSubscription(ctx context.Context, id int64) (db.WebhookSubscription, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, subscription := range q.webhookSubscriptions {
+		if subscription.ID == id {
+			return subscription, nil
+		}
+	}
+	return db.WebhookSubscription{}, pgx.ErrNoRows
+}
+
+// dueWebhookDeliveries returns the due deliveries, the longest overdue first.
+func (q *Queries) dueWebhookDeliveries() []db.WebhookDelivery {
+	now := q.now()
+	due := []db.WebhookDelivery{}
+	for _, delivery := range q.webhookDeliveries {
+		if delivery.Status == "pending" && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	slices.SortStableFunc(due, func(a, b db.WebhookDelivery) int { return a.NextAttemptAt.Compare(b.NextAttemptAt) })
+	return due
+}
+
+func (q *Queries) ListDueWebhookDeliveries(ctx context.Context, arg db.ListDueWebhookDeliveriesParams) ([]db.ListDueWebhookDeliveriesRow, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if arg.RowLimit < 0 {
+		return nil, pgError("2201W", "", "LIMIT must not be negative")
+	}
+
+	due := slices.DeleteFunc(q.dueWebhookDeliveries(), func(delivery db.WebhookDelivery) bool {
+		return delivery.SubscriptionID != arg.SubscriptionID
+	})
+	items := []db.ListDueWebhookDeliveriesRow{}
+	for _, delivery := range due[:min(int(arg.RowLimit), len(due))] {
+		i := slices.IndexFunc(q.webhookSubscriptions, func(subscription db.WebhookSubscription) bool { return subscription.ID == delivery.SubscriptionID })
+		items = append(items, db.ListDueWebhookDeliveriesRow{
+			ID:        delivery.ID,
+			EventID:   delivery.EventID,
+			EventType: delivery.EventType,
+			Payload:   delivery.Payload,
+			Attempts:  delivery.Attempts,
+			Url:       q.webhookSubscriptions[i].Url,
+			Secret:    q.webhookSubscriptions[i].Secret,
+		})
+	}
+	return items, nil
+}
+
+func (q *Queries) ListDueWebhookSubscriptions(ctx context.Context) ([]int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	items := []int64{}
+	for _, delivery := range q.dueWebhookDeliveries() {
+		if !slices.Contains(items, delivery.SubscriptionID) {
+			items = append(items, delivery.SubscriptionID)
+		}
+	}
+	return items, nil
+}
+
+func (q *Queries) ListWebhookDeliveries(ctx context.Context, arg db.ListWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if arg.RowLimit < 0 {
+		return nil, pgError("2201W", "", "LIMIT must not be negative")
+	}
+	if arg.RowOffset < 0 {
+		return nil, pgError("2201X", "", "OFFSET must not be negative")
+	}
+
+	items := []db.WebhookDelivery{}
+	for _, delivery := range slices.Backward(q.webhookDeliveries) {
+		if delivery.SubscriptionID == arg.SubscriptionID && (!arg.Status.Valid || delivery.Status == arg.Status.String) {
+			items = append(items, delivery)
+		}
+	}
+	start := min(int(arg.RowOffset), len(items))
+	end := min(start+int(arg.RowLimit), len(items))
+	return items[start:end], nil
+}
+
+func (q *Queries) ListWebhookSubscriptions(ctx context.Context) ([]db.WebhookSubscription, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return append([]db.WebhookSubscription{}, q.webhookSubscriptions...), nil
+}
+
+func (q *Queries) RecordWebhookDeliveryAttempt(ctx context.Context, arg db.RecordWebhookDeliveryAttemptParams) (db.WebhookDelivery, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !slices.Contains(webhookDeliveryStatuses, arg.Status) {
+		return db.WebhookDelivery{}, pgError("23514", "webhook_delivery_status_check", `new row for relation "webhook_delivery" violates check constraint "webhook_delivery_status_check"`)
+	}
+	i := q.webhookDelivery(arg.ID)
+	if i < 0 {
+		return db.WebhookDelivery{}, pgx.ErrNoRows
+	}
+
+	now := q.now()
+	delivery := &q.webhookDeliveries[i]
+	delivery.Status = arg.Status
+	delivery.Attempts++
+	delivery.LastAttemptAt = pgtype.Timestamptz{Time: now, Valid: true}
+	delivery.NextAttemptAt = addInterval(now, arg.RetryDelay)
+	delivery.ResponseStatus = arg.ResponseStatus
+	delivery.LastError = arg.LastError
+	return *delivery, nil
+}
+
+func (q *Queries) ReplayWebhookDelivery(ctx context.Context, id int64) (db.WebhookDelivery, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	i := q.webhookDelivery(id)
+	if i < 0 {
+		return db.WebhookDelivery{}, pgx.ErrNoRows
+	}
+	original := q.webhookDeliveries[i]
+	return q.insertWebhookDelivery(db.WebhookDelivery{
+		SubscriptionID: original.SubscriptionID,
+		EventID:        original.EventID,
+		EventType:      original.EventType,
+		RoomID:         original.RoomID,
+		Payload:        original.Payload,
+		ReplayOf:       pgtype.Int8{Int64: original.ID, Valid: true},
+	}), nil
+}
@@ -0,0 +1,6 @@
+CREATE TABLE "api_key" (
+  "id" bigserial PRIMARY KEY,
+  "name" varchar NOT NULL UNIQUE,
+  "key_hash" bytea NOT NULL UNIQUE,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
\ No newline at end of file
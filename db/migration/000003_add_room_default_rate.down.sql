@@ -0,0 +1 @@
+ALTER TABLE "room" DROP COLUMN IF EXISTS "default_rate";
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE "room" ADD COLUMN "default_rate" integer NOT NULL DEFAULT 5000 CHECK ("default_rate" > 0);
\ No newline at end of file
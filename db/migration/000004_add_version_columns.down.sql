@@ -0,0 +1,7 @@
+ALTER TABLE "room_availability"
+  DROP COLUMN IF EXISTS "version",
+  DROP COLUMN IF EXISTS "updated_at";
+
+ALTER TABLE "room"
+  DROP COLUMN IF EXISTS "version",
+  DROP COLUMN IF EXISTS "updated_at";
\ No newline at end of file
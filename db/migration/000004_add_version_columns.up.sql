@@ -0,0 +1,7 @@
+ALTER TABLE "room"
+  ADD COLUMN "version" integer NOT NULL DEFAULT 1,
+  ADD COLUMN "updated_at" timestamptz NOT NULL DEFAULT (now());
+
+ALTER TABLE "room_availability"
+  ADD COLUMN "version" integer NOT NULL DEFAULT 1,
+  ADD COLUMN "updated_at" timestamptz NOT NULL DEFAULT (now());
\ No newline at end of file
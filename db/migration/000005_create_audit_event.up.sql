@@ -0,0 +1,24 @@
+CREATE TABLE "audit_event" (
+  "id" bigserial PRIMARY KEY,
+  "occurred_at" timestamptz NOT NULL DEFAULT (now()),
+  "actor" varchar NOT NULL,
+  "action" varchar NOT NULL,
+  "room_id" integer,
+  "date" date,
+  "before" jsonb,
+  "after" jsonb,
+  "request_id" varchar
+);
+
+CREATE INDEX ON "audit_event" ("room_id", "id");
+
+-- Events outlive the rooms they describe, so room_id has no foreign key, and they are never modified.
+CREATE FUNCTION "audit_event_append_only"() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_event is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "audit_event_append_only"
+  BEFORE UPDATE OR DELETE OR TRUNCATE ON "audit_event"
+  FOR EACH STATEMENT EXECUTE FUNCTION "audit_event_append_only"();
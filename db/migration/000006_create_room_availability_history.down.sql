@@ -0,0 +1,3 @@
+DROP VIEW IF EXISTS "room_night";
+
+DROP TABLE IF EXISTS "room_availability_history";
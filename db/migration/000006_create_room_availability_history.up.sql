@@ -0,0 +1,21 @@
+-- Past nights are archived here instead of being deleted, for year-over-year comparisons.
+-- Archived nights outlive their room, so room_id has no foreign key.
+CREATE TABLE "room_availability_history" (
+  "room_id" integer NOT NULL,
+  "date" date NOT NULL,
+  "is_available" boolean NOT NULL,
+  "night_rate" integer NOT NULL,
+  "archived_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("room_id", "date")
+);
+
+-- Every night of every room, current or archived. A night present in both tables,
+-- e.g. after a past night was imported again, is taken from the current calendar.
+CREATE VIEW "room_night" AS
+SELECT "room_id", "date", "is_available", "night_rate" FROM "room_availability"
+UNION ALL
+SELECT h."room_id", h."date", h."is_available", h."night_rate" FROM "room_availability_history" AS h
+WHERE NOT EXISTS (
+  SELECT 1 FROM "room_availability" AS a
+  WHERE a."room_id" = h."room_id" AND a."date" = h."date"
+);
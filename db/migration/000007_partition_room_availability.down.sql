@@ -0,0 +1,36 @@
+DROP VIEW IF EXISTS "room_night";
+
+ALTER TABLE "room_availability" RENAME TO "room_availability_partitioned";
+ALTER INDEX "room_availability_room_id_date_idx" RENAME TO "room_availability_partitioned_room_id_date_idx";
+ALTER TABLE "room_availability_partitioned" RENAME CONSTRAINT "room_availability_room_id_fkey" TO "room_availability_partitioned_room_id_fkey";
+
+CREATE TABLE "room_availability" (
+  "room_id" integer NOT NULL,
+  "date" date NOT NULL,
+  "is_available" boolean NOT NULL,
+  "night_rate" integer NOT NULL,
+  "version" integer NOT NULL DEFAULT 1,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX "room_availability_room_id_date_idx" ON "room_availability" ("room_id", "date");
+
+ALTER TABLE "room_availability" ADD CONSTRAINT "room_availability_room_id_fkey" FOREIGN KEY ("room_id") REFERENCES "room" ("room_id");
+
+INSERT INTO "room_availability" ("room_id", "date", "is_available", "night_rate", "version", "updated_at")
+SELECT "room_id", "date", "is_available", "night_rate", "version", "updated_at" FROM "room_availability_partitioned";
+
+-- Dropping the partitioned table drops every partition.
+DROP TABLE "room_availability_partitioned";
+
+DROP FUNCTION IF EXISTS "archive_room_availability_partitions"(date);
+DROP FUNCTION IF EXISTS "create_room_availability_partition"(date);
+
+CREATE VIEW "room_night" AS
+SELECT "room_id", "date", "is_available", "night_rate" FROM "room_availability"
+UNION ALL
+SELECT h."room_id", h."date", h."is_available", h."night_rate" FROM "room_availability_history" AS h
+WHERE NOT EXISTS (
+  SELECT 1 FROM "room_availability" AS a
+  WHERE a."room_id" = h."room_id" AND a."date" = h."date"
+);
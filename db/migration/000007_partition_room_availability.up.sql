@@ -0,0 +1,105 @@
+-- The view depends on the table being replaced; it is recreated at the end.
+DROP VIEW "room_night";
+
+ALTER TABLE "room_availability" RENAME TO "room_availability_unpartitioned";
+ALTER INDEX "room_availability_room_id_date_idx" RENAME TO "room_availability_unpartitioned_room_id_date_idx";
+ALTER TABLE "room_availability_unpartitioned" RENAME CONSTRAINT "room_availability_room_id_fkey" TO "room_availability_unpartitioned_room_id_fkey";
+
+-- Nights are partitioned by month. Nights outside of every monthly partition go to the default partition.
+CREATE TABLE "room_availability" (
+  "room_id" integer NOT NULL,
+  "date" date NOT NULL,
+  "is_available" boolean NOT NULL,
+  "night_rate" integer NOT NULL,
+  "version" integer NOT NULL DEFAULT 1,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+) PARTITION BY RANGE ("date");
+
+CREATE UNIQUE INDEX "room_availability_room_id_date_idx" ON "room_availability" ("room_id", "date");
+
+ALTER TABLE "room_availability" ADD CONSTRAINT "room_availability_room_id_fkey" FOREIGN KEY ("room_id") REFERENCES "room" ("room_id");
+
+CREATE TABLE "room_availability_default" PARTITION OF "room_availability" DEFAULT;
+
+-- create_room_availability_partition creates the partition of the month containing the given night,
+-- moving its nights out of the default partition. It returns false if the partition already exists.
+CREATE FUNCTION "create_room_availability_partition"(night date) RETURNS boolean AS $$
+DECLARE
+  first_night date := date_trunc('month', night)::date;
+  next_month date := (date_trunc('month', night) + interval '1 month')::date;
+  partition_name text := 'room_availability_' || to_char(night, 'YYYY_MM');
+BEGIN
+  IF to_regclass(partition_name) IS NOT NULL THEN
+    RETURN FALSE;
+  END IF;
+
+  EXECUTE format('CREATE TABLE %I (LIKE room_availability INCLUDING DEFAULTS INCLUDING CONSTRAINTS)', partition_name);
+  EXECUTE format(
+    'WITH moved AS (DELETE FROM room_availability_default WHERE date >= %L AND date < %L RETURNING *) '
+    'INSERT INTO %I SELECT * FROM moved',
+    first_night, next_month, partition_name);
+  EXECUTE format('ALTER TABLE room_availability ATTACH PARTITION %I FOR VALUES FROM (%L) TO (%L)',
+    partition_name, first_night, next_month);
+  RETURN TRUE;
+END;
+$$ LANGUAGE plpgsql;
+
+-- archive_room_availability_partitions moves the nights of the monthly partitions ending on or before
+-- the given date, and the nights of the default partition before it, to room_availability_history.
+-- The emptied monthly partitions are detached and dropped. It returns the number of dropped partitions.
+CREATE FUNCTION "archive_room_availability_partitions"(before date) RETURNS integer AS $$
+DECLARE
+  partition_name text;
+  archived integer := 0;
+BEGIN
+  WITH moved AS (
+    DELETE FROM room_availability_default WHERE date < before
+    RETURNING room_id, date, is_available, night_rate
+  )
+  INSERT INTO room_availability_history (room_id, date, is_available, night_rate)
+  SELECT room_id, date, is_available, night_rate FROM moved
+  ON CONFLICT (room_id, date) DO UPDATE
+  SET is_available = EXCLUDED.is_available, night_rate = EXCLUDED.night_rate, archived_at = now();
+
+  FOR partition_name IN
+    SELECT c.relname FROM pg_inherits AS i JOIN pg_class AS c ON c.oid = i.inhrelid
+    WHERE i.inhparent = 'room_availability'::regclass AND c.relname ~ '^room_availability_\d{4}_\d{2}$'
+    ORDER BY c.relname
+  LOOP
+    EXIT WHEN to_date(right(partition_name, 7), 'YYYY_MM') + interval '1 month' > before;
+
+    EXECUTE format(
+      'INSERT INTO room_availability_history (room_id, date, is_available, night_rate) '
+      'SELECT room_id, date, is_available, night_rate FROM %I '
+      'ON CONFLICT (room_id, date) DO UPDATE '
+      'SET is_available = EXCLUDED.is_available, night_rate = EXCLUDED.night_rate, archived_at = now()',
+      partition_name);
+    EXECUTE format('ALTER TABLE room_availability DETACH PARTITION %I', partition_name);
+    EXECUTE format('DROP TABLE %I', partition_name);
+    archived := archived + 1;
+  END LOOP;
+  RETURN archived;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Create the partitions of the existing nights and of the next 12 months, then move the nights.
+SELECT create_room_availability_partition(month::date)
+FROM generate_series(
+  (SELECT date_trunc('month', LEAST(MIN("date"), CURRENT_DATE)) FROM "room_availability_unpartitioned"),
+  (SELECT date_trunc('month', GREATEST(MAX("date"), CURRENT_DATE + 365)) FROM "room_availability_unpartitioned"),
+  interval '1 month'
+) AS month;
+
+INSERT INTO "room_availability" ("room_id", "date", "is_available", "night_rate", "version", "updated_at")
+SELECT "room_id", "date", "is_available", "night_rate", "version", "updated_at" FROM "room_availability_unpartitioned";
+
+DROP TABLE "room_availability_unpartitioned";
+
+CREATE VIEW "room_night" AS
+SELECT "room_id", "date", "is_available", "night_rate" FROM "room_availability"
+UNION ALL
+SELECT h."room_id", h."date", h."is_available", h."night_rate" FROM "room_availability_history" AS h
+WHERE NOT EXISTS (
+  SELECT 1 FROM "room_availability" AS a
+  WHERE a."room_id" = h."room_id" AND a."date" = h."date"
+);
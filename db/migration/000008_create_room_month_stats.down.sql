@@ -0,0 +1,47 @@
+DROP TRIGGER IF EXISTS "room_month_stats_insert" ON "room_availability";
+DROP TRIGGER IF EXISTS "room_month_stats_update" ON "room_availability";
+DROP TRIGGER IF EXISTS "room_month_stats_delete" ON "room_availability";
+
+DROP FUNCTION IF EXISTS "refresh_room_month_stats_on_write"();
+
+-- archive_room_availability_partitions moves the nights of the monthly partitions ending on or before
+-- the given date, and the nights of the default partition before it, to room_availability_history.
+-- The emptied monthly partitions are detached and dropped. It returns the number of dropped partitions.
+CREATE OR REPLACE FUNCTION "archive_room_availability_partitions"(before date) RETURNS integer AS $$
+DECLARE
+  partition_name text;
+  archived integer := 0;
+BEGIN
+  WITH moved AS (
+    DELETE FROM room_availability_default WHERE date < before
+    RETURNING room_id, date, is_available, night_rate
+  )
+  INSERT INTO room_availability_history (room_id, date, is_available, night_rate)
+  SELECT room_id, date, is_available, night_rate FROM moved
+  ON CONFLICT (room_id, date) DO UPDATE
+  SET is_available = EXCLUDED.is_available, night_rate = EXCLUDED.night_rate, archived_at = now();
+
+  FOR partition_name IN
+    SELECT c.relname FROM pg_inherits AS i JOIN pg_class AS c ON c.oid = i.inhrelid
+    WHERE i.inhparent = 'room_availability'::regclass AND c.relname ~ '^room_availability_\d{4}_\d{2}$'
+    ORDER BY c.relname
+  LOOP
+    EXIT WHEN to_date(right(partition_name, 7), 'YYYY_MM') + interval '1 month' > before;
+
+    EXECUTE format(
+      'INSERT INTO room_availability_history (room_id, date, is_available, night_rate) '
+      'SELECT room_id, date, is_available, night_rate FROM %I '
+      'ON CONFLICT (room_id, date) DO UPDATE '
+      'SET is_available = EXCLUDED.is_available, night_rate = EXCLUDED.night_rate, archived_at = now()',
+      partition_name);
+    EXECUTE format('ALTER TABLE room_availability DETACH PARTITION %I', partition_name);
+    EXECUTE format('DROP TABLE %I', partition_name);
+    archived := archived + 1;
+  END LOOP;
+  RETURN archived;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP FUNCTION IF EXISTS "refresh_room_month_stats"(integer[], date[]);
+
+DROP TABLE IF EXISTS "room_month_stats";
@@ -0,0 +1,143 @@
+-- The statistics of the nights of each room in each month, kept up to date by triggers on room_availability.
+CREATE TABLE "room_month_stats" (
+  "room_id" integer NOT NULL REFERENCES "room" ("room_id") ON DELETE CASCADE,
+  "month" date NOT NULL,
+  "nights" integer NOT NULL,
+  "booked_nights" integer NOT NULL,
+  "revenue" bigint NOT NULL,
+  "rate_sum" bigint NOT NULL,
+  "lowest_rate" integer NOT NULL,
+  "highest_rate" integer NOT NULL,
+  "refreshed_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("room_id", "month")
+);
+
+-- refresh_room_month_stats recomputes the statistics of the months of the given nights from room_availability,
+-- deleting those of months left without nights. Refreshes of the same room are serialized, so that each one
+-- sees the nights written by the transactions that refreshed it before.
+CREATE FUNCTION "refresh_room_month_stats"(room_ids integer[], dates date[]) RETURNS void AS $$
+BEGIN
+  PERFORM pg_advisory_xact_lock(7344004, rooms.room_id)
+  FROM (SELECT DISTINCT room_id FROM unnest(room_ids) AS r(room_id) WHERE room_id IS NOT NULL ORDER BY room_id) AS rooms;
+
+  WITH touched AS (
+    SELECT DISTINCT t.room_id, date_trunc('month', t.date)::date AS month
+    FROM unnest(room_ids, dates) AS t(room_id, date)
+    WHERE t.room_id IS NOT NULL AND t.date IS NOT NULL
+  ), actual AS (
+    SELECT
+      t.room_id,
+      t.month,
+      COUNT(a.date)::integer AS nights,
+      COUNT(a.date) FILTER (WHERE NOT a.is_available)::integer AS booked_nights,
+      COALESCE(SUM(a.night_rate) FILTER (WHERE NOT a.is_available), 0)::bigint AS revenue,
+      COALESCE(SUM(a.night_rate), 0)::bigint AS rate_sum,
+      COALESCE(MIN(a.night_rate), 0) AS lowest_rate,
+      COALESCE(MAX(a.night_rate), 0) AS highest_rate
+    FROM touched AS t
+    LEFT JOIN room_availability AS a
+      ON a.room_id = t.room_id AND a.date >= t.month AND a.date < t.month + interval '1 month'
+    GROUP BY t.room_id, t.month
+  ), emptied AS (
+    DELETE FROM room_month_stats AS s USING actual
+    WHERE s.room_id = actual.room_id AND s.month = actual.month AND actual.nights = 0
+  )
+  INSERT INTO room_month_stats (room_id, month, nights, booked_nights, revenue, rate_sum, lowest_rate, highest_rate)
+  SELECT room_id, month, nights, booked_nights, revenue, rate_sum, lowest_rate, highest_rate
+  FROM actual
+  WHERE nights > 0
+  ON CONFLICT (room_id, month) DO UPDATE
+  SET nights = EXCLUDED.nights,
+    booked_nights = EXCLUDED.booked_nights,
+    revenue = EXCLUDED.revenue,
+    rate_sum = EXCLUDED.rate_sum,
+    lowest_rate = EXCLUDED.lowest_rate,
+    highest_rate = EXCLUDED.highest_rate,
+    refreshed_at = now();
+END;
+$$ LANGUAGE plpgsql;
+
+-- Only the months written by a statement are refreshed, once per statement, so bulk loads stay cheap.
+CREATE FUNCTION "refresh_room_month_stats_on_write"() RETURNS trigger AS $$
+DECLARE
+  room_ids integer[];
+  dates date[];
+BEGIN
+  IF TG_OP IN ('INSERT', 'UPDATE') THEN
+    SELECT array_agg(room_id), array_agg(date) INTO room_ids, dates FROM new_nights;
+    PERFORM refresh_room_month_stats(room_ids, dates);
+  END IF;
+  IF TG_OP IN ('UPDATE', 'DELETE') THEN
+    SELECT array_agg(room_id), array_agg(date) INTO room_ids, dates FROM old_nights;
+    PERFORM refresh_room_month_stats(room_ids, dates);
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "room_month_stats_insert" AFTER INSERT ON "room_availability"
+REFERENCING NEW TABLE AS new_nights
+FOR EACH STATEMENT EXECUTE FUNCTION refresh_room_month_stats_on_write();
+
+CREATE TRIGGER "room_month_stats_update" AFTER UPDATE ON "room_availability"
+REFERENCING OLD TABLE AS old_nights NEW TABLE AS new_nights
+FOR EACH STATEMENT EXECUTE FUNCTION refresh_room_month_stats_on_write();
+
+CREATE TRIGGER "room_month_stats_delete" AFTER DELETE ON "room_availability"
+REFERENCING OLD TABLE AS old_nights
+FOR EACH STATEMENT EXECUTE FUNCTION refresh_room_month_stats_on_write();
+
+-- Statements on the partitions themselves do not fire the triggers, so archiving partitions refreshes
+-- the statistics of the archived months explicitly.
+CREATE OR REPLACE FUNCTION "archive_room_availability_partitions"(before date) RETURNS integer AS $$
+DECLARE
+  partition_name text;
+  archived integer := 0;
+  room_ids integer[];
+  months date[];
+BEGIN
+  WITH moved AS (
+    DELETE FROM room_availability_default WHERE date < before
+    RETURNING room_id, date, is_available, night_rate
+  )
+  INSERT INTO room_availability_history (room_id, date, is_available, night_rate)
+  SELECT room_id, date, is_available, night_rate FROM moved
+  ON CONFLICT (room_id, date) DO UPDATE
+  SET is_available = EXCLUDED.is_available, night_rate = EXCLUDED.night_rate, archived_at = now();
+
+  FOR partition_name IN
+    SELECT c.relname FROM pg_inherits AS i JOIN pg_class AS c ON c.oid = i.inhrelid
+    WHERE i.inhparent = 'room_availability'::regclass AND c.relname ~ '^room_availability_\d{4}_\d{2}$'
+    ORDER BY c.relname
+  LOOP
+    EXIT WHEN to_date(right(partition_name, 7), 'YYYY_MM') + interval '1 month' > before;
+
+    EXECUTE format(
+      'INSERT INTO room_availability_history (room_id, date, is_available, night_rate) '
+      'SELECT room_id, date, is_available, night_rate FROM %I '
+      'ON CONFLICT (room_id, date) DO UPDATE '
+      'SET is_available = EXCLUDED.is_available, night_rate = EXCLUDED.night_rate, archived_at = now()',
+      partition_name);
+    EXECUTE format('ALTER TABLE room_availability DETACH PARTITION %I', partition_name);
+    EXECUTE format('DROP TABLE %I', partition_name);
+    archived := archived + 1;
+  END LOOP;
+
+  SELECT array_agg(room_id), array_agg(month) INTO room_ids, months FROM room_month_stats WHERE month < before;
+  PERFORM refresh_room_month_stats(room_ids, months);
+  RETURN archived;
+END;
+$$ LANGUAGE plpgsql;
+
+INSERT INTO "room_month_stats" ("room_id", "month", "nights", "booked_nights", "revenue", "rate_sum", "lowest_rate", "highest_rate")
+SELECT
+  "room_id",
+  date_trunc('month', "date")::date,
+  COUNT(*),
+  COUNT(*) FILTER (WHERE NOT "is_available"),
+  COALESCE(SUM("night_rate") FILTER (WHERE NOT "is_available"), 0),
+  SUM("night_rate"),
+  MIN("night_rate"),
+  MAX("night_rate")
+FROM "room_availability"
+GROUP BY "room_id", date_trunc('month', "date");
@@ -0,0 +1,57 @@
+-- Columns cannot be dropped from a view, so it is recreated.
+DROP VIEW IF EXISTS "room_night";
+
+CREATE VIEW "room_night" AS
+SELECT "room_id", "date", "is_available", "night_rate" FROM "room_availability"
+UNION ALL
+SELECT h."room_id", h."date", h."is_available", h."night_rate" FROM "room_availability_history" AS h
+WHERE NOT EXISTS (
+  SELECT 1 FROM "room_availability" AS a
+  WHERE a."room_id" = h."room_id" AND a."date" = h."date"
+);
+
+CREATE OR REPLACE FUNCTION "archive_room_availability_partitions"(before date) RETURNS integer AS $$
+DECLARE
+  partition_name text;
+  archived integer := 0;
+  room_ids integer[];
+  months date[];
+BEGIN
+  WITH moved AS (
+    DELETE FROM room_availability_default WHERE date < before
+    RETURNING room_id, date, is_available, night_rate
+  )
+  INSERT INTO room_availability_history (room_id, date, is_available, night_rate)
+  SELECT room_id, date, is_available, night_rate FROM moved
+  ON CONFLICT (room_id, date) DO UPDATE
+  SET is_available = EXCLUDED.is_available, night_rate = EXCLUDED.night_rate, archived_at = now();
+
+  FOR partition_name IN
+    SELECT c.relname FROM pg_inherits AS i JOIN pg_class AS c ON c.oid = i.inhrelid
+    WHERE i.inhparent = 'room_availability'::regclass AND c.relname ~ '^room_availability_\d{4}_\d{2}$'
+    ORDER BY c.relname
+  LOOP
+    EXIT WHEN to_date(right(partition_name, 7), 'YYYY_MM') + interval '1 month' > before;
+
+    EXECUTE format(
+      'INSERT INTO room_availability_history (room_id, date, is_available, night_rate) '
+      'SELECT room_id, date, is_available, night_rate FROM %I '
+      'ON CONFLICT (room_id, date) DO UPDATE '
+      'SET is_available = EXCLUDED.is_available, night_rate = EXCLUDED.night_rate, archived_at = now()',
+      partition_name);
+    EXECUTE format('ALTER TABLE room_availability DETACH PARTITION %I', partition_name);
+    EXECUTE format('DROP TABLE %I', partition_name);
+    archived := archived + 1;
+  END LOOP;
+
+  SELECT array_agg(room_id), array_agg(month) INTO room_ids, months FROM room_month_stats WHERE month < before;
+  PERFORM refresh_room_month_stats(room_ids, months);
+  RETURN archived;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS "room_availability_booked_at" ON "room_availability";
+DROP FUNCTION IF EXISTS "set_room_availability_booked_at"();
+
+ALTER TABLE "room_availability_history" DROP COLUMN IF EXISTS "booked_at";
+ALTER TABLE "room_availability" DROP COLUMN IF EXISTS "booked_at";
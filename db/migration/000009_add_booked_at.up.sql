@@ -0,0 +1,71 @@
+-- The time a night was booked, kept while it stays booked. There are no reservations in the database,
+-- so this is what measures how far ahead of arrival nights get booked.
+ALTER TABLE "room_availability" ADD COLUMN "booked_at" timestamptz;
+ALTER TABLE "room_availability_history" ADD COLUMN "booked_at" timestamptz;
+
+-- Nights booked before the column existed are left without a booking time: their updated_at was set
+-- when migration 000004 added it, not when they were booked, and the booking pace leaves them out.
+
+CREATE FUNCTION "set_room_availability_booked_at"() RETURNS trigger AS $$
+BEGIN
+  IF NEW.is_available THEN
+    NEW.booked_at := NULL;
+  ELSIF TG_OP = 'INSERT' OR OLD.is_available THEN
+    NEW.booked_at := COALESCE(NEW.booked_at, now());
+  END IF;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "room_availability_booked_at" BEFORE INSERT OR UPDATE ON "room_availability"
+FOR EACH ROW EXECUTE FUNCTION set_room_availability_booked_at();
+
+CREATE OR REPLACE VIEW "room_night" AS
+SELECT "room_id", "date", "is_available", "night_rate", "booked_at" FROM "room_availability"
+UNION ALL
+SELECT h."room_id", h."date", h."is_available", h."night_rate", h."booked_at" FROM "room_availability_history" AS h
+WHERE NOT EXISTS (
+  SELECT 1 FROM "room_availability" AS a
+  WHERE a."room_id" = h."room_id" AND a."date" = h."date"
+);
+
+-- Archived nights keep their booking time.
+CREATE OR REPLACE FUNCTION "archive_room_availability_partitions"(before date) RETURNS integer AS $$
+DECLARE
+  partition_name text;
+  archived integer := 0;
+  room_ids integer[];
+  months date[];
+BEGIN
+  WITH moved AS (
+    DELETE FROM room_availability_default WHERE date < before
+    RETURNING room_id, date, is_available, night_rate, booked_at
+  )
+  INSERT INTO room_availability_history (room_id, date, is_available, night_rate, booked_at)
+  SELECT room_id, date, is_available, night_rate, booked_at FROM moved
+  ON CONFLICT (room_id, date) DO UPDATE
+  SET is_available = EXCLUDED.is_available, night_rate = EXCLUDED.night_rate, booked_at = EXCLUDED.booked_at, archived_at = now();
+
+  FOR partition_name IN
+    SELECT c.relname FROM pg_inherits AS i JOIN pg_class AS c ON c.oid = i.inhrelid
+    WHERE i.inhparent = 'room_availability'::regclass AND c.relname ~ '^room_availability_\d{4}_\d{2}$'
+    ORDER BY c.relname
+  LOOP
+    EXIT WHEN to_date(right(partition_name, 7), 'YYYY_MM') + interval '1 month' > before;
+
+    EXECUTE format(
+      'INSERT INTO room_availability_history (room_id, date, is_available, night_rate, booked_at) '
+      'SELECT room_id, date, is_available, night_rate, booked_at FROM %I '
+      'ON CONFLICT (room_id, date) DO UPDATE '
+      'SET is_available = EXCLUDED.is_available, night_rate = EXCLUDED.night_rate, booked_at = EXCLUDED.booked_at, archived_at = now()',
+      partition_name);
+    EXECUTE format('ALTER TABLE room_availability DETACH PARTITION %I', partition_name);
+    EXECUTE format('DROP TABLE %I', partition_name);
+    archived := archived + 1;
+  END LOOP;
+
+  SELECT array_agg(room_id), array_agg(month) INTO room_ids, months FROM room_month_stats WHERE month < before;
+  PERFORM refresh_room_month_stats(room_ids, months);
+  RETURN archived;
+END;
+$$ LANGUAGE plpgsql;
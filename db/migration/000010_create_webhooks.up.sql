@@ -0,0 +1,30 @@
+CREATE TABLE "webhook_subscription" (
+  "id" bigserial PRIMARY KEY,
+  "url" varchar NOT NULL,
+  "secret" varchar NOT NULL,
+  "event_types" varchar[] NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+-- Deliveries are queued in the transaction of the write they notify about, and form the delivery log.
+-- A replay is a new delivery of the same event.
+CREATE TABLE "webhook_delivery" (
+  "id" bigserial PRIMARY KEY,
+  "subscription_id" bigint NOT NULL REFERENCES "webhook_subscription" ("id") ON DELETE CASCADE,
+  "event_id" uuid NOT NULL,
+  "event_type" varchar NOT NULL,
+  "room_id" integer NOT NULL,
+  "payload" jsonb NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending' CHECK ("status" IN ('pending', 'delivered', 'failed')),
+  "attempts" integer NOT NULL DEFAULT 0,
+  "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+  "last_attempt_at" timestamptz,
+  "response_status" integer,
+  "last_error" varchar,
+  "replay_of" bigint REFERENCES "webhook_delivery" ("id") ON DELETE SET NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "webhook_delivery" ("subscription_id", "id");
+
+CREATE INDEX ON "webhook_delivery" ("next_attempt_at", "id") WHERE "status" = 'pending';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "webhook_delivery_event_key";
+
+DROP TABLE IF EXISTS "outbox";
@@ -0,0 +1,17 @@
+-- Events are written in the transaction of the change they describe, and published afterwards by the dispatcher.
+CREATE TABLE "outbox" (
+  "id" bigserial PRIMARY KEY,
+  "event_id" uuid UNIQUE NOT NULL,
+  "event_type" varchar NOT NULL,
+  "room_id" integer NOT NULL,
+  "payload" jsonb NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "published_at" timestamptz
+);
+
+CREATE INDEX ON "outbox" ("id") WHERE "published_at" IS NULL;
+
+CREATE INDEX ON "outbox" ("published_at") WHERE "published_at" IS NOT NULL;
+
+-- An event published again queues no second delivery; replays are new deliveries of the same event.
+CREATE UNIQUE INDEX "webhook_delivery_event_key" ON "webhook_delivery" ("subscription_id", "event_id") WHERE "replay_of" IS NULL;
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS "outbox_published" ON "outbox";
+
+DROP FUNCTION IF EXISTS "notify_outbox_published"();
+
+ALTER TABLE "outbox" DROP COLUMN IF EXISTS "position";
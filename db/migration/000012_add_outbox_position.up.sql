@@ -0,0 +1,21 @@
+-- The position of an event in the stream of published events. Positions are assigned by the single dispatcher in
+-- the order it publishes events, so unlike IDs, which transactions of different rooms commit in any order, they
+-- are committed in increasing order and a reader resuming after a position misses no event.
+ALTER TABLE "outbox" ADD COLUMN "position" bigint UNIQUE;
+
+CREATE SEQUENCE "outbox_position_seq" OWNED BY "outbox"."position";
+
+CREATE INDEX ON "outbox" ("room_id", "position") WHERE "position" IS NOT NULL;
+
+-- Listeners on the outbox_published channel are notified of every published event once its position is committed.
+CREATE FUNCTION "notify_outbox_published"() RETURNS trigger AS $$
+BEGIN
+  PERFORM pg_notify('outbox_published', NEW.position::text);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "outbox_published"
+AFTER UPDATE OF "position" ON "outbox"
+FOR EACH ROW WHEN (NEW.position IS NOT NULL AND OLD.position IS NULL)
+EXECUTE FUNCTION "notify_outbox_published"();
@@ -0,0 +1,126 @@
+// Package migration embeds the SQL schema migrations and applies them with golang-migrate.
+package migration
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/pgx/v5" // Registers the `pgx5` database driver.
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5"
+)
+
+// files holds the up and down migrations of this directory.
+//
+//go:embed *.sql
+var files embed.FS
+
+// autoMigrateLockID is the key of the advisory lock serializing automatic migrations across replicas.
+const autoMigrateLockID = 7_344_001
+
+// Migrator applies the embedded migrations to a database.
+type Migrator struct {
+	migrate *migrate.Migrate
+}
+
+// New creates a Migrator for the database at the given PostgreSQL connection string.
+func New(dbSource string) (*Migrator, error) {
+	source, err := iofs.New(files, ".")
+	if err != nil {
+		return nil, fmt.Errorf("cannot read embedded migrations: %w", err)
+	}
+
+	databaseURL, err := driverURL(dbSource)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := migrate.NewWithSourceInstance("iofs", source, databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create migrator: %w", err)
+	}
+	return &Migrator{migrate: m}, nil
+}
+
+// driverURL rewrites a PostgreSQL connection URL to select the pgx v5 migrate driver.
+func driverURL(dbSource string) (string, error) {
+	u, err := url.Parse(dbSource)
+	if err != nil {
+		return "", fmt.Errorf("invalid database source: %w", err)
+	}
+	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		return "", fmt.Errorf("unsupported database scheme %q", u.Scheme)
+	}
+	u.Scheme = "pgx5"
+	return u.String(), nil
+}
+
+// Up applies all pending migrations. It is not an error if the schema is already up to date.
+func (migrator *Migrator) Up() error {
+	return ignoreNoChange(migrator.migrate.Up())
+}
+
+// Down rolls back the given number of migrations, or all of them if steps is zero.
+func (migrator *Migrator) Down(steps int) error {
+	if steps == 0 {
+		return ignoreNoChange(migrator.migrate.Down())
+	}
+	return ignoreNoChange(migrator.migrate.Steps(-steps))
+}
+
+// Version returns the currently applied version and whether the last migration failed half way.
+func (migrator *Migrator) Version() (version uint, dirty bool, err error) {
+	version, dirty, err = migrator.migrate.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+// Force sets the schema version without running any migration, clearing the dirty flag.
+// It is used to recover after a migration failed half way and was fixed by hand.
+func (migrator *Migrator) Force(version int) error {
+	return migrator.migrate.Force(version)
+}
+
+// Close releases the database connection of the migrator.
+func (migrator *Migrator) Close() error {
+	sourceErr, databaseErr := migrator.migrate.Close()
+	return errors.Join(sourceErr, databaseErr)
+}
+
+// ignoreNoChange treats an up to date schema as success.
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+	return err
+}
+
+// AutoMigrate applies all pending migrations while holding a PostgreSQL advisory lock,
+// so that replicas starting at the same time apply them exactly once.
+func AutoMigrate(ctx context.Context, dbSource string) error {
+	conn, err := pgx.Connect(ctx, dbSource)
+	if err != nil {
+		return fmt.Errorf("cannot connect to the database: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	// The lock is bound to this session and released when it ends, even if the process crashes.
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", autoMigrateLockID); err != nil {
+		return fmt.Errorf("cannot acquire migration lock: %w", err)
+	}
+	defer conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", autoMigrateLockID)
+
+	migrator, err := New(dbSource)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	return migrator.Up()
+}
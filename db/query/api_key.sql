@@ -0,0 +1,16 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_key (
+  name,
+  key_hash
+) VALUES (
+  $1, $2
+)
+RETURNING *;
+
+-- name: GetAPIKeyByHash :one
+SELECT * FROM api_key
+WHERE key_hash = $1 LIMIT 1;
+
+-- name: ListAPIKeys :many
+SELECT * FROM api_key
+ORDER BY id;
\ No newline at end of file
@@ -0,0 +1,24 @@
+-- name: CreateAuditEvent :one
+INSERT INTO audit_event (
+  actor,
+  action,
+  room_id,
+  date,
+  before,
+  after,
+  request_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: ListRoomAuditEvents :many
+SELECT * FROM audit_event
+WHERE room_id = sqlc.arg(room_id)
+  AND (sqlc.narg(date)::date IS NULL OR date = sqlc.narg(date))
+  AND (sqlc.narg(actor)::varchar IS NULL OR actor = sqlc.narg(actor))
+  AND (sqlc.narg(since)::timestamptz IS NULL OR occurred_at >= sqlc.narg(since))
+  AND (sqlc.narg(until)::timestamptz IS NULL OR occurred_at < sqlc.narg(until))
+ORDER BY id DESC
+LIMIT sqlc.arg(row_limit)
+OFFSET sqlc.arg(row_offset);
@@ -0,0 +1,37 @@
+-- name: CreateOutboxEvent :one
+-- The transaction-level advisory lock on the room makes the events of a room committed in the order of their
+-- IDs: a transaction writing an event for the room waits until the previous one commits or rolls back.
+WITH room_lock AS (
+  SELECT pg_advisory_xact_lock(7344007, sqlc.arg(room_id)::integer)
+)
+INSERT INTO outbox (event_id, event_type, room_id, payload)
+SELECT sqlc.arg(event_id)::uuid, sqlc.arg(event_type)::varchar, sqlc.arg(room_id)::integer, sqlc.arg(payload)::jsonb
+FROM room_lock
+RETURNING *;
+
+-- name: ListPendingOutboxEvents :many
+SELECT * FROM outbox
+WHERE published_at IS NULL AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(row_limit);
+
+-- name: MarkOutboxEventPublished :execrows
+-- The event takes the next position in the stream of published events.
+UPDATE outbox
+SET published_at = now(), position = nextval('outbox_position_seq')
+WHERE id = $1 AND published_at IS NULL;
+
+-- name: ListPublishedOutboxEvents :many
+SELECT * FROM outbox
+WHERE position > sqlc.arg(after_position)
+  AND (sqlc.narg(room_id)::integer IS NULL OR room_id = sqlc.narg(room_id))
+ORDER BY position
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetOutboxPosition :one
+-- The position of the last published event, or 0 if none was.
+SELECT COALESCE(MAX(position), 0)::bigint AS position FROM outbox;
+
+-- name: DeletePublishedOutboxEvents :execrows
+DELETE FROM outbox
+WHERE published_at < now() - sqlc.arg(retention)::interval;
@@ -0,0 +1,14 @@
+-- name: CreateRoomAvailabilityPartition :one
+SELECT create_room_availability_partition(sqlc.arg(night)::date)::boolean AS created;
+
+-- name: ArchiveRoomAvailabilityPartitions :one
+SELECT archive_room_availability_partitions(sqlc.arg(before)::date)::integer AS archived;
+
+-- name: ListRoomAvailabilityPartitions :many
+SELECT
+  c.relname::text AS name,
+  pg_get_expr(c.relpartbound, c.oid)::text AS bound
+FROM pg_inherits AS i
+JOIN pg_class AS c ON c.oid = i.inhrelid
+WHERE i.inhparent = 'room_availability'::regclass
+ORDER BY c.relname;
@@ -5,9 +5,10 @@ INSERT INTO room (
   balcony,
   fridge,
   indoor_pool,
-  gaming_console
+  gaming_console,
+  default_rate
 ) VALUES (
-  $1, $2, $3, $4, $5, $6
+  $1, $2, $3, $4, $5, $6, $7
 )
 RETURNING *;
 
@@ -27,19 +28,25 @@ OFFSET $2;
 
 -- name: UpdateMaxGuests :one
 UPDATE room
-SET max_guests = $2
+SET max_guests = $2,
+    version = version + 1,
+    updated_at = now()
 WHERE room_id = $1
 RETURNING *;
 
 -- name: UpdateRoomFridge :one
 UPDATE room
-SET fridge = $2
+SET fridge = $2,
+    version = version + 1,
+    updated_at = now()
 WHERE room_id = $1
 RETURNING *;
 
 -- name: UpdateRoomConsole :one
 UPDATE room
-SET gaming_console = $2
+SET gaming_console = $2,
+    version = version + 1,
+    updated_at = now()
 WHERE room_id = $1
 RETURNING *;
 
@@ -47,4 +54,67 @@ RETURNING *;
 DELETE FROM room WHERE room_id = $1;
 
 -- name: GetRoomCount :one
-SELECT COUNT(room_id) FROM room;
\ No newline at end of file
+SELECT COUNT(room_id) FROM room;
+
+-- name: UpsertRoom :one
+-- An existing room keeps its version and update time when none of its fields change, so reruns are no-ops.
+INSERT INTO room (
+  room_id,
+  max_guests,
+  balcony,
+  fridge,
+  indoor_pool,
+  gaming_console,
+  default_rate
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+ON CONFLICT (room_id) DO UPDATE
+SET max_guests = EXCLUDED.max_guests,
+    balcony = EXCLUDED.balcony,
+    fridge = EXCLUDED.fridge,
+    indoor_pool = EXCLUDED.indoor_pool,
+    gaming_console = EXCLUDED.gaming_console,
+    default_rate = EXCLUDED.default_rate,
+    version = CASE WHEN (room.max_guests, room.balcony, room.fridge, room.indoor_pool, room.gaming_console, room.default_rate)
+      IS DISTINCT FROM (EXCLUDED.max_guests, EXCLUDED.balcony, EXCLUDED.fridge, EXCLUDED.indoor_pool, EXCLUDED.gaming_console, EXCLUDED.default_rate)
+      THEN room.version + 1 ELSE room.version END,
+    updated_at = CASE WHEN (room.max_guests, room.balcony, room.fridge, room.indoor_pool, room.gaming_console, room.default_rate)
+      IS DISTINCT FROM (EXCLUDED.max_guests, EXCLUDED.balcony, EXCLUDED.fridge, EXCLUDED.indoor_pool, EXCLUDED.gaming_console, EXCLUDED.default_rate)
+      THEN now() ELSE room.updated_at END
+RETURNING *;
+
+-- name: UpdateRoom :one
+UPDATE room
+SET max_guests = COALESCE(sqlc.narg(max_guests), max_guests),
+    balcony = COALESCE(sqlc.narg(balcony), balcony),
+    fridge = COALESCE(sqlc.narg(fridge), fridge),
+    indoor_pool = COALESCE(sqlc.narg(indoor_pool), indoor_pool),
+    gaming_console = COALESCE(sqlc.narg(gaming_console), gaming_console),
+    default_rate = COALESCE(sqlc.narg(default_rate), default_rate),
+    version = version + 1,
+    updated_at = now()
+WHERE room_id = sqlc.arg(room_id) AND version = sqlc.arg(version)
+RETURNING *;
+
+-- name: SearchAvailableRooms :many
+-- Rooms hosting the guests, with the requested amenities and every night of the stay available, cheapest stay first.
+SELECT sqlc.embed(room), SUM(room_availability.night_rate)::bigint AS total
+FROM room
+JOIN room_availability ON room_availability.room_id = room.room_id
+WHERE room.max_guests >= sqlc.arg(guests)
+  AND (room.balcony OR NOT sqlc.arg(balcony)::boolean)
+  AND (room.fridge OR NOT sqlc.arg(fridge)::boolean)
+  AND (room.indoor_pool OR NOT sqlc.arg(indoor_pool)::boolean)
+  AND (room.gaming_console OR NOT sqlc.arg(gaming_console)::boolean)
+  AND room_availability.date >= sqlc.arg(check_in) AND room_availability.date < sqlc.arg(check_out)
+  AND room_availability.is_available
+GROUP BY room.room_id
+HAVING COUNT(*) = sqlc.arg(check_out)::date - sqlc.arg(check_in)::date
+ORDER BY total, room.room_id
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListRoomsByIDs :many
+SELECT * FROM room
+WHERE room_id = ANY(sqlc.arg(room_ids)::int[])
+ORDER BY room_id;
\ No newline at end of file
@@ -9,11 +9,23 @@ INSERT INTO room_availability (
 )
 RETURNING *;
 
+-- name: CopyRoomAvailability :copyfrom
+INSERT INTO room_availability (
+  room_id,
+  date,
+  is_available,
+  night_rate
+) VALUES (
+  $1, $2, $3, $4
+);
+
 -- name: UpdateRoomAvailability :one
 UPDATE room_availability
 SET is_available = $3,
-    night_rate = $4
-WHERE room_id = $1 AND date = $2
+    night_rate = $4,
+    version = version + 1,
+    updated_at = now()
+WHERE room_id = $1 AND date = $2 AND version = $5
 RETURNING *;
 
 -- name: GetRoomAvailabilityByDate :one
@@ -24,7 +36,9 @@ LIMIT 1;
 -- name: ListRoomAvailability :many
 SELECT date, is_available, night_rate FROM room_availability
 WHERE room_id = $1
-LIMIT 30;
+  AND date >= CURRENT_DATE
+  AND date < CURRENT_DATE + INTERVAL '30 days'
+ORDER BY date;
 
 -- name: ListAvailableDates :many
 SELECT date FROM room_availability
@@ -50,7 +64,8 @@ LIMIT 1;
 -- name: GetMaxDate :one
 SELECT date
 FROM room_availability
-ORDER BY night_rate DESC
+WHERE room_id = $1
+ORDER BY date DESC
 LIMIT 1;
 
 -- name: GetMinimumRate :one
@@ -64,19 +79,138 @@ LIMIT 1;
 
 -- name: GetAvailabilityPercentage :many
 SELECT
- EXTRACT(YEAR FROM date) AS year,
- EXTRACT(MONTH FROM date) AS month,
- CAST(COUNT(CASE WHEN is_available THEN 1 END) * 100.0 / COUNT(*) AS DECIMAL(10,2)) AS availability_percentage
-FROM room_availability
+ EXTRACT(YEAR FROM month) AS year,
+ EXTRACT(MONTH FROM month) AS month,
+ CAST((nights - booked_nights) * 100.0 / nights AS DECIMAL(10,2)) AS availability_percentage
+FROM room_month_stats
 WHERE room_id = $1
-GROUP BY year, month
-ORDER BY year, month;
+ORDER BY room_month_stats.month;
 
 -- name: GetDateCount :one
-SELECT COUNT(DISTINCT date) FROM room_availability;
+SELECT COUNT(date) FROM room_availability
+WHERE room_id = $1;
 
--- name: DeleteOldRoomAvailabilityData :exec
-DELETE FROM room_availability WHERE date < CURRENT_DATE;
+-- name: GetCurrentDate :one
+SELECT CURRENT_DATE::date AS today;
 
--- name: DeleteAllAvailabilityForRoom :exec
-DELETE FROM room_availability WHERE room_id = $1;
\ No newline at end of file
+-- name: ArchiveOldRoomAvailabilityData :exec
+WITH archived AS (
+  DELETE FROM room_availability
+  WHERE date < CURRENT_DATE
+  RETURNING room_id, date, is_available, night_rate, booked_at
+)
+INSERT INTO room_availability_history (room_id, date, is_available, night_rate, booked_at)
+SELECT room_id, date, is_available, night_rate, booked_at FROM archived
+ON CONFLICT (room_id, date) DO UPDATE
+SET is_available = EXCLUDED.is_available, night_rate = EXCLUDED.night_rate, booked_at = EXCLUDED.booked_at, archived_at = now();
+
+-- name: ArchiveRoomPastAvailability :many
+WITH archived AS (
+  DELETE FROM room_availability
+  WHERE room_id = $1 AND date < CURRENT_DATE
+  RETURNING room_id, date, is_available, night_rate, booked_at
+)
+INSERT INTO room_availability_history (room_id, date, is_available, night_rate, booked_at)
+SELECT room_id, date, is_available, night_rate, booked_at FROM archived
+ON CONFLICT (room_id, date) DO UPDATE
+SET is_available = EXCLUDED.is_available, night_rate = EXCLUDED.night_rate, booked_at = EXCLUDED.booked_at, archived_at = now()
+RETURNING date;
+
+-- name: ExtendRoomAvailability :many
+INSERT INTO room_availability (
+  room_id,
+  date,
+  is_available,
+  night_rate
+)
+SELECT room.room_id, CURRENT_DATE + night.n, TRUE, room.default_rate
+FROM room, generate_series(0, sqlc.arg(horizon_days)::int - 1) AS night(n)
+WHERE room.room_id = sqlc.arg(room_id)
+ON CONFLICT (room_id, date) DO NOTHING
+RETURNING date;
+
+-- name: DeleteAllAvailabilityForRoom :many
+DELETE FROM room_availability WHERE room_id = $1
+RETURNING date;
+
+-- name: DeleteRoomAvailabilityNights :many
+DELETE FROM room_availability AS ra
+USING unnest(sqlc.arg(room_ids)::int[], sqlc.arg(dates)::date[]) AS night(room_id, date)
+WHERE ra.room_id = night.room_id AND ra.date = night.date
+RETURNING ra.room_id, ra.date;
+
+-- name: UpsertRoomAvailability :one
+INSERT INTO room_availability (
+  room_id,
+  date,
+  is_available,
+  night_rate
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (room_id, date) DO UPDATE
+SET is_available = EXCLUDED.is_available,
+    night_rate = EXCLUDED.night_rate,
+    version = room_availability.version + 1,
+    updated_at = now()
+RETURNING *;
+
+-- name: UpsertRoomAvailabilityNights :many
+-- Inserts the given nights, or updates those that exist as UpsertRoomAvailability does: their version is
+-- incremented and they keep their booking time while they stay booked. Nights that would not change are left
+-- as they are and not returned.
+INSERT INTO room_availability (
+  room_id,
+  date,
+  is_available,
+  night_rate
+)
+SELECT night.room_id, night.date, night.is_available, night.night_rate
+FROM unnest(sqlc.arg(room_ids)::int[], sqlc.arg(dates)::date[], sqlc.arg(is_available)::boolean[], sqlc.arg(night_rates)::int[]) AS night(room_id, date, is_available, night_rate)
+ON CONFLICT (room_id, date) DO UPDATE
+SET is_available = EXCLUDED.is_available,
+    night_rate = EXCLUDED.night_rate,
+    version = room_availability.version + 1,
+    updated_at = now()
+WHERE (room_availability.is_available, room_availability.night_rate) IS DISTINCT FROM (EXCLUDED.is_available, EXCLUDED.night_rate)
+RETURNING room_id, date;
+
+-- name: ListRoomNights :many
+SELECT * FROM room_availability
+WHERE room_id = sqlc.arg(room_id) AND date >= sqlc.arg(start_date) AND date < sqlc.arg(end_date)
+ORDER BY date;
+
+-- name: ListNightsForRooms :many
+SELECT * FROM room_availability
+WHERE room_id = ANY(sqlc.arg(room_ids)::int[]) AND date >= sqlc.arg(start_date) AND date < sqlc.arg(end_date)
+ORDER BY room_id, date;
+
+-- name: ListUpcomingRates :many
+-- Average, highest and lowest rates of the next 30 days of every room having nights in that window.
+SELECT
+  room_id,
+  AVG(night_rate)::float8 AS average_rate,
+  MAX(night_rate)::integer AS highest_rate,
+  MIN(night_rate)::integer AS lowest_rate
+FROM room_availability
+WHERE room_id = ANY(sqlc.arg(room_ids)::int[])
+  AND date >= CURRENT_DATE
+  AND date < CURRENT_DATE + INTERVAL '30 days'
+GROUP BY room_id
+ORDER BY room_id;
+
+-- name: ListUpcomingNightMetrics :many
+-- The nights of the next 30 days of the given rooms, with the availability percentage of their month.
+SELECT
+  a.room_id,
+  a.date,
+  a.is_available,
+  a.night_rate,
+  CAST((s.nights - s.booked_nights) * 100.0 / s.nights AS DECIMAL(10,2)) AS availability_percentage
+FROM room_availability AS a
+LEFT JOIN room_month_stats AS s
+  ON s.room_id = a.room_id AND s.month = date_trunc('month', a.date)::date
+WHERE a.room_id = ANY(sqlc.arg(room_ids)::int[])
+  AND a.date >= CURRENT_DATE
+  AND a.date < CURRENT_DATE + INTERVAL '30 days'
+ORDER BY a.room_id, a.date;
\ No newline at end of file
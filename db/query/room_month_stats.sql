@@ -0,0 +1,39 @@
+-- name: ListRoomMonthStats :many
+SELECT * FROM room_month_stats
+WHERE room_id = $1
+ORDER BY month;
+
+-- name: ListInconsistentRoomMonthStats :many
+WITH stored AS (
+  SELECT room_id, month, nights, booked_nights, revenue, rate_sum, lowest_rate, highest_rate
+  FROM room_month_stats
+), actual AS (
+  SELECT
+    room_id,
+    date_trunc('month', date)::date AS month,
+    COUNT(*)::integer AS nights,
+    COUNT(*) FILTER (WHERE NOT is_available)::integer AS booked_nights,
+    COALESCE(SUM(night_rate) FILTER (WHERE NOT is_available), 0)::bigint AS revenue,
+    SUM(night_rate)::bigint AS rate_sum,
+    MIN(night_rate) AS lowest_rate,
+    MAX(night_rate) AS highest_rate
+  FROM room_availability
+  GROUP BY room_id, date_trunc('month', date)
+), differences AS (
+  (SELECT room_id, month, nights, booked_nights, revenue, rate_sum, lowest_rate, highest_rate FROM stored
+   EXCEPT SELECT room_id, month, nights, booked_nights, revenue, rate_sum, lowest_rate, highest_rate FROM actual)
+  UNION ALL
+  (SELECT room_id, month, nights, booked_nights, revenue, rate_sum, lowest_rate, highest_rate FROM actual
+   EXCEPT SELECT room_id, month, nights, booked_nights, revenue, rate_sum, lowest_rate, highest_rate FROM stored)
+)
+SELECT DISTINCT differences.room_id, differences.month
+FROM differences
+ORDER BY differences.room_id, differences.month;
+
+-- name: RefreshRoomMonthStats :exec
+SELECT refresh_room_month_stats(sqlc.arg(room_ids)::integer[], sqlc.arg(months)::date[]);
+
+-- name: ListMonthStatsForRooms :many
+SELECT * FROM room_month_stats
+WHERE room_id = ANY(sqlc.arg(room_ids)::int[])
+ORDER BY room_id, month;
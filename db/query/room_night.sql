@@ -0,0 +1,50 @@
+-- name: GetRoomPeriodStats :one
+SELECT
+  COUNT(*) AS nights,
+  COUNT(*) FILTER (WHERE NOT is_available) AS booked_nights,
+  COALESCE(SUM(night_rate) FILTER (WHERE NOT is_available), 0)::bigint AS revenue,
+  COALESCE(AVG(night_rate), 0)::float8 AS average_rate,
+  COALESCE(MIN(night_rate), 0)::integer AS lowest_rate,
+  COALESCE(MAX(night_rate), 0)::integer AS highest_rate
+FROM room_night
+WHERE room_id = sqlc.arg(room_id)
+  AND date BETWEEN sqlc.arg(from_date)::date AND sqlc.arg(to_date)::date;
+
+-- name: GetPortfolioPeriodStats :one
+SELECT
+  COUNT(DISTINCT room_id) AS rooms,
+  COUNT(*) AS nights,
+  COUNT(*) FILTER (WHERE NOT is_available) AS booked_nights,
+  COALESCE(SUM(night_rate) FILTER (WHERE NOT is_available), 0)::bigint AS revenue,
+  COALESCE(AVG(night_rate), 0)::float8 AS average_rate,
+  COALESCE(MIN(night_rate), 0)::integer AS lowest_rate,
+  COALESCE(MAX(night_rate), 0)::integer AS highest_rate
+FROM room_night
+WHERE date BETWEEN sqlc.arg(from_date)::date AND sqlc.arg(to_date)::date;
+
+-- name: GetBookingPace :many
+-- Only counts the nights from the first recorded booking time on: the nights booked before booking times
+-- were recorded have none, so the nights left available at the time are left out as well.
+SELECT
+  CASE WHEN is_available THEN NULL ELSE date - booked_at::date END::integer AS lead_days,
+  COUNT(*) AS nights
+FROM room_night
+WHERE date < CURRENT_DATE
+  AND date >= CURRENT_DATE - sqlc.arg(lookback_days)::integer
+  AND date >= (SELECT MIN(booked_at)::date FROM room_night)
+  AND (sqlc.narg(room_id)::integer IS NULL OR room_id = sqlc.narg(room_id))
+  AND (is_available OR booked_at::date <= date)
+GROUP BY 1
+ORDER BY 1 NULLS FIRST;
+
+-- name: GetForecastNights :many
+SELECT
+  date,
+  (date - CURRENT_DATE)::integer AS days_ahead,
+  COUNT(*) AS nights,
+  COUNT(*) FILTER (WHERE NOT is_available) AS booked_nights
+FROM room_night
+WHERE date BETWEEN sqlc.arg(from_date)::date AND sqlc.arg(to_date)::date
+  AND (sqlc.narg(room_id)::integer IS NULL OR room_id = sqlc.narg(room_id))
+GROUP BY date
+ORDER BY date;
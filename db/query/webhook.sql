@@ -0,0 +1,82 @@
+-- name: CreateWebhookSubscription :one
+INSERT INTO webhook_subscription (
+  url,
+  secret,
+  event_types
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetWebhookSubscription :one
+SELECT * FROM webhook_subscription
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhookSubscriptions :many
+SELECT * FROM webhook_subscription
+ORDER BY id;
+
+-- name: DeleteWebhookSubscription :execrows
+DELETE FROM webhook_subscription
+WHERE id = $1;
+
+-- name: CreateWebhookDeliveries :execrows
+INSERT INTO webhook_delivery (subscription_id, event_id, event_type, room_id, payload)
+SELECT id, sqlc.arg(event_id)::uuid, sqlc.arg(event_type)::varchar, sqlc.arg(room_id)::integer, sqlc.arg(payload)::jsonb
+FROM webhook_subscription
+WHERE sqlc.arg(event_type)::varchar = ANY (event_types)
+ORDER BY id
+ON CONFLICT (subscription_id, event_id) WHERE replay_of IS NULL DO NOTHING;
+
+-- name: ListDueWebhookSubscriptions :many
+-- The subscriptions with due deliveries, the longest overdue first.
+SELECT subscription_id FROM webhook_delivery
+WHERE status = 'pending' AND next_attempt_at <= now()
+GROUP BY subscription_id
+ORDER BY min(next_attempt_at), subscription_id;
+
+-- name: ListDueWebhookDeliveries :many
+SELECT
+  webhook_delivery.id,
+  webhook_delivery.event_id,
+  webhook_delivery.event_type,
+  webhook_delivery.payload,
+  webhook_delivery.attempts,
+  webhook_subscription.url,
+  webhook_subscription.secret
+FROM webhook_delivery
+JOIN webhook_subscription ON webhook_subscription.id = webhook_delivery.subscription_id
+WHERE webhook_delivery.subscription_id = sqlc.arg(subscription_id)
+  AND webhook_delivery.status = 'pending' AND webhook_delivery.next_attempt_at <= now()
+ORDER BY webhook_delivery.next_attempt_at, webhook_delivery.id
+LIMIT sqlc.arg(row_limit);
+
+-- name: RecordWebhookDeliveryAttempt :one
+UPDATE webhook_delivery
+SET status = sqlc.arg(status),
+    attempts = attempts + 1,
+    last_attempt_at = now(),
+    next_attempt_at = now() + sqlc.arg(retry_delay)::interval,
+    response_status = sqlc.narg(response_status),
+    last_error = sqlc.narg(last_error)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: GetWebhookDelivery :one
+SELECT * FROM webhook_delivery
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM webhook_delivery
+WHERE subscription_id = sqlc.arg(subscription_id)
+  AND (sqlc.narg(status)::varchar IS NULL OR status = sqlc.narg(status))
+ORDER BY id DESC
+LIMIT sqlc.arg(row_limit)
+OFFSET sqlc.arg(row_offset);
+
+-- name: ReplayWebhookDelivery :one
+INSERT INTO webhook_delivery (subscription_id, event_id, event_type, room_id, payload, replay_of)
+SELECT subscription_id, event_id, event_type, room_id, payload, id
+FROM webhook_delivery
+WHERE webhook_delivery.id = $1
+RETURNING *;
@@ -0,0 +1,66 @@
+package db_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+	"github.com/vivek-344/airbnb-api/util"
+)
+
+func TestAuditEvents(t *testing.T) {
+	ctx := context.Background()
+
+	// Events cannot be deleted, so the test only looks at the events of its own actor.
+	actor := fmt.Sprintf("test-%d", time.Now().UnixNano())
+	roomID := pgtype.Int4{Int32: util.RandomInt(1_000_000, 2_000_000), Valid: true}
+	date := pgtype.Date{Time: time.Date(2024, 6, 20, 0, 0, 0, 0, time.UTC), Valid: true}
+
+	created, err := testQueries.CreateAuditEvent(ctx, db.CreateAuditEventParams{
+		Actor:     actor,
+		Action:    "availability.update",
+		RoomID:    roomID,
+		Date:      date,
+		Before:    []byte(`{"night_rate": 5000}`),
+		After:     []byte(`{"night_rate": 7000}`),
+		RequestID: pgtype.Text{String: "request", Valid: true},
+	})
+	require.NoError(t, err)
+	require.NotZero(t, created.ID)
+	require.WithinDuration(t, time.Now(), created.OccurredAt, time.Minute)
+	require.JSONEq(t, `{"night_rate": 7000}`, string(created.After))
+
+	_, err = testQueries.CreateAuditEvent(ctx, db.CreateAuditEventParams{Actor: actor, Action: "room.update", RoomID: roomID})
+	require.NoError(t, err)
+
+	events, err := testQueries.ListRoomAuditEvents(ctx, db.ListRoomAuditEventsParams{
+		RoomID:   roomID.Int32,
+		Actor:    pgtype.Text{String: actor, Valid: true},
+		RowLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, "room.update", events[0].Action)
+	require.Nil(t, events[0].Before)
+
+	events, err = testQueries.ListRoomAuditEvents(ctx, db.ListRoomAuditEventsParams{
+		RoomID:   roomID.Int32,
+		Actor:    pgtype.Text{String: actor, Valid: true},
+		Date:     date,
+		Since:    pgtype.Timestamptz{Time: created.OccurredAt, Valid: true},
+		RowLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, created.ID, events[0].ID)
+
+	// The audit log is append-only.
+	_, err = testDB.Exec(ctx, "UPDATE audit_event SET actor = 'someone' WHERE id = $1", created.ID)
+	require.ErrorContains(t, err, "append-only")
+	_, err = testDB.Exec(ctx, "DELETE FROM audit_event WHERE id = $1", created.ID)
+	require.ErrorContains(t, err, "append-only")
+}
@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// DefaultBulkBatchSize is the number of rows copied per batch when BulkOptions.BatchSize is not set.
+const DefaultBulkBatchSize = 10_000
+
+// BulkOptions configures BulkLoadAvailability.
+type BulkOptions struct {
+	// BatchSize is the number of rows copied, and committed, at once.
+	BatchSize int
+	// Replace updates the nights of a batch that already exist instead of copying them, making loads
+	// idempotent. Updated nights get a new version and keep their booking time while they stay booked,
+	// and unchanged nights are left as they are, though still counted as loaded.
+	// Without it, a night that already exists fails the whole batch with a unique violation.
+	Replace bool
+	// Progress, if set, is called after every committed batch.
+	Progress func(BulkProgress)
+}
+
+// BulkProgress reports the state of a running bulk load.
+type BulkProgress struct {
+	Batches int
+	Rows    int64
+	Elapsed time.Duration
+}
+
+// TxFunc calls fn with queries running in a single transaction, which is committed if fn succeeds
+// and rolled back otherwise.
+type TxFunc func(ctx context.Context, fn func(Querier) error) error
+
+// BulkLoadAvailability copies availability rows into `room_availability` with the COPY protocol.
+// Rows are streamed from the sequence and written in batches of one transaction each; a failing batch
+// is rolled back and stops the load, leaving the previous batches committed.
+func BulkLoadAvailability(ctx context.Context, execTx TxFunc, rows iter.Seq2[CopyRoomAvailabilityParams, error], options BulkOptions) (int64, error) {
+	batchSize := options.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBulkBatchSize
+	}
+
+	start := time.Now()
+	progress := BulkProgress{}
+	batch := make([]CopyRoomAvailabilityParams, 0, batchSize)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		count, err := copyAvailabilityBatch(ctx, execTx, batch, options.Replace)
+		if err != nil {
+			return fmt.Errorf("batch %d: %w", progress.Batches+1, err)
+		}
+
+		progress.Batches++
+		progress.Rows += count
+		progress.Elapsed = time.Since(start)
+		if options.Progress != nil {
+			options.Progress(progress)
+		}
+
+		batch = batch[:0]
+		return nil
+	}
+
+	for row, err := range rows {
+		if err != nil {
+			return progress.Rows, err
+		}
+
+		batch = append(batch, row)
+		if len(batch) == batchSize {
+			if err := flush(); err != nil {
+				return progress.Rows, err
+			}
+		}
+	}
+
+	err := flush()
+	return progress.Rows, err
+}
+
+// copyAvailabilityBatch writes a single batch within a transaction: with the COPY protocol, or merged
+// into the existing nights when replacing them.
+func copyAvailabilityBatch(ctx context.Context, execTx TxFunc, batch []CopyRoomAvailabilityParams, replace bool) (count int64, err error) {
+	err = execTx(ctx, func(queries Querier) error {
+		if !replace {
+			count, err = queries.CopyRoomAvailability(ctx, batch)
+			return err
+		}
+
+		arg := UpsertRoomAvailabilityNightsParams{
+			RoomIds:     make([]int32, len(batch)),
+			Dates:       make([]pgtype.Date, len(batch)),
+			IsAvailable: make([]bool, len(batch)),
+			NightRates:  make([]int32, len(batch)),
+		}
+		for i, row := range batch {
+			arg.RoomIds[i], arg.Dates[i], arg.IsAvailable[i], arg.NightRates[i] = row.RoomID, row.Date, row.IsAvailable, row.NightRate
+		}
+		if _, err := queries.UpsertRoomAvailabilityNights(ctx, arg); err != nil {
+			return err
+		}
+		count = int64(len(batch))
+		return nil
+	})
+	return count, err
+}
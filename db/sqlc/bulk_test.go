@@ -0,0 +1,145 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+	"github.com/vivek-344/airbnb-api/util"
+)
+
+// availabilityRows yields one night per day for the room, starting from the given date.
+func availabilityRows(roomID int32, start time.Time, nights int) iter.Seq2[db.CopyRoomAvailabilityParams, error] {
+	return func(yield func(db.CopyRoomAvailabilityParams, error) bool) {
+		for i := range nights {
+			row := db.CopyRoomAvailabilityParams{
+				RoomID:      roomID,
+				Date:        pgtype.Date{Time: start.AddDate(0, 0, i), Valid: true},
+				IsAvailable: util.RandomBool(),
+				NightRate:   util.RandomPrice(),
+			}
+			if !yield(row, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestBulkLoadAvailability(t *testing.T) {
+	room := createRandomRoom(1, t)
+	defer deleteRoom(room, t)
+	defer testQueries.DeleteAllAvailabilityForRoom(context.Background(), room.RoomID)
+
+	start := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	var batches int
+	options := db.BulkOptions{BatchSize: 40, Progress: func(progress db.BulkProgress) { batches = progress.Batches }}
+
+	count, err := db.BulkLoadAvailability(context.Background(), beginFunc(testDB), availabilityRows(room.RoomID, start, 100), options)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), count)
+	require.Equal(t, 3, batches)
+
+	// Loading the same nights again violates the primary key unless they are replaced.
+	_, err = db.BulkLoadAvailability(context.Background(), beginFunc(testDB), availabilityRows(room.RoomID, start, 100), options)
+	require.Error(t, err)
+
+	options.Replace = true
+	count, err = db.BulkLoadAvailability(context.Background(), beginFunc(testDB), availabilityRows(room.RoomID, start, 100), options)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), count)
+
+	end := pgtype.Date{Time: start.AddDate(0, 0, 99), Valid: true}
+	nights, err := testQueries.GetRoomAvailabilityByDate(context.Background(), db.GetRoomAvailabilityByDateParams{RoomID: room.RoomID, Date: end})
+	require.NoError(t, err)
+	require.Equal(t, room.RoomID, nights.RoomID)
+}
+
+func TestBulkLoadAvailabilityReplace(t *testing.T) {
+	room := createRandomRoom(1, t)
+	defer deleteRoom(room, t)
+	defer testQueries.DeleteAllAvailabilityForRoom(context.Background(), room.RoomID)
+
+	date := pgtype.Date{Time: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	night, err := testQueries.CreateRoomAvailability(context.Background(), db.CreateRoomAvailabilityParams{
+		RoomID: room.RoomID, Date: date, IsAvailable: true, NightRate: 5000,
+	})
+	require.NoError(t, err)
+	night, err = testQueries.UpdateRoomAvailability(context.Background(), db.UpdateRoomAvailabilityParams{
+		RoomID: room.RoomID, Date: date, NightRate: 5000, Version: night.Version,
+	})
+	require.NoError(t, err)
+	require.Greater(t, night.Version, int32(1))
+	require.True(t, night.BookedAt.Valid)
+
+	// Reloading the night keeps its version increasing, so that stale ETags cannot overwrite it,
+	// and keeps its booking time while it stays booked.
+	rows := func(yield func(db.CopyRoomAvailabilityParams, error) bool) {
+		yield(db.CopyRoomAvailabilityParams{RoomID: room.RoomID, Date: date, NightRate: 6000}, nil)
+	}
+	count, err := db.BulkLoadAvailability(context.Background(), beginFunc(testDB), rows, db.BulkOptions{Replace: true})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	reloaded, err := testQueries.GetRoomAvailabilityByDate(context.Background(), db.GetRoomAvailabilityByDateParams{RoomID: room.RoomID, Date: date})
+	require.NoError(t, err)
+	require.Equal(t, night.Version+1, reloaded.Version)
+	require.Equal(t, int32(6000), reloaded.NightRate)
+	require.Equal(t, night.BookedAt, reloaded.BookedAt)
+
+	// Reloading the same values leaves the night as it is, though it is counted as loaded.
+	count, err = db.BulkLoadAvailability(context.Background(), beginFunc(testDB), rows, db.BulkOptions{Replace: true})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+	unchanged, err := testQueries.GetRoomAvailabilityByDate(context.Background(), db.GetRoomAvailabilityByDateParams{RoomID: room.RoomID, Date: date})
+	require.NoError(t, err)
+	require.Equal(t, reloaded, unchanged)
+}
+
+func TestBulkLoadAvailabilitySourceError(t *testing.T) {
+	sourceErr := errors.New("bad line")
+	rows := func(yield func(db.CopyRoomAvailabilityParams, error) bool) {
+		yield(db.CopyRoomAvailabilityParams{}, sourceErr)
+	}
+
+	count, err := db.BulkLoadAvailability(context.Background(), beginFunc(testDB), rows, db.BulkOptions{})
+	require.ErrorIs(t, err, sourceErr)
+	require.Zero(t, count)
+}
+
+// benchmarkNights is the number of nights written per iteration of the load benchmarks.
+const benchmarkNights = 365
+
+func BenchmarkCreateRoomAvailability(b *testing.B) {
+	room, err := testQueries.UpsertRoom(context.Background(), db.UpsertRoomParams{RoomID: 1, MaxGuests: 2, DefaultRate: 5000})
+	require.NoError(b, err)
+	defer testQueries.DeleteRoom(context.Background(), room.RoomID)
+
+	start := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	for range b.N {
+		for row := range availabilityRows(room.RoomID, start, benchmarkNights) {
+			_, err := testQueries.CreateRoomAvailability(context.Background(), db.CreateRoomAvailabilityParams(row))
+			require.NoError(b, err)
+		}
+		_, err = testQueries.DeleteAllAvailabilityForRoom(context.Background(), room.RoomID)
+		require.NoError(b, err)
+	}
+}
+
+func BenchmarkBulkLoadAvailability(b *testing.B) {
+	room, err := testQueries.UpsertRoom(context.Background(), db.UpsertRoomParams{RoomID: 1, MaxGuests: 2, DefaultRate: 5000})
+	require.NoError(b, err)
+	defer testQueries.DeleteRoom(context.Background(), room.RoomID)
+
+	start := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	for range b.N {
+		_, err := db.BulkLoadAvailability(context.Background(), beginFunc(testDB), availabilityRows(room.RoomID, start, benchmarkNights), db.BulkOptions{})
+		require.NoError(b, err)
+		_, err = testQueries.DeleteAllAvailabilityForRoom(context.Background(), room.RoomID)
+		require.NoError(b, err)
+	}
+}
@@ -5,21 +5,114 @@
 package db
 
 import (
+	"time"
+
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type ApiKey struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	KeyHash   []byte    `json:"key_hash"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AuditEvent struct {
+	ID         int64       `json:"id"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Actor      string      `json:"actor"`
+	Action     string      `json:"action"`
+	RoomID     pgtype.Int4 `json:"room_id"`
+	Date       pgtype.Date `json:"date"`
+	Before     []byte      `json:"before"`
+	After      []byte      `json:"after"`
+	RequestID  pgtype.Text `json:"request_id"`
+}
+
+type Outbox struct {
+	ID          int64              `json:"id"`
+	EventID     uuid.UUID          `json:"event_id"`
+	EventType   string             `json:"event_type"`
+	RoomID      int32              `json:"room_id"`
+	Payload     []byte             `json:"payload"`
+	CreatedAt   time.Time          `json:"created_at"`
+	PublishedAt pgtype.Timestamptz `json:"published_at"`
+	Position    pgtype.Int8        `json:"position"`
+}
+
 type Room struct {
-	RoomID        int32 `json:"room_id"`
-	MaxGuests     int32 `json:"max_guests"`
-	Balcony       bool  `json:"balcony"`
-	Fridge        bool  `json:"fridge"`
-	IndoorPool    bool  `json:"indoor_pool"`
-	GamingConsole bool  `json:"gaming_console"`
+	RoomID        int32     `json:"room_id"`
+	MaxGuests     int32     `json:"max_guests"`
+	Balcony       bool      `json:"balcony"`
+	Fridge        bool      `json:"fridge"`
+	IndoorPool    bool      `json:"indoor_pool"`
+	GamingConsole bool      `json:"gaming_console"`
+	DefaultRate   int32     `json:"default_rate"`
+	Version       int32     `json:"version"`
+	UpdatedAt     time.Time `json:"updated_at"`
 }
 
 type RoomAvailability struct {
-	RoomID      int32       `json:"room_id"`
-	Date        pgtype.Date `json:"date"`
-	IsAvailable bool        `json:"is_available"`
-	NightRate   int32       `json:"night_rate"`
+	RoomID      int32              `json:"room_id"`
+	Date        pgtype.Date        `json:"date"`
+	IsAvailable bool               `json:"is_available"`
+	NightRate   int32              `json:"night_rate"`
+	Version     int32              `json:"version"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+	BookedAt    pgtype.Timestamptz `json:"booked_at"`
+}
+
+type RoomAvailabilityHistory struct {
+	RoomID      int32              `json:"room_id"`
+	Date        pgtype.Date        `json:"date"`
+	IsAvailable bool               `json:"is_available"`
+	NightRate   int32              `json:"night_rate"`
+	ArchivedAt  time.Time          `json:"archived_at"`
+	BookedAt    pgtype.Timestamptz `json:"booked_at"`
+}
+
+type RoomMonthStat struct {
+	RoomID       int32       `json:"room_id"`
+	Month        pgtype.Date `json:"month"`
+	Nights       int32       `json:"nights"`
+	BookedNights int32       `json:"booked_nights"`
+	Revenue      int64       `json:"revenue"`
+	RateSum      int64       `json:"rate_sum"`
+	LowestRate   int32       `json:"lowest_rate"`
+	HighestRate  int32       `json:"highest_rate"`
+	RefreshedAt  time.Time   `json:"refreshed_at"`
+}
+
+type RoomNight struct {
+	RoomID      int32              `json:"room_id"`
+	Date        pgtype.Date        `json:"date"`
+	IsAvailable bool               `json:"is_available"`
+	NightRate   int32              `json:"night_rate"`
+	BookedAt    pgtype.Timestamptz `json:"booked_at"`
+}
+
+type WebhookDelivery struct {
+	ID             int64              `json:"id"`
+	SubscriptionID int64              `json:"subscription_id"`
+	EventID        uuid.UUID          `json:"event_id"`
+	EventType      string             `json:"event_type"`
+	RoomID         int32              `json:"room_id"`
+	Payload        []byte             `json:"payload"`
+	Status         string             `json:"status"`
+	Attempts       int32              `json:"attempts"`
+	NextAttemptAt  time.Time          `json:"next_attempt_at"`
+	LastAttemptAt  pgtype.Timestamptz `json:"last_attempt_at"`
+	ResponseStatus pgtype.Int4        `json:"response_status"`
+	LastError      pgtype.Text        `json:"last_error"`
+	ReplayOf       pgtype.Int8        `json:"replay_of"`
+	CreatedAt      time.Time          `json:"created_at"`
+}
+
+type WebhookSubscription struct {
+	ID         int64     `json:"id"`
+	Url        string    `json:"url"`
+	Secret     string    `json:"secret"`
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
 }
@@ -0,0 +1,74 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+	"github.com/vivek-344/airbnb-api/util"
+)
+
+func TestOutbox(t *testing.T) {
+	ctx := context.Background()
+	roomID := util.RandomInt(1_000_000, 2_000_000)
+
+	// Other events may be pending, so the test only looks at its own.
+	first, err := testQueries.CreateOutboxEvent(ctx, db.CreateOutboxEventParams{
+		RoomID:    roomID,
+		EventID:   uuid.New(),
+		EventType: "room.created",
+		Payload:   []byte(`{"type": "room.created"}`),
+	})
+	require.NoError(t, err)
+	require.False(t, first.PublishedAt.Valid)
+	require.WithinDuration(t, time.Now(), first.CreatedAt, time.Minute)
+
+	second, err := testQueries.CreateOutboxEvent(ctx, db.CreateOutboxEventParams{
+		RoomID:    roomID,
+		EventID:   uuid.New(),
+		EventType: "room.updated",
+		Payload:   []byte(`{"type": "room.updated"}`),
+	})
+	require.NoError(t, err)
+	require.Greater(t, second.ID, first.ID)
+
+	// Event IDs are unique.
+	_, err = testQueries.CreateOutboxEvent(ctx, db.CreateOutboxEventParams{
+		RoomID:    roomID,
+		EventID:   first.EventID,
+		EventType: "room.created",
+		Payload:   []byte(`{}`),
+	})
+	require.Error(t, err)
+
+	events, err := testQueries.ListPendingOutboxEvents(ctx, db.ListPendingOutboxEventsParams{AfterID: first.ID - 1, RowLimit: 1})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, first.ID, events[0].ID)
+
+	// Marking an event is idempotent.
+	marked, err := testQueries.MarkOutboxEventPublished(ctx, first.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), marked)
+	marked, err = testQueries.MarkOutboxEventPublished(ctx, first.ID)
+	require.NoError(t, err)
+	require.Zero(t, marked)
+
+	events, err = testQueries.ListPendingOutboxEvents(ctx, db.ListPendingOutboxEventsParams{AfterID: first.ID - 1, RowLimit: 1})
+	require.NoError(t, err)
+	require.Equal(t, second.ID, events[0].ID)
+
+	_, err = testQueries.MarkOutboxEventPublished(ctx, second.ID)
+	require.NoError(t, err)
+	_, err = testQueries.DeletePublishedOutboxEvents(ctx, pgtype.Interval{Valid: true})
+	require.NoError(t, err)
+	events, err = testQueries.ListPendingOutboxEvents(ctx, db.ListPendingOutboxEventsParams{AfterID: first.ID - 1, RowLimit: 10})
+	require.NoError(t, err)
+	for _, event := range events {
+		require.NotEqual(t, roomID, event.RoomID)
+	}
+}
@@ -0,0 +1,41 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+func TestCreateRoomAvailabilityPartition(t *testing.T) {
+	ctx := context.Background()
+	room := createRandomRoom(1, t)
+
+	// A night beyond every partition is kept in the default partition, then moved to its own.
+	date := pgtype.Date{Valid: true, Time: time.Date(2090, 3, 15, 0, 0, 0, 0, time.UTC)}
+	night := createRandomRoomAvailability(date, room, t)
+
+	_, err := testQueries.CreateRoomAvailabilityPartition(ctx, date)
+	require.NoError(t, err)
+	created, err := testQueries.CreateRoomAvailabilityPartition(ctx, date)
+	require.NoError(t, err)
+	require.False(t, created)
+
+	moved, err := testQueries.GetRoomAvailabilityByDate(ctx, db.GetRoomAvailabilityByDateParams{RoomID: room.RoomID, Date: date})
+	require.NoError(t, err)
+	require.Equal(t, night, moved)
+
+	partitions, err := testQueries.ListRoomAvailabilityPartitions(ctx)
+	require.NoError(t, err)
+	require.Contains(t, partitions, db.ListRoomAvailabilityPartitionsRow{
+		Name:  "room_availability_2090_03",
+		Bound: "FOR VALUES FROM ('2090-03-01') TO ('2090-04-01')",
+	})
+	require.Equal(t, "room_availability_default", partitions[len(partitions)-1].Name)
+
+	testQueries.DeleteAllAvailabilityForRoom(ctx, room.RoomID)
+	deleteRoom(room, t)
+}
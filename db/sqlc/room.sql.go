@@ -7,6 +7,8 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createRoom = `-- name: CreateRoom :one
@@ -16,11 +18,12 @@ INSERT INTO room (
   balcony,
   fridge,
   indoor_pool,
-  gaming_console
+  gaming_console,
+  default_rate
 ) VALUES (
-  $1, $2, $3, $4, $5, $6
+  $1, $2, $3, $4, $5, $6, $7
 )
-RETURNING room_id, max_guests, balcony, fridge, indoor_pool, gaming_console
+RETURNING room_id, max_guests, balcony, fridge, indoor_pool, gaming_console, default_rate, version, updated_at
 `
 
 type CreateRoomParams struct {
@@ -30,6 +33,7 @@ type CreateRoomParams struct {
 	Fridge        bool  `json:"fridge"`
 	IndoorPool    bool  `json:"indoor_pool"`
 	GamingConsole bool  `json:"gaming_console"`
+	DefaultRate   int32 `json:"default_rate"`
 }
 
 func (q *Queries) CreateRoom(ctx context.Context, arg CreateRoomParams) (Room, error) {
@@ -40,6 +44,7 @@ func (q *Queries) CreateRoom(ctx context.Context, arg CreateRoomParams) (Room, e
 		arg.Fridge,
 		arg.IndoorPool,
 		arg.GamingConsole,
+		arg.DefaultRate,
 	)
 	var i Room
 	err := row.Scan(
@@ -49,6 +54,9 @@ func (q *Queries) CreateRoom(ctx context.Context, arg CreateRoomParams) (Room, e
 		&i.Fridge,
 		&i.IndoorPool,
 		&i.GamingConsole,
+		&i.DefaultRate,
+		&i.Version,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
@@ -63,7 +71,7 @@ func (q *Queries) DeleteRoom(ctx context.Context, roomID int32) error {
 }
 
 const getRoom = `-- name: GetRoom :one
-SELECT room_id, max_guests, balcony, fridge, indoor_pool, gaming_console FROM room
+SELECT room_id, max_guests, balcony, fridge, indoor_pool, gaming_console, default_rate, version, updated_at FROM room
 WHERE room_id = $1 LIMIT 1
 `
 
@@ -77,6 +85,9 @@ func (q *Queries) GetRoom(ctx context.Context, roomID int32) (Room, error) {
 		&i.Fridge,
 		&i.IndoorPool,
 		&i.GamingConsole,
+		&i.DefaultRate,
+		&i.Version,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
@@ -118,7 +129,7 @@ func (q *Queries) ListAllRoomIDs(ctx context.Context) ([]int32, error) {
 }
 
 const listRooms = `-- name: ListRooms :many
-SELECT room_id, max_guests, balcony, fridge, indoor_pool, gaming_console FROM room
+SELECT room_id, max_guests, balcony, fridge, indoor_pool, gaming_console, default_rate, version, updated_at FROM room
 ORDER BY room_id
 LIMIT $1
 OFFSET $2
@@ -145,6 +156,119 @@ func (q *Queries) ListRooms(ctx context.Context, arg ListRoomsParams) ([]Room, e
 			&i.Fridge,
 			&i.IndoorPool,
 			&i.GamingConsole,
+			&i.DefaultRate,
+			&i.Version,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRoomsByIDs = `-- name: ListRoomsByIDs :many
+SELECT room_id, max_guests, balcony, fridge, indoor_pool, gaming_console, default_rate, version, updated_at FROM room
+WHERE room_id = ANY($1::int[])
+ORDER BY room_id
+`
+
+func (q *Queries) ListRoomsByIDs(ctx context.Context, roomIds []int32) ([]Room, error) {
+	rows, err := q.db.Query(ctx, listRoomsByIDs, roomIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Room{}
+	for rows.Next() {
+		var i Room
+		if err := rows.Scan(
+			&i.RoomID,
+			&i.MaxGuests,
+			&i.Balcony,
+			&i.Fridge,
+			&i.IndoorPool,
+			&i.GamingConsole,
+			&i.DefaultRate,
+			&i.Version,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchAvailableRooms = `-- name: SearchAvailableRooms :many
+SELECT room.room_id, room.max_guests, room.balcony, room.fridge, room.indoor_pool, room.gaming_console, room.default_rate, room.version, room.updated_at, SUM(room_availability.night_rate)::bigint AS total
+FROM room
+JOIN room_availability ON room_availability.room_id = room.room_id
+WHERE room.max_guests >= $1
+  AND (room.balcony OR NOT $2::boolean)
+  AND (room.fridge OR NOT $3::boolean)
+  AND (room.indoor_pool OR NOT $4::boolean)
+  AND (room.gaming_console OR NOT $5::boolean)
+  AND room_availability.date >= $6 AND room_availability.date < $7
+  AND room_availability.is_available
+GROUP BY room.room_id
+HAVING COUNT(*) = $7::date - $6::date
+ORDER BY total, room.room_id
+LIMIT $8
+`
+
+type SearchAvailableRoomsParams struct {
+	Guests        int32       `json:"guests"`
+	Balcony       bool        `json:"balcony"`
+	Fridge        bool        `json:"fridge"`
+	IndoorPool    bool        `json:"indoor_pool"`
+	GamingConsole bool        `json:"gaming_console"`
+	CheckIn       pgtype.Date `json:"check_in"`
+	CheckOut      pgtype.Date `json:"check_out"`
+	RowLimit      int32       `json:"row_limit"`
+}
+
+type SearchAvailableRoomsRow struct {
+	Room  Room  `json:"room"`
+	Total int64 `json:"total"`
+}
+
+// Rooms hosting the guests, with the requested amenities and every night of the stay available, cheapest stay first.
+func (q *Queries) SearchAvailableRooms(ctx context.Context, arg SearchAvailableRoomsParams) ([]SearchAvailableRoomsRow, error) {
+	rows, err := q.db.Query(ctx, searchAvailableRooms,
+		arg.Guests,
+		arg.Balcony,
+		arg.Fridge,
+		arg.IndoorPool,
+		arg.GamingConsole,
+		arg.CheckIn,
+		arg.CheckOut,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchAvailableRoomsRow{}
+	for rows.Next() {
+		var i SearchAvailableRoomsRow
+		if err := rows.Scan(
+			&i.Room.RoomID,
+			&i.Room.MaxGuests,
+			&i.Room.Balcony,
+			&i.Room.Fridge,
+			&i.Room.IndoorPool,
+			&i.Room.GamingConsole,
+			&i.Room.DefaultRate,
+			&i.Room.Version,
+			&i.Room.UpdatedAt,
+			&i.Total,
 		); err != nil {
 			return nil, err
 		}
@@ -158,9 +282,11 @@ func (q *Queries) ListRooms(ctx context.Context, arg ListRoomsParams) ([]Room, e
 
 const updateMaxGuests = `-- name: UpdateMaxGuests :one
 UPDATE room
-SET max_guests = $2
+SET max_guests = $2,
+    version = version + 1,
+    updated_at = now()
 WHERE room_id = $1
-RETURNING room_id, max_guests, balcony, fridge, indoor_pool, gaming_console
+RETURNING room_id, max_guests, balcony, fridge, indoor_pool, gaming_console, default_rate, version, updated_at
 `
 
 type UpdateMaxGuestsParams struct {
@@ -178,15 +304,71 @@ func (q *Queries) UpdateMaxGuests(ctx context.Context, arg UpdateMaxGuestsParams
 		&i.Fridge,
 		&i.IndoorPool,
 		&i.GamingConsole,
+		&i.DefaultRate,
+		&i.Version,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateRoom = `-- name: UpdateRoom :one
+UPDATE room
+SET max_guests = COALESCE($1, max_guests),
+    balcony = COALESCE($2, balcony),
+    fridge = COALESCE($3, fridge),
+    indoor_pool = COALESCE($4, indoor_pool),
+    gaming_console = COALESCE($5, gaming_console),
+    default_rate = COALESCE($6, default_rate),
+    version = version + 1,
+    updated_at = now()
+WHERE room_id = $7 AND version = $8
+RETURNING room_id, max_guests, balcony, fridge, indoor_pool, gaming_console, default_rate, version, updated_at
+`
+
+type UpdateRoomParams struct {
+	MaxGuests     pgtype.Int4 `json:"max_guests"`
+	Balcony       pgtype.Bool `json:"balcony"`
+	Fridge        pgtype.Bool `json:"fridge"`
+	IndoorPool    pgtype.Bool `json:"indoor_pool"`
+	GamingConsole pgtype.Bool `json:"gaming_console"`
+	DefaultRate   pgtype.Int4 `json:"default_rate"`
+	RoomID        int32       `json:"room_id"`
+	Version       int32       `json:"version"`
+}
+
+func (q *Queries) UpdateRoom(ctx context.Context, arg UpdateRoomParams) (Room, error) {
+	row := q.db.QueryRow(ctx, updateRoom,
+		arg.MaxGuests,
+		arg.Balcony,
+		arg.Fridge,
+		arg.IndoorPool,
+		arg.GamingConsole,
+		arg.DefaultRate,
+		arg.RoomID,
+		arg.Version,
+	)
+	var i Room
+	err := row.Scan(
+		&i.RoomID,
+		&i.MaxGuests,
+		&i.Balcony,
+		&i.Fridge,
+		&i.IndoorPool,
+		&i.GamingConsole,
+		&i.DefaultRate,
+		&i.Version,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
 const updateRoomConsole = `-- name: UpdateRoomConsole :one
 UPDATE room
-SET gaming_console = $2
+SET gaming_console = $2,
+    version = version + 1,
+    updated_at = now()
 WHERE room_id = $1
-RETURNING room_id, max_guests, balcony, fridge, indoor_pool, gaming_console
+RETURNING room_id, max_guests, balcony, fridge, indoor_pool, gaming_console, default_rate, version, updated_at
 `
 
 type UpdateRoomConsoleParams struct {
@@ -204,15 +386,20 @@ func (q *Queries) UpdateRoomConsole(ctx context.Context, arg UpdateRoomConsolePa
 		&i.Fridge,
 		&i.IndoorPool,
 		&i.GamingConsole,
+		&i.DefaultRate,
+		&i.Version,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
 const updateRoomFridge = `-- name: UpdateRoomFridge :one
 UPDATE room
-SET fridge = $2
+SET fridge = $2,
+    version = version + 1,
+    updated_at = now()
 WHERE room_id = $1
-RETURNING room_id, max_guests, balcony, fridge, indoor_pool, gaming_console
+RETURNING room_id, max_guests, balcony, fridge, indoor_pool, gaming_console, default_rate, version, updated_at
 `
 
 type UpdateRoomFridgeParams struct {
@@ -230,6 +417,73 @@ func (q *Queries) UpdateRoomFridge(ctx context.Context, arg UpdateRoomFridgePara
 		&i.Fridge,
 		&i.IndoorPool,
 		&i.GamingConsole,
+		&i.DefaultRate,
+		&i.Version,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertRoom = `-- name: UpsertRoom :one
+INSERT INTO room (
+  room_id,
+  max_guests,
+  balcony,
+  fridge,
+  indoor_pool,
+  gaming_console,
+  default_rate
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+ON CONFLICT (room_id) DO UPDATE
+SET max_guests = EXCLUDED.max_guests,
+    balcony = EXCLUDED.balcony,
+    fridge = EXCLUDED.fridge,
+    indoor_pool = EXCLUDED.indoor_pool,
+    gaming_console = EXCLUDED.gaming_console,
+    default_rate = EXCLUDED.default_rate,
+    version = CASE WHEN (room.max_guests, room.balcony, room.fridge, room.indoor_pool, room.gaming_console, room.default_rate)
+      IS DISTINCT FROM (EXCLUDED.max_guests, EXCLUDED.balcony, EXCLUDED.fridge, EXCLUDED.indoor_pool, EXCLUDED.gaming_console, EXCLUDED.default_rate)
+      THEN room.version + 1 ELSE room.version END,
+    updated_at = CASE WHEN (room.max_guests, room.balcony, room.fridge, room.indoor_pool, room.gaming_console, room.default_rate)
+      IS DISTINCT FROM (EXCLUDED.max_guests, EXCLUDED.balcony, EXCLUDED.fridge, EXCLUDED.indoor_pool, EXCLUDED.gaming_console, EXCLUDED.default_rate)
+      THEN now() ELSE room.updated_at END
+RETURNING room_id, max_guests, balcony, fridge, indoor_pool, gaming_console, default_rate, version, updated_at
+`
+
+type UpsertRoomParams struct {
+	RoomID        int32 `json:"room_id"`
+	MaxGuests     int32 `json:"max_guests"`
+	Balcony       bool  `json:"balcony"`
+	Fridge        bool  `json:"fridge"`
+	IndoorPool    bool  `json:"indoor_pool"`
+	GamingConsole bool  `json:"gaming_console"`
+	DefaultRate   int32 `json:"default_rate"`
+}
+
+// An existing room keeps its version and update time when none of its fields change, so reruns are no-ops.
+func (q *Queries) UpsertRoom(ctx context.Context, arg UpsertRoomParams) (Room, error) {
+	row := q.db.QueryRow(ctx, upsertRoom,
+		arg.RoomID,
+		arg.MaxGuests,
+		arg.Balcony,
+		arg.Fridge,
+		arg.IndoorPool,
+		arg.GamingConsole,
+		arg.DefaultRate,
+	)
+	var i Room
+	err := row.Scan(
+		&i.RoomID,
+		&i.MaxGuests,
+		&i.Balcony,
+		&i.Fridge,
+		&i.IndoorPool,
+		&i.GamingConsole,
+		&i.DefaultRate,
+		&i.Version,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
@@ -11,6 +11,63 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type CopyRoomAvailabilityParams struct {
+	RoomID      int32       `json:"room_id"`
+	Date        pgtype.Date `json:"date"`
+	IsAvailable bool        `json:"is_available"`
+	NightRate   int32       `json:"night_rate"`
+}
+
+const archiveOldRoomAvailabilityData = `-- name: ArchiveOldRoomAvailabilityData :exec
+WITH archived AS (
+  DELETE FROM room_availability
+  WHERE date < CURRENT_DATE
+  RETURNING room_id, date, is_available, night_rate, booked_at
+)
+INSERT INTO room_availability_history (room_id, date, is_available, night_rate, booked_at)
+SELECT room_id, date, is_available, night_rate, booked_at FROM archived
+ON CONFLICT (room_id, date) DO UPDATE
+SET is_available = EXCLUDED.is_available, night_rate = EXCLUDED.night_rate, booked_at = EXCLUDED.booked_at, archived_at = now()
+`
+
+func (q *Queries) ArchiveOldRoomAvailabilityData(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, archiveOldRoomAvailabilityData)
+	return err
+}
+
+const archiveRoomPastAvailability = `-- name: ArchiveRoomPastAvailability :many
+WITH archived AS (
+  DELETE FROM room_availability
+  WHERE room_id = $1 AND date < CURRENT_DATE
+  RETURNING room_id, date, is_available, night_rate, booked_at
+)
+INSERT INTO room_availability_history (room_id, date, is_available, night_rate, booked_at)
+SELECT room_id, date, is_available, night_rate, booked_at FROM archived
+ON CONFLICT (room_id, date) DO UPDATE
+SET is_available = EXCLUDED.is_available, night_rate = EXCLUDED.night_rate, booked_at = EXCLUDED.booked_at, archived_at = now()
+RETURNING date
+`
+
+func (q *Queries) ArchiveRoomPastAvailability(ctx context.Context, roomID int32) ([]pgtype.Date, error) {
+	rows, err := q.db.Query(ctx, archiveRoomPastAvailability, roomID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []pgtype.Date{}
+	for rows.Next() {
+		var date pgtype.Date
+		if err := rows.Scan(&date); err != nil {
+			return nil, err
+		}
+		items = append(items, date)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createRoomAvailability = `-- name: CreateRoomAvailability :one
 INSERT INTO room_availability (
   room_id,
@@ -20,7 +77,7 @@ INSERT INTO room_availability (
 ) VALUES (
   $1, $2, $3, $4
 )
-RETURNING room_id, date, is_available, night_rate
+RETURNING room_id, date, is_available, night_rate, version, updated_at, booked_at
 `
 
 type CreateRoomAvailabilityParams struct {
@@ -43,37 +100,122 @@ func (q *Queries) CreateRoomAvailability(ctx context.Context, arg CreateRoomAvai
 		&i.Date,
 		&i.IsAvailable,
 		&i.NightRate,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.BookedAt,
 	)
 	return i, err
 }
 
-const deleteAllAvailabilityForRoom = `-- name: DeleteAllAvailabilityForRoom :exec
+const deleteAllAvailabilityForRoom = `-- name: DeleteAllAvailabilityForRoom :many
 DELETE FROM room_availability WHERE room_id = $1
+RETURNING date
 `
 
-func (q *Queries) DeleteAllAvailabilityForRoom(ctx context.Context, roomID int32) error {
-	_, err := q.db.Exec(ctx, deleteAllAvailabilityForRoom, roomID)
-	return err
+func (q *Queries) DeleteAllAvailabilityForRoom(ctx context.Context, roomID int32) ([]pgtype.Date, error) {
+	rows, err := q.db.Query(ctx, deleteAllAvailabilityForRoom, roomID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []pgtype.Date{}
+	for rows.Next() {
+		var date pgtype.Date
+		if err := rows.Scan(&date); err != nil {
+			return nil, err
+		}
+		items = append(items, date)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const deleteOldRoomAvailabilityData = `-- name: DeleteOldRoomAvailabilityData :exec
-DELETE FROM room_availability WHERE date < CURRENT_DATE
+const deleteRoomAvailabilityNights = `-- name: DeleteRoomAvailabilityNights :many
+DELETE FROM room_availability AS ra
+USING unnest($1::int[], $2::date[]) AS night(room_id, date)
+WHERE ra.room_id = night.room_id AND ra.date = night.date
+RETURNING ra.room_id, ra.date
 `
 
-func (q *Queries) DeleteOldRoomAvailabilityData(ctx context.Context) error {
-	_, err := q.db.Exec(ctx, deleteOldRoomAvailabilityData)
-	return err
+type DeleteRoomAvailabilityNightsParams struct {
+	RoomIds []int32       `json:"room_ids"`
+	Dates   []pgtype.Date `json:"dates"`
+}
+
+type DeleteRoomAvailabilityNightsRow struct {
+	RoomID int32       `json:"room_id"`
+	Date   pgtype.Date `json:"date"`
+}
+
+func (q *Queries) DeleteRoomAvailabilityNights(ctx context.Context, arg DeleteRoomAvailabilityNightsParams) ([]DeleteRoomAvailabilityNightsRow, error) {
+	rows, err := q.db.Query(ctx, deleteRoomAvailabilityNights, arg.RoomIds, arg.Dates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []DeleteRoomAvailabilityNightsRow{}
+	for rows.Next() {
+		var i DeleteRoomAvailabilityNightsRow
+		if err := rows.Scan(&i.RoomID, &i.Date); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const extendRoomAvailability = `-- name: ExtendRoomAvailability :many
+INSERT INTO room_availability (
+  room_id,
+  date,
+  is_available,
+  night_rate
+)
+SELECT room.room_id, CURRENT_DATE + night.n, TRUE, room.default_rate
+FROM room, generate_series(0, $1::int - 1) AS night(n)
+WHERE room.room_id = $2
+ON CONFLICT (room_id, date) DO NOTHING
+RETURNING date
+`
+
+type ExtendRoomAvailabilityParams struct {
+	HorizonDays int32 `json:"horizon_days"`
+	RoomID      int32 `json:"room_id"`
+}
+
+func (q *Queries) ExtendRoomAvailability(ctx context.Context, arg ExtendRoomAvailabilityParams) ([]pgtype.Date, error) {
+	rows, err := q.db.Query(ctx, extendRoomAvailability, arg.HorizonDays, arg.RoomID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []pgtype.Date{}
+	for rows.Next() {
+		var date pgtype.Date
+		if err := rows.Scan(&date); err != nil {
+			return nil, err
+		}
+		items = append(items, date)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const getAvailabilityPercentage = `-- name: GetAvailabilityPercentage :many
 SELECT
- EXTRACT(YEAR FROM date) AS year,
- EXTRACT(MONTH FROM date) AS month,
- CAST(COUNT(CASE WHEN is_available THEN 1 END) * 100.0 / COUNT(*) AS DECIMAL(10,2)) AS availability_percentage
-FROM room_availability
+ EXTRACT(YEAR FROM month) AS year,
+ EXTRACT(MONTH FROM month) AS month,
+ CAST((nights - booked_nights) * 100.0 / nights AS DECIMAL(10,2)) AS availability_percentage
+FROM room_month_stats
 WHERE room_id = $1
-GROUP BY year, month
-ORDER BY year, month
+ORDER BY room_month_stats.month
 `
 
 type GetAvailabilityPercentageRow struct {
@@ -117,12 +259,24 @@ func (q *Queries) GetAverageRate(ctx context.Context, roomID int32) (float64, er
 	return avg, err
 }
 
+const getCurrentDate = `-- name: GetCurrentDate :one
+SELECT CURRENT_DATE::date AS today
+`
+
+func (q *Queries) GetCurrentDate(ctx context.Context) (pgtype.Date, error) {
+	row := q.db.QueryRow(ctx, getCurrentDate)
+	var today pgtype.Date
+	err := row.Scan(&today)
+	return today, err
+}
+
 const getDateCount = `-- name: GetDateCount :one
-SELECT COUNT(DISTINCT date) FROM room_availability
+SELECT COUNT(date) FROM room_availability
+WHERE room_id = $1
 `
 
-func (q *Queries) GetDateCount(ctx context.Context) (int64, error) {
-	row := q.db.QueryRow(ctx, getDateCount)
+func (q *Queries) GetDateCount(ctx context.Context, roomID int32) (int64, error) {
+	row := q.db.QueryRow(ctx, getDateCount, roomID)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -131,12 +285,13 @@ func (q *Queries) GetDateCount(ctx context.Context) (int64, error) {
 const getMaxDate = `-- name: GetMaxDate :one
 SELECT date
 FROM room_availability
-ORDER BY night_rate DESC
+WHERE room_id = $1
+ORDER BY date DESC
 LIMIT 1
 `
 
-func (q *Queries) GetMaxDate(ctx context.Context) (pgtype.Date, error) {
-	row := q.db.QueryRow(ctx, getMaxDate)
+func (q *Queries) GetMaxDate(ctx context.Context, roomID int32) (pgtype.Date, error) {
+	row := q.db.QueryRow(ctx, getMaxDate, roomID)
 	var date pgtype.Date
 	err := row.Scan(&date)
 	return date, err
@@ -177,7 +332,7 @@ func (q *Queries) GetMinimumRate(ctx context.Context, roomID int32) (int32, erro
 }
 
 const getRoomAvailabilityByDate = `-- name: GetRoomAvailabilityByDate :one
-SELECT room_id, date, is_available, night_rate FROM room_availability
+SELECT room_id, date, is_available, night_rate, version, updated_at, booked_at FROM room_availability
 WHERE room_id = $1 AND date = $2 
 LIMIT 1
 `
@@ -195,6 +350,9 @@ func (q *Queries) GetRoomAvailabilityByDate(ctx context.Context, arg GetRoomAvai
 		&i.Date,
 		&i.IsAvailable,
 		&i.NightRate,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.BookedAt,
 	)
 	return i, err
 }
@@ -225,10 +383,52 @@ func (q *Queries) ListAvailableDates(ctx context.Context, roomID int32) ([]pgtyp
 	return items, nil
 }
 
+const listNightsForRooms = `-- name: ListNightsForRooms :many
+SELECT room_id, date, is_available, night_rate, version, updated_at, booked_at FROM room_availability
+WHERE room_id = ANY($1::int[]) AND date >= $2 AND date < $3
+ORDER BY room_id, date
+`
+
+type ListNightsForRoomsParams struct {
+	RoomIds   []int32     `json:"room_ids"`
+	StartDate pgtype.Date `json:"start_date"`
+	EndDate   pgtype.Date `json:"end_date"`
+}
+
+func (q *Queries) ListNightsForRooms(ctx context.Context, arg ListNightsForRoomsParams) ([]RoomAvailability, error) {
+	rows, err := q.db.Query(ctx, listNightsForRooms, arg.RoomIds, arg.StartDate, arg.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []RoomAvailability{}
+	for rows.Next() {
+		var i RoomAvailability
+		if err := rows.Scan(
+			&i.RoomID,
+			&i.Date,
+			&i.IsAvailable,
+			&i.NightRate,
+			&i.Version,
+			&i.UpdatedAt,
+			&i.BookedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listRoomAvailability = `-- name: ListRoomAvailability :many
 SELECT date, is_available, night_rate FROM room_availability
 WHERE room_id = $1
-LIMIT 30
+  AND date >= CURRENT_DATE
+  AND date < CURRENT_DATE + INTERVAL '30 days'
+ORDER BY date
 `
 
 type ListRoomAvailabilityRow struct {
@@ -257,12 +457,152 @@ func (q *Queries) ListRoomAvailability(ctx context.Context, roomID int32) ([]Lis
 	return items, nil
 }
 
+const listRoomNights = `-- name: ListRoomNights :many
+SELECT room_id, date, is_available, night_rate, version, updated_at, booked_at FROM room_availability
+WHERE room_id = $1 AND date >= $2 AND date < $3
+ORDER BY date
+`
+
+type ListRoomNightsParams struct {
+	RoomID    int32       `json:"room_id"`
+	StartDate pgtype.Date `json:"start_date"`
+	EndDate   pgtype.Date `json:"end_date"`
+}
+
+func (q *Queries) ListRoomNights(ctx context.Context, arg ListRoomNightsParams) ([]RoomAvailability, error) {
+	rows, err := q.db.Query(ctx, listRoomNights, arg.RoomID, arg.StartDate, arg.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []RoomAvailability{}
+	for rows.Next() {
+		var i RoomAvailability
+		if err := rows.Scan(
+			&i.RoomID,
+			&i.Date,
+			&i.IsAvailable,
+			&i.NightRate,
+			&i.Version,
+			&i.UpdatedAt,
+			&i.BookedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUpcomingNightMetrics = `-- name: ListUpcomingNightMetrics :many
+SELECT
+  a.room_id,
+  a.date,
+  a.is_available,
+  a.night_rate,
+  CAST((s.nights - s.booked_nights) * 100.0 / s.nights AS DECIMAL(10,2)) AS availability_percentage
+FROM room_availability AS a
+LEFT JOIN room_month_stats AS s
+  ON s.room_id = a.room_id AND s.month = date_trunc('month', a.date)::date
+WHERE a.room_id = ANY($1::int[])
+  AND a.date >= CURRENT_DATE
+  AND a.date < CURRENT_DATE + INTERVAL '30 days'
+ORDER BY a.room_id, a.date
+`
+
+type ListUpcomingNightMetricsRow struct {
+	RoomID                 int32          `json:"room_id"`
+	Date                   pgtype.Date    `json:"date"`
+	IsAvailable            bool           `json:"is_available"`
+	NightRate              int32          `json:"night_rate"`
+	AvailabilityPercentage pgtype.Numeric `json:"availability_percentage"`
+}
+
+// The nights of the next 30 days of the given rooms, with the availability percentage of their month.
+func (q *Queries) ListUpcomingNightMetrics(ctx context.Context, roomIds []int32) ([]ListUpcomingNightMetricsRow, error) {
+	rows, err := q.db.Query(ctx, listUpcomingNightMetrics, roomIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUpcomingNightMetricsRow{}
+	for rows.Next() {
+		var i ListUpcomingNightMetricsRow
+		if err := rows.Scan(
+			&i.RoomID,
+			&i.Date,
+			&i.IsAvailable,
+			&i.NightRate,
+			&i.AvailabilityPercentage,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUpcomingRates = `-- name: ListUpcomingRates :many
+SELECT
+  room_id,
+  AVG(night_rate)::float8 AS average_rate,
+  MAX(night_rate)::integer AS highest_rate,
+  MIN(night_rate)::integer AS lowest_rate
+FROM room_availability
+WHERE room_id = ANY($1::int[])
+  AND date >= CURRENT_DATE
+  AND date < CURRENT_DATE + INTERVAL '30 days'
+GROUP BY room_id
+ORDER BY room_id
+`
+
+type ListUpcomingRatesRow struct {
+	RoomID      int32   `json:"room_id"`
+	AverageRate float64 `json:"average_rate"`
+	HighestRate int32   `json:"highest_rate"`
+	LowestRate  int32   `json:"lowest_rate"`
+}
+
+// Average, highest and lowest rates of the next 30 days of every room having nights in that window.
+func (q *Queries) ListUpcomingRates(ctx context.Context, roomIds []int32) ([]ListUpcomingRatesRow, error) {
+	rows, err := q.db.Query(ctx, listUpcomingRates, roomIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUpcomingRatesRow{}
+	for rows.Next() {
+		var i ListUpcomingRatesRow
+		if err := rows.Scan(
+			&i.RoomID,
+			&i.AverageRate,
+			&i.HighestRate,
+			&i.LowestRate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateRoomAvailability = `-- name: UpdateRoomAvailability :one
 UPDATE room_availability
 SET is_available = $3,
-    night_rate = $4
-WHERE room_id = $1 AND date = $2
-RETURNING room_id, date, is_available, night_rate
+    night_rate = $4,
+    version = version + 1,
+    updated_at = now()
+WHERE room_id = $1 AND date = $2 AND version = $5
+RETURNING room_id, date, is_available, night_rate, version, updated_at, booked_at
 `
 
 type UpdateRoomAvailabilityParams struct {
@@ -270,6 +610,7 @@ type UpdateRoomAvailabilityParams struct {
 	Date        pgtype.Date `json:"date"`
 	IsAvailable bool        `json:"is_available"`
 	NightRate   int32       `json:"night_rate"`
+	Version     int32       `json:"version"`
 }
 
 func (q *Queries) UpdateRoomAvailability(ctx context.Context, arg UpdateRoomAvailabilityParams) (RoomAvailability, error) {
@@ -278,6 +619,51 @@ func (q *Queries) UpdateRoomAvailability(ctx context.Context, arg UpdateRoomAvai
 		arg.Date,
 		arg.IsAvailable,
 		arg.NightRate,
+		arg.Version,
+	)
+	var i RoomAvailability
+	err := row.Scan(
+		&i.RoomID,
+		&i.Date,
+		&i.IsAvailable,
+		&i.NightRate,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.BookedAt,
+	)
+	return i, err
+}
+
+const upsertRoomAvailability = `-- name: UpsertRoomAvailability :one
+INSERT INTO room_availability (
+  room_id,
+  date,
+  is_available,
+  night_rate
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (room_id, date) DO UPDATE
+SET is_available = EXCLUDED.is_available,
+    night_rate = EXCLUDED.night_rate,
+    version = room_availability.version + 1,
+    updated_at = now()
+RETURNING room_id, date, is_available, night_rate, version, updated_at, booked_at
+`
+
+type UpsertRoomAvailabilityParams struct {
+	RoomID      int32       `json:"room_id"`
+	Date        pgtype.Date `json:"date"`
+	IsAvailable bool        `json:"is_available"`
+	NightRate   int32       `json:"night_rate"`
+}
+
+func (q *Queries) UpsertRoomAvailability(ctx context.Context, arg UpsertRoomAvailabilityParams) (RoomAvailability, error) {
+	row := q.db.QueryRow(ctx, upsertRoomAvailability,
+		arg.RoomID,
+		arg.Date,
+		arg.IsAvailable,
+		arg.NightRate,
 	)
 	var i RoomAvailability
 	err := row.Scan(
@@ -285,6 +671,67 @@ func (q *Queries) UpdateRoomAvailability(ctx context.Context, arg UpdateRoomAvai
 		&i.Date,
 		&i.IsAvailable,
 		&i.NightRate,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.BookedAt,
 	)
 	return i, err
 }
+
+const upsertRoomAvailabilityNights = `-- name: UpsertRoomAvailabilityNights :many
+INSERT INTO room_availability (
+  room_id,
+  date,
+  is_available,
+  night_rate
+)
+SELECT night.room_id, night.date, night.is_available, night.night_rate
+FROM unnest($1::int[], $2::date[], $3::boolean[], $4::int[]) AS night(room_id, date, is_available, night_rate)
+ON CONFLICT (room_id, date) DO UPDATE
+SET is_available = EXCLUDED.is_available,
+    night_rate = EXCLUDED.night_rate,
+    version = room_availability.version + 1,
+    updated_at = now()
+WHERE (room_availability.is_available, room_availability.night_rate) IS DISTINCT FROM (EXCLUDED.is_available, EXCLUDED.night_rate)
+RETURNING room_id, date
+`
+
+type UpsertRoomAvailabilityNightsParams struct {
+	RoomIds     []int32       `json:"room_ids"`
+	Dates       []pgtype.Date `json:"dates"`
+	IsAvailable []bool        `json:"is_available"`
+	NightRates  []int32       `json:"night_rates"`
+}
+
+type UpsertRoomAvailabilityNightsRow struct {
+	RoomID int32       `json:"room_id"`
+	Date   pgtype.Date `json:"date"`
+}
+
+// Inserts the given nights, or updates those that exist as UpsertRoomAvailability does: their version is
+// incremented and they keep their booking time while they stay booked. Nights that would not change are left
+// as they are and not returned.
+func (q *Queries) UpsertRoomAvailabilityNights(ctx context.Context, arg UpsertRoomAvailabilityNightsParams) ([]UpsertRoomAvailabilityNightsRow, error) {
+	rows, err := q.db.Query(ctx, upsertRoomAvailabilityNights,
+		arg.RoomIds,
+		arg.Dates,
+		arg.IsAvailable,
+		arg.NightRates,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []UpsertRoomAvailabilityNightsRow{}
+	for rows.Next() {
+		var i UpsertRoomAvailabilityNightsRow
+		if err := rows.Scan(&i.RoomID, &i.Date); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
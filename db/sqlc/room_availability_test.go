@@ -45,13 +45,13 @@ func TestCreateRoomAvailability(t *testing.T) {
 	deleteRoom(room, t)
 }
 
-func TestDeleteOldRoomAvailabilityData(t *testing.T) {
+func TestArchiveOldRoomAvailabilityData(t *testing.T) {
 	room := createRandomRoom(1, t)
 	time := time.Date(2009, 11, 1, 0, 0, 0, 0, time.UTC)
 	entryDate := pgtype.Date{Valid: true, Time: time}
-	createRandomRoomAvailability(entryDate, room, t)
+	night := createRandomRoomAvailability(entryDate, room, t)
 
-	err := testQueries.DeleteOldRoomAvailabilityData(context.Background())
+	err := testQueries.ArchiveOldRoomAvailabilityData(context.Background())
 	require.NoError(t, err)
 
 	arg := db.GetRoomAvailabilityByDateParams{
@@ -64,6 +64,16 @@ func TestDeleteOldRoomAvailabilityData(t *testing.T) {
 	require.EqualError(t, err, pgx.ErrNoRows.Error())
 	require.Empty(t, room_availability)
 
+	// The night is kept in the archive.
+	stats, err := testQueries.GetRoomPeriodStats(context.Background(), db.GetRoomPeriodStatsParams{
+		RoomID:   room.RoomID,
+		FromDate: entryDate,
+		ToDate:   entryDate,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), stats.Nights)
+	require.Equal(t, night.NightRate, stats.HighestRate)
+
 	deleteRoom(room, t)
 }
 
@@ -144,23 +154,33 @@ func TestGetDateCount(t *testing.T) {
 	entryDate := pgtype.Date{Valid: true, Time: time.Date(entryTime.Year(), entryTime.Month(), entryTime.Day(), 0, 0, 0, 0, time.UTC)}
 	createRandomRoomAvailability(entryDate, room, t)
 
-	count, err := testQueries.GetDateCount(context.Background())
+	count, err := testQueries.GetDateCount(context.Background(), room.RoomID)
 	require.NoError(t, err)
-	require.NotEmpty(t, count)
+	require.Equal(t, int64(1), count)
 
 	testQueries.DeleteAllAvailabilityForRoom(context.Background(), room.RoomID)
 	deleteRoom(room, t)
 }
 
+func TestGetCurrentDate(t *testing.T) {
+	today, err := testQueries.GetCurrentDate(context.Background())
+	require.NoError(t, err)
+	require.True(t, today.Valid)
+	require.WithinDuration(t, time.Now(), today.Time, 36*time.Hour) // The session time zone may differ from UTC.
+}
+
 func TestGetMaxDate(t *testing.T) {
 	room := createRandomRoom(1, t)
 	entryTime := time.Now().UTC()
 	entryDate := pgtype.Date{Valid: true, Time: time.Date(entryTime.Year(), entryTime.Month(), entryTime.Day(), 0, 0, 0, 0, time.UTC)}
 	createRandomRoomAvailability(entryDate, room, t)
 
-	max_date, err := testQueries.GetMaxDate(context.Background())
+	laterDate := pgtype.Date{Valid: true, Time: entryDate.Time.AddDate(0, 0, 10)}
+	createRandomRoomAvailability(laterDate, room, t)
+
+	max_date, err := testQueries.GetMaxDate(context.Background(), room.RoomID)
 	require.NoError(t, err)
-	require.NotEmpty(t, max_date)
+	require.Equal(t, laterDate, max_date)
 
 	testQueries.DeleteAllAvailabilityForRoom(context.Background(), room.RoomID)
 	deleteRoom(room, t)
@@ -298,6 +318,7 @@ func TestUpdateRoomAvailability(t *testing.T) {
 		Date:        newEntryDate,
 		IsAvailable: !availability_data.IsAvailable,
 		NightRate:   util.RandomPrice(),
+		Version:     availability_data.Version,
 	}
 	updated_availability_data, err := testQueries.UpdateRoomAvailability(context.Background(), arg)
 	require.NoError(t, err)
@@ -305,7 +326,197 @@ func TestUpdateRoomAvailability(t *testing.T) {
 	require.Equal(t, arg.Date, updated_availability_data.Date)
 	require.Equal(t, arg.IsAvailable, updated_availability_data.IsAvailable)
 	require.Equal(t, arg.NightRate, updated_availability_data.NightRate)
+	require.Equal(t, availability_data.Version+1, updated_availability_data.Version)
+
+	// The booking time is set when the night gets booked and cleared when it is released.
+	require.Equal(t, !availability_data.IsAvailable, availability_data.BookedAt.Valid)
+	require.Equal(t, !updated_availability_data.IsAvailable, updated_availability_data.BookedAt.Valid)
+
+	// An update based on the previous version is rejected.
+	_, err = testQueries.UpdateRoomAvailability(context.Background(), arg)
+	require.EqualError(t, err, pgx.ErrNoRows.Error())
+
+	testQueries.DeleteAllAvailabilityForRoom(context.Background(), room.RoomID)
+	deleteRoom(room, t)
+}
+
+func TestArchiveRoomPastAvailability(t *testing.T) {
+	room := createRandomRoom(1, t)
+	today := time.Now().UTC()
+	pastDate := pgtype.Date{Valid: true, Time: time.Date(today.Year(), today.Month(), today.Day()-3, 0, 0, 0, 0, time.UTC)}
+	futureDate := pgtype.Date{Valid: true, Time: time.Date(today.Year(), today.Month(), today.Day()+3, 0, 0, 0, 0, time.UTC)}
+	createRandomRoomAvailability(pastDate, room, t)
+	createRandomRoomAvailability(futureDate, room, t)
+
+	archived, err := testQueries.ArchiveRoomPastAvailability(context.Background(), room.RoomID)
+	require.NoError(t, err)
+	require.Equal(t, []pgtype.Date{pastDate}, archived)
+
+	count, err := testQueries.GetDateCount(context.Background(), room.RoomID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	// Archived nights are still counted in the statistics of their period.
+	stats, err := testQueries.GetRoomPeriodStats(context.Background(), db.GetRoomPeriodStatsParams{
+		RoomID:   room.RoomID,
+		FromDate: pastDate,
+		ToDate:   pastDate,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), stats.Nights)
+
+	testQueries.DeleteAllAvailabilityForRoom(context.Background(), room.RoomID)
+	deleteRoom(room, t)
+}
+
+func TestExtendRoomAvailability(t *testing.T) {
+	room := createRandomRoom(1, t)
+	today := time.Now().UTC()
+	existing := createRandomRoomAvailability(pgtype.Date{Valid: true, Time: time.Date(today.Year(), today.Month(), today.Day()+2, 0, 0, 0, 0, time.UTC)}, room, t)
+
+	arg := db.ExtendRoomAvailabilityParams{RoomID: room.RoomID, HorizonDays: 10}
+	added, err := testQueries.ExtendRoomAvailability(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, added, 9)
+	require.NotContains(t, added, existing.Date)
+
+	// Existing nights are kept, missing ones are added at the default rate of the room.
+	night, err := testQueries.GetRoomAvailabilityByDate(context.Background(), db.GetRoomAvailabilityByDateParams{RoomID: room.RoomID, Date: existing.Date})
+	require.NoError(t, err)
+	require.Equal(t, existing, night)
+
+	max_date, err := testQueries.GetMaxDate(context.Background(), room.RoomID)
+	require.NoError(t, err)
+	night, err = testQueries.GetRoomAvailabilityByDate(context.Background(), db.GetRoomAvailabilityByDateParams{RoomID: room.RoomID, Date: max_date})
+	require.NoError(t, err)
+	require.True(t, night.IsAvailable)
+	require.Equal(t, room.DefaultRate, night.NightRate)
+
+	// Running it again is a no-op.
+	added, err = testQueries.ExtendRoomAvailability(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, added)
 
 	testQueries.DeleteAllAvailabilityForRoom(context.Background(), room.RoomID)
 	deleteRoom(room, t)
 }
+
+func TestListRoomNights(t *testing.T) {
+	room := createRandomRoom(1, t)
+	startDate := time.Date(2090, 1, 1, 0, 0, 0, 0, time.UTC)
+	var nights []db.RoomAvailability
+	for i := 0; i < 5; i++ {
+		nights = append(nights, createRandomRoomAvailability(pgtype.Date{Valid: true, Time: startDate.AddDate(0, 0, i)}, room, t))
+	}
+
+	listed, err := testQueries.ListRoomNights(context.Background(), db.ListRoomNightsParams{
+		RoomID:    room.RoomID,
+		StartDate: pgtype.Date{Valid: true, Time: startDate.AddDate(0, 0, 1)},
+		EndDate:   pgtype.Date{Valid: true, Time: startDate.AddDate(0, 0, 4)},
+	})
+	require.NoError(t, err)
+	require.Len(t, listed, 3)
+	for i, night := range listed {
+		require.Equal(t, nights[i+1].Date, night.Date)
+		require.Equal(t, nights[i+1].NightRate, night.NightRate)
+	}
+
+	testQueries.DeleteAllAvailabilityForRoom(context.Background(), room.RoomID)
+	deleteRoom(room, t)
+}
+
+func TestSearchAvailableRooms(t *testing.T) {
+	room := createRandomRoom(1, t)
+	startDate := time.Date(2090, 1, 1, 0, 0, 0, 0, time.UTC)
+	var total int64
+	for i := 0; i < 3; i++ {
+		night, err := testQueries.CreateRoomAvailability(context.Background(), db.CreateRoomAvailabilityParams{
+			RoomID:      room.RoomID,
+			Date:        pgtype.Date{Valid: true, Time: startDate.AddDate(0, 0, i)},
+			IsAvailable: i < 2,
+			NightRate:   util.RandomPrice(),
+		})
+		require.NoError(t, err)
+		if i < 2 {
+			total += int64(night.NightRate)
+		}
+	}
+
+	search := func(nights int, guests int32) []db.SearchAvailableRoomsRow {
+		rows, err := testQueries.SearchAvailableRooms(context.Background(), db.SearchAvailableRoomsParams{
+			Guests:   guests,
+			Balcony:  room.Balcony,
+			Fridge:   room.Fridge,
+			CheckIn:  pgtype.Date{Valid: true, Time: startDate},
+			CheckOut: pgtype.Date{Valid: true, Time: startDate.AddDate(0, 0, nights)},
+			RowLimit: 10,
+		})
+		require.NoError(t, err)
+		return rows
+	}
+
+	rows := search(2, room.MaxGuests)
+	require.Len(t, rows, 1)
+	require.Equal(t, room.RoomID, rows[0].Room.RoomID)
+	require.Equal(t, total, rows[0].Total)
+
+	// The third night is booked, and the room hosts at most its maximum number of guests.
+	require.Empty(t, search(3, room.MaxGuests))
+	require.Empty(t, search(2, room.MaxGuests+1))
+
+	testQueries.DeleteAllAvailabilityForRoom(context.Background(), room.RoomID)
+	deleteRoom(room, t)
+}
+
+func TestListForRooms(t *testing.T) {
+	ctx := context.Background()
+	rooms := []db.Room{createRandomRoom(1, t), createRandomRoom(2, t)}
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	for _, room := range rooms {
+		for i := 0; i < 3; i++ {
+			_, err := testQueries.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{
+				RoomID:      room.RoomID,
+				Date:        pgtype.Date{Valid: true, Time: today.AddDate(0, 0, i)},
+				IsAvailable: true,
+				NightRate:   room.RoomID*1000 + int32(i)*100,
+			})
+			require.NoError(t, err)
+		}
+	}
+
+	listed, err := testQueries.ListRoomsByIDs(ctx, []int32{2, 1, 404})
+	require.NoError(t, err)
+	require.Len(t, listed, 2)
+	require.Equal(t, rooms[0], listed[0])
+	require.Equal(t, rooms[1], listed[1])
+
+	nights, err := testQueries.ListNightsForRooms(ctx, db.ListNightsForRoomsParams{
+		RoomIds:   []int32{2, 1},
+		StartDate: pgtype.Date{Valid: true, Time: today.AddDate(0, 0, 1)},
+		EndDate:   pgtype.Date{Valid: true, Time: today.AddDate(0, 0, 3)},
+	})
+	require.NoError(t, err)
+	require.Len(t, nights, 4)
+	require.Equal(t, int32(1), nights[0].RoomID)
+	require.Equal(t, int32(2), nights[3].RoomID)
+	require.Equal(t, int32(2200), nights[3].NightRate)
+
+	rates, err := testQueries.ListUpcomingRates(ctx, []int32{1, 2})
+	require.NoError(t, err)
+	require.Equal(t, []db.ListUpcomingRatesRow{
+		{RoomID: 1, AverageRate: 1100, HighestRate: 1200, LowestRate: 1000},
+		{RoomID: 2, AverageRate: 2100, HighestRate: 2200, LowestRate: 2000},
+	}, rates)
+
+	stats, err := testQueries.ListMonthStatsForRooms(ctx, []int32{1, 2})
+	require.NoError(t, err)
+	require.NotEmpty(t, stats)
+	require.Equal(t, int32(1), stats[0].RoomID)
+	require.Equal(t, int32(2), stats[len(stats)-1].RoomID)
+
+	for _, room := range rooms {
+		testQueries.DeleteAllAvailabilityForRoom(ctx, room.RoomID)
+		deleteRoom(room, t)
+	}
+}
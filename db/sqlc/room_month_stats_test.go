@@ -0,0 +1,71 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+func TestRoomMonthStats(t *testing.T) {
+	ctx := context.Background()
+	room := createRandomRoom(1, t)
+
+	// Three nights in May and one in June of a year beyond the seeded calendars.
+	month := time.Date(2091, 5, 1, 0, 0, 0, 0, time.UTC)
+	for day, rate := range []int32{5000, 6000, 7000} {
+		_, err := testQueries.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{
+			RoomID:      room.RoomID,
+			Date:        pgtype.Date{Time: month.AddDate(0, 0, day), Valid: true},
+			IsAvailable: day != 1,
+			NightRate:   rate,
+		})
+		require.NoError(t, err)
+	}
+	june := pgtype.Date{Time: month.AddDate(0, 1, 0), Valid: true}
+	juneNight, err := testQueries.CreateRoomAvailability(ctx, db.CreateRoomAvailabilityParams{RoomID: room.RoomID, Date: june, NightRate: 8000})
+	require.NoError(t, err)
+
+	stats, err := testQueries.ListRoomMonthStats(ctx, room.RoomID)
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+	require.Equal(t, month, stats[0].Month.Time)
+	require.Equal(t, int32(3), stats[0].Nights)
+	require.Equal(t, int32(1), stats[0].BookedNights)
+	require.Equal(t, int64(6000), stats[0].Revenue)
+	require.Equal(t, int64(18000), stats[0].RateSum)
+	require.Equal(t, int32(5000), stats[0].LowestRate)
+	require.Equal(t, int32(7000), stats[0].HighestRate)
+
+	// Updates refresh the month of the night, and months left without nights are dropped.
+	_, err = testQueries.UpdateRoomAvailability(ctx, db.UpdateRoomAvailabilityParams{
+		RoomID: room.RoomID, Date: june, IsAvailable: false, NightRate: 9000, Version: juneNight.Version,
+	})
+	require.NoError(t, err)
+	stats, err = testQueries.ListRoomMonthStats(ctx, room.RoomID)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), stats[1].BookedNights)
+	require.Equal(t, int64(9000), stats[1].Revenue)
+
+	deleted, err := testQueries.DeleteRoomAvailabilityNights(ctx, db.DeleteRoomAvailabilityNightsParams{RoomIds: []int32{room.RoomID}, Dates: []pgtype.Date{june}})
+	require.NoError(t, err)
+	require.Equal(t, []db.DeleteRoomAvailabilityNightsRow{{RoomID: room.RoomID, Date: june}}, deleted)
+	stats, err = testQueries.ListRoomMonthStats(ctx, room.RoomID)
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+
+	inconsistent, err := testQueries.ListInconsistentRoomMonthStats(ctx)
+	require.NoError(t, err)
+	for _, month := range inconsistent {
+		require.NotEqual(t, room.RoomID, month.RoomID)
+	}
+
+	testQueries.DeleteAllAvailabilityForRoom(ctx, room.RoomID)
+	stats, err = testQueries.ListRoomMonthStats(ctx, room.RoomID)
+	require.NoError(t, err)
+	require.Empty(t, stats)
+	deleteRoom(room, t)
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 	db "github.com/vivek-344/airbnb-api/db/sqlc"
 	"github.com/vivek-344/airbnb-api/util"
@@ -18,6 +19,7 @@ func createRandomRoom(room_id int32, t *testing.T) db.Room {
 		Fridge:        util.RandomBool(),
 		IndoorPool:    util.RandomBool(),
 		GamingConsole: util.RandomBool(),
+		DefaultRate:   util.RandomPrice(),
 	}
 
 	room, err := testQueries.CreateRoom(context.Background(), arg)
@@ -31,6 +33,7 @@ func createRandomRoom(room_id int32, t *testing.T) db.Room {
 	require.Equal(t, arg.Fridge, room.Fridge)
 	require.Equal(t, arg.IndoorPool, room.IndoorPool)
 	require.Equal(t, arg.GamingConsole, room.GamingConsole)
+	require.Equal(t, arg.DefaultRate, room.DefaultRate)
 
 	return room
 }
@@ -160,3 +163,56 @@ func TestUpdateRoomFridge(t *testing.T) {
 
 	deleteRoom(room, t)
 }
+
+func TestUpdateRoom(t *testing.T) {
+	room := createRandomRoom(1, t)
+	require.Equal(t, int32(1), room.Version)
+
+	args := db.UpdateRoomParams{
+		RoomID:      room.RoomID,
+		Version:     room.Version,
+		Balcony:     pgtype.Bool{Bool: !room.Balcony, Valid: true},
+		DefaultRate: pgtype.Int4{Int32: room.DefaultRate + 100, Valid: true},
+	}
+
+	updatedRoom, err := testQueries.UpdateRoom(context.Background(), args)
+	require.NoError(t, err)
+	require.Equal(t, room.MaxGuests, updatedRoom.MaxGuests)
+	require.Equal(t, !room.Balcony, updatedRoom.Balcony)
+	require.Equal(t, room.Fridge, updatedRoom.Fridge)
+	require.Equal(t, room.DefaultRate+100, updatedRoom.DefaultRate)
+	require.Equal(t, room.Version+1, updatedRoom.Version)
+	require.False(t, updatedRoom.UpdatedAt.Before(room.UpdatedAt))
+
+	// An update based on the previous version is rejected.
+	_, err = testQueries.UpdateRoom(context.Background(), args)
+	require.EqualError(t, err, pgx.ErrNoRows.Error())
+
+	deleteRoom(room, t)
+}
+
+func TestUpsertRoom(t *testing.T) {
+	room := createRandomRoom(1, t)
+	args := db.UpsertRoomParams{
+		RoomID:        room.RoomID,
+		MaxGuests:     room.MaxGuests,
+		Balcony:       room.Balcony,
+		Fridge:        room.Fridge,
+		IndoorPool:    room.IndoorPool,
+		GamingConsole: room.GamingConsole,
+		DefaultRate:   room.DefaultRate,
+	}
+
+	// Upserting the same fields leaves the room as it is.
+	unchanged, err := testQueries.UpsertRoom(context.Background(), args)
+	require.NoError(t, err)
+	require.Equal(t, room, unchanged)
+
+	args.DefaultRate += 100
+	updatedRoom, err := testQueries.UpsertRoom(context.Background(), args)
+	require.NoError(t, err)
+	require.Equal(t, room.DefaultRate+100, updatedRoom.DefaultRate)
+	require.Equal(t, room.Version+1, updatedRoom.Version)
+
+	deleteRoom(room, t)
+}
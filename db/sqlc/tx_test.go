@@ -0,0 +1,51 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+	"github.com/vivek-344/airbnb-api/util"
+)
+
+// beginFunc returns a TxFunc running the queries in transactions begun on conn.
+func beginFunc(conn *pgxpool.Pool) db.TxFunc {
+	return func(ctx context.Context, fn func(db.Querier) error) error {
+		return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			return fn(db.New(tx))
+		})
+	}
+}
+
+func TestBeginFuncRollback(t *testing.T) {
+	failure := errors.New("failure")
+	arg := db.CreateRoomParams{RoomID: 1, MaxGuests: util.RandomGuests(), DefaultRate: util.RandomPrice()}
+
+	err := beginFunc(testDB)(context.Background(), func(queries db.Querier) error {
+		_, err := queries.CreateRoom(context.Background(), arg)
+		require.NoError(t, err)
+		return failure
+	})
+	require.ErrorIs(t, err, failure)
+
+	_, err = testQueries.GetRoom(context.Background(), arg.RoomID)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func TestBeginFuncCommit(t *testing.T) {
+	arg := db.CreateRoomParams{RoomID: 1, MaxGuests: util.RandomGuests(), DefaultRate: util.RandomPrice()}
+
+	err := beginFunc(testDB)(context.Background(), func(queries db.Querier) error {
+		_, err := queries.CreateRoom(context.Background(), arg)
+		return err
+	})
+	require.NoError(t, err)
+
+	room, err := testQueries.GetRoom(context.Background(), arg.RoomID)
+	require.NoError(t, err)
+	deleteRoom(room, t)
+}
@@ -0,0 +1,97 @@
+package db_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+func TestWebhookDeliveries(t *testing.T) {
+	ctx := context.Background()
+
+	// Other subscriptions may exist, so the test subscribes to an event type of its own.
+	eventType := fmt.Sprintf("test.%d", time.Now().UnixNano())
+	subscription, err := testQueries.CreateWebhookSubscription(ctx, db.CreateWebhookSubscriptionParams{
+		Url:        "http://localhost/hook",
+		Secret:     "secret",
+		EventTypes: []string{eventType},
+	})
+	require.NoError(t, err)
+	defer testQueries.DeleteWebhookSubscription(ctx, subscription.ID)
+	require.Equal(t, []string{eventType}, subscription.EventTypes)
+
+	created, err := testQueries.CreateWebhookDeliveries(ctx, db.CreateWebhookDeliveriesParams{
+		EventID:   uuid.New(),
+		EventType: eventType,
+		RoomID:    1,
+		Payload:   []byte(`{"type": "test"}`),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), created)
+
+	deliveries, err := testQueries.ListWebhookDeliveries(ctx, db.ListWebhookDeliveriesParams{SubscriptionID: subscription.ID, RowLimit: 10})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	delivery := deliveries[0]
+	require.Equal(t, "pending", delivery.Status)
+	require.Zero(t, delivery.Attempts)
+
+	// A failed attempt is retried after the delay.
+	delivery, err = testQueries.RecordWebhookDeliveryAttempt(ctx, db.RecordWebhookDeliveryAttemptParams{
+		ID:             delivery.ID,
+		Status:         "pending",
+		RetryDelay:     pgtype.Interval{Microseconds: time.Hour.Microseconds(), Valid: true},
+		ResponseStatus: pgtype.Int4{Int32: 500, Valid: true},
+		LastError:      pgtype.Text{String: "unexpected response status 500", Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), delivery.Attempts)
+	require.True(t, delivery.LastAttemptAt.Valid)
+	require.WithinDuration(t, delivery.LastAttemptAt.Time.Add(time.Hour), delivery.NextAttemptAt, time.Second)
+
+	// Invalid statuses are rejected.
+	_, err = testQueries.RecordWebhookDeliveryAttempt(ctx, db.RecordWebhookDeliveryAttemptParams{
+		ID:         delivery.ID,
+		Status:     "lost",
+		RetryDelay: pgtype.Interval{Valid: true},
+	})
+	require.Error(t, err)
+
+	replay, err := testQueries.ReplayWebhookDelivery(ctx, delivery.ID)
+	require.NoError(t, err)
+	require.Equal(t, delivery.EventID, replay.EventID)
+	require.Equal(t, pgtype.Int8{Int64: delivery.ID, Valid: true}, replay.ReplayOf)
+	require.Equal(t, "pending", replay.Status)
+
+	pending, err := testQueries.ListWebhookDeliveries(ctx, db.ListWebhookDeliveriesParams{
+		SubscriptionID: subscription.ID,
+		Status:         pgtype.Text{String: "pending", Valid: true},
+		RowLimit:       10,
+	})
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	require.Equal(t, replay.ID, pending[0].ID)
+
+	// Only the replay is due, the failed delivery waits for its retry.
+	subscriptionIDs, err := testQueries.ListDueWebhookSubscriptions(ctx)
+	require.NoError(t, err)
+	require.Contains(t, subscriptionIDs, subscription.ID)
+	due, err := testQueries.ListDueWebhookDeliveries(ctx, db.ListDueWebhookDeliveriesParams{SubscriptionID: subscription.ID, RowLimit: 10})
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	require.Equal(t, replay.ID, due[0].ID)
+
+	// Deleting the subscription deletes its delivery log.
+	deleted, err := testQueries.DeleteWebhookSubscription(ctx, subscription.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+	_, err = testQueries.GetWebhookDelivery(ctx, replay.ID)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
@@ -0,0 +1,2100 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.2
+// 	protoc        (unknown)
+// source: room_service.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Room struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoomId        int32                  `protobuf:"varint,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	MaxGuests     int32                  `protobuf:"varint,2,opt,name=max_guests,json=maxGuests,proto3" json:"max_guests,omitempty"`
+	Balcony       bool                   `protobuf:"varint,3,opt,name=balcony,proto3" json:"balcony,omitempty"`
+	Fridge        bool                   `protobuf:"varint,4,opt,name=fridge,proto3" json:"fridge,omitempty"`
+	IndoorPool    bool                   `protobuf:"varint,5,opt,name=indoor_pool,json=indoorPool,proto3" json:"indoor_pool,omitempty"`
+	GamingConsole bool                   `protobuf:"varint,6,opt,name=gaming_console,json=gamingConsole,proto3" json:"gaming_console,omitempty"`
+	DefaultRate   int32                  `protobuf:"varint,7,opt,name=default_rate,json=defaultRate,proto3" json:"default_rate,omitempty"`
+	Version       int32                  `protobuf:"varint,8,opt,name=version,proto3" json:"version,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+}
+
+func (x *Room) Reset() {
+	*x = Room{}
+	mi := &file_room_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Room) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Room) ProtoMessage() {}
+
+func (x *Room) ProtoReflect() protoreflect.Message {
+	mi := &file_room_service_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Room.ProtoReflect.Descriptor instead.
+func (*Room) Descriptor() ([]byte, []int) {
+	return file_room_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Room) GetRoomId() int32 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+func (x *Room) GetMaxGuests() int32 {
+	if x != nil {
+		return x.MaxGuests
+	}
+	return 0
+}
+
+func (x *Room) GetBalcony() bool {
+	if x != nil {
+		return x.Balcony
+	}
+	return false
+}
+
+func (x *Room) GetFridge() bool {
+	if x != nil {
+		return x.Fridge
+	}
+	return false
+}
+
+func (x *Room) GetIndoorPool() bool {
+	if x != nil {
+		return x.IndoorPool
+	}
+	return false
+}
+
+func (x *Room) GetGamingConsole() bool {
+	if x != nil {
+		return x.GamingConsole
+	}
+	return false
+}
+
+func (x *Room) GetDefaultRate() int32 {
+	if x != nil {
+		return x.DefaultRate
+	}
+	return 0
+}
+
+func (x *Room) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Room) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type Night struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoomId      int32                  `protobuf:"varint,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Date        string                 `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+	IsAvailable bool                   `protobuf:"varint,3,opt,name=is_available,json=isAvailable,proto3" json:"is_available,omitempty"`
+	NightRate   int32                  `protobuf:"varint,4,opt,name=night_rate,json=nightRate,proto3" json:"night_rate,omitempty"`
+	Version     int32                  `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`
+	UpdatedAt   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	BookedAt    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=booked_at,json=bookedAt,proto3" json:"booked_at,omitempty"` // Unset while the night is available.
+}
+
+func (x *Night) Reset() {
+	*x = Night{}
+	mi := &file_room_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Night) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Night) ProtoMessage() {}
+
+func (x *Night) ProtoReflect() protoreflect.Message {
+	mi := &file_room_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Night.ProtoReflect.Descriptor instead.
+func (*Night) Descriptor() ([]byte, []int) {
+	return file_room_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Night) GetRoomId() int32 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+func (x *Night) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *Night) GetIsAvailable() bool {
+	if x != nil {
+		return x.IsAvailable
+	}
+	return false
+}
+
+func (x *Night) GetNightRate() int32 {
+	if x != nil {
+		return x.NightRate
+	}
+	return 0
+}
+
+func (x *Night) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Night) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Night) GetBookedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.BookedAt
+	}
+	return nil
+}
+
+type GetRoomMetricsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoomId int32 `protobuf:"varint,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+}
+
+func (x *GetRoomMetricsRequest) Reset() {
+	*x = GetRoomMetricsRequest{}
+	mi := &file_room_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRoomMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoomMetricsRequest) ProtoMessage() {}
+
+func (x *GetRoomMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_room_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoomMetricsRequest.ProtoReflect.Descriptor instead.
+func (*GetRoomMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_room_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetRoomMetricsRequest) GetRoomId() int32 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+type RoomMetrics struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoomId              int32                            `protobuf:"varint,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	RatePerNight        []*RoomMetrics_NightRate         `protobuf:"bytes,2,rep,name=rate_per_night,json=ratePerNight,proto3" json:"rate_per_night,omitempty"`
+	MaxGuests           int32                            `protobuf:"varint,3,opt,name=max_guests,json=maxGuests,proto3" json:"max_guests,omitempty"`
+	AvailableDates      []string                         `protobuf:"bytes,4,rep,name=available_dates,json=availableDates,proto3" json:"available_dates,omitempty"`
+	OccupancyPercentage []*RoomMetrics_MonthAvailability `protobuf:"bytes,5,rep,name=occupancy_percentage,json=occupancyPercentage,proto3" json:"occupancy_percentage,omitempty"`
+	MonthlyStats        []*RoomMetrics_MonthStats        `protobuf:"bytes,6,rep,name=monthly_stats,json=monthlyStats,proto3" json:"monthly_stats,omitempty"`
+	AverageRate         float64                          `protobuf:"fixed64,7,opt,name=average_rate,json=averageRate,proto3" json:"average_rate,omitempty"`
+	HighestRate         int32                            `protobuf:"varint,8,opt,name=highest_rate,json=highestRate,proto3" json:"highest_rate,omitempty"`
+	LowestRate          int32                            `protobuf:"varint,9,opt,name=lowest_rate,json=lowestRate,proto3" json:"lowest_rate,omitempty"`
+	Balcony             bool                             `protobuf:"varint,10,opt,name=balcony,proto3" json:"balcony,omitempty"`
+	Fridge              bool                             `protobuf:"varint,11,opt,name=fridge,proto3" json:"fridge,omitempty"`
+	IndoorPool          bool                             `protobuf:"varint,12,opt,name=indoor_pool,json=indoorPool,proto3" json:"indoor_pool,omitempty"`
+	GamingConsole       bool                             `protobuf:"varint,13,opt,name=gaming_console,json=gamingConsole,proto3" json:"gaming_console,omitempty"`
+}
+
+func (x *RoomMetrics) Reset() {
+	*x = RoomMetrics{}
+	mi := &file_room_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomMetrics) ProtoMessage() {}
+
+func (x *RoomMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_room_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomMetrics.ProtoReflect.Descriptor instead.
+func (*RoomMetrics) Descriptor() ([]byte, []int) {
+	return file_room_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RoomMetrics) GetRoomId() int32 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+func (x *RoomMetrics) GetRatePerNight() []*RoomMetrics_NightRate {
+	if x != nil {
+		return x.RatePerNight
+	}
+	return nil
+}
+
+func (x *RoomMetrics) GetMaxGuests() int32 {
+	if x != nil {
+		return x.MaxGuests
+	}
+	return 0
+}
+
+func (x *RoomMetrics) GetAvailableDates() []string {
+	if x != nil {
+		return x.AvailableDates
+	}
+	return nil
+}
+
+func (x *RoomMetrics) GetOccupancyPercentage() []*RoomMetrics_MonthAvailability {
+	if x != nil {
+		return x.OccupancyPercentage
+	}
+	return nil
+}
+
+func (x *RoomMetrics) GetMonthlyStats() []*RoomMetrics_MonthStats {
+	if x != nil {
+		return x.MonthlyStats
+	}
+	return nil
+}
+
+func (x *RoomMetrics) GetAverageRate() float64 {
+	if x != nil {
+		return x.AverageRate
+	}
+	return 0
+}
+
+func (x *RoomMetrics) GetHighestRate() int32 {
+	if x != nil {
+		return x.HighestRate
+	}
+	return 0
+}
+
+func (x *RoomMetrics) GetLowestRate() int32 {
+	if x != nil {
+		return x.LowestRate
+	}
+	return 0
+}
+
+func (x *RoomMetrics) GetBalcony() bool {
+	if x != nil {
+		return x.Balcony
+	}
+	return false
+}
+
+func (x *RoomMetrics) GetFridge() bool {
+	if x != nil {
+		return x.Fridge
+	}
+	return false
+}
+
+func (x *RoomMetrics) GetIndoorPool() bool {
+	if x != nil {
+		return x.IndoorPool
+	}
+	return false
+}
+
+func (x *RoomMetrics) GetGamingConsole() bool {
+	if x != nil {
+		return x.GamingConsole
+	}
+	return false
+}
+
+type CreateRoomRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoomId        int32 `protobuf:"varint,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	MaxGuests     int32 `protobuf:"varint,2,opt,name=max_guests,json=maxGuests,proto3" json:"max_guests,omitempty"`
+	Balcony       bool  `protobuf:"varint,3,opt,name=balcony,proto3" json:"balcony,omitempty"`
+	Fridge        bool  `protobuf:"varint,4,opt,name=fridge,proto3" json:"fridge,omitempty"`
+	IndoorPool    bool  `protobuf:"varint,5,opt,name=indoor_pool,json=indoorPool,proto3" json:"indoor_pool,omitempty"`
+	GamingConsole bool  `protobuf:"varint,6,opt,name=gaming_console,json=gamingConsole,proto3" json:"gaming_console,omitempty"`
+	DefaultRate   int32 `protobuf:"varint,7,opt,name=default_rate,json=defaultRate,proto3" json:"default_rate,omitempty"`
+}
+
+func (x *CreateRoomRequest) Reset() {
+	*x = CreateRoomRequest{}
+	mi := &file_room_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRoomRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRoomRequest) ProtoMessage() {}
+
+func (x *CreateRoomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_room_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRoomRequest.ProtoReflect.Descriptor instead.
+func (*CreateRoomRequest) Descriptor() ([]byte, []int) {
+	return file_room_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateRoomRequest) GetRoomId() int32 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+func (x *CreateRoomRequest) GetMaxGuests() int32 {
+	if x != nil {
+		return x.MaxGuests
+	}
+	return 0
+}
+
+func (x *CreateRoomRequest) GetBalcony() bool {
+	if x != nil {
+		return x.Balcony
+	}
+	return false
+}
+
+func (x *CreateRoomRequest) GetFridge() bool {
+	if x != nil {
+		return x.Fridge
+	}
+	return false
+}
+
+func (x *CreateRoomRequest) GetIndoorPool() bool {
+	if x != nil {
+		return x.IndoorPool
+	}
+	return false
+}
+
+func (x *CreateRoomRequest) GetGamingConsole() bool {
+	if x != nil {
+		return x.GamingConsole
+	}
+	return false
+}
+
+func (x *CreateRoomRequest) GetDefaultRate() int32 {
+	if x != nil {
+		return x.DefaultRate
+	}
+	return 0
+}
+
+type GetRoomRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoomId int32 `protobuf:"varint,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+}
+
+func (x *GetRoomRequest) Reset() {
+	*x = GetRoomRequest{}
+	mi := &file_room_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRoomRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoomRequest) ProtoMessage() {}
+
+func (x *GetRoomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_room_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoomRequest.ProtoReflect.Descriptor instead.
+func (*GetRoomRequest) Descriptor() ([]byte, []int) {
+	return file_room_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetRoomRequest) GetRoomId() int32 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+type ListRoomsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Limit  int32 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"` // 50 by default, at most 500.
+	Offset int32 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+}
+
+func (x *ListRoomsRequest) Reset() {
+	*x = ListRoomsRequest{}
+	mi := &file_room_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRoomsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRoomsRequest) ProtoMessage() {}
+
+func (x *ListRoomsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_room_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRoomsRequest.ProtoReflect.Descriptor instead.
+func (*ListRoomsRequest) Descriptor() ([]byte, []int) {
+	return file_room_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListRoomsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListRoomsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type ListRoomsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rooms []*Room `protobuf:"bytes,1,rep,name=rooms,proto3" json:"rooms,omitempty"`
+}
+
+func (x *ListRoomsResponse) Reset() {
+	*x = ListRoomsResponse{}
+	mi := &file_room_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRoomsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRoomsResponse) ProtoMessage() {}
+
+func (x *ListRoomsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_room_service_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRoomsResponse.ProtoReflect.Descriptor instead.
+func (*ListRoomsResponse) Descriptor() ([]byte, []int) {
+	return file_room_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListRoomsResponse) GetRooms() []*Room {
+	if x != nil {
+		return x.Rooms
+	}
+	return nil
+}
+
+// UpdateRoomRequest leaves the fields that are not set unchanged.
+type UpdateRoomRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoomId        int32  `protobuf:"varint,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Version       int32  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"` // The current version of the room, as returned with it.
+	MaxGuests     *int32 `protobuf:"varint,3,opt,name=max_guests,json=maxGuests,proto3,oneof" json:"max_guests,omitempty"`
+	Balcony       *bool  `protobuf:"varint,4,opt,name=balcony,proto3,oneof" json:"balcony,omitempty"`
+	Fridge        *bool  `protobuf:"varint,5,opt,name=fridge,proto3,oneof" json:"fridge,omitempty"`
+	IndoorPool    *bool  `protobuf:"varint,6,opt,name=indoor_pool,json=indoorPool,proto3,oneof" json:"indoor_pool,omitempty"`
+	GamingConsole *bool  `protobuf:"varint,7,opt,name=gaming_console,json=gamingConsole,proto3,oneof" json:"gaming_console,omitempty"`
+	DefaultRate   *int32 `protobuf:"varint,8,opt,name=default_rate,json=defaultRate,proto3,oneof" json:"default_rate,omitempty"`
+}
+
+func (x *UpdateRoomRequest) Reset() {
+	*x = UpdateRoomRequest{}
+	mi := &file_room_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRoomRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRoomRequest) ProtoMessage() {}
+
+func (x *UpdateRoomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_room_service_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRoomRequest.ProtoReflect.Descriptor instead.
+func (*UpdateRoomRequest) Descriptor() ([]byte, []int) {
+	return file_room_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdateRoomRequest) GetRoomId() int32 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+func (x *UpdateRoomRequest) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *UpdateRoomRequest) GetMaxGuests() int32 {
+	if x != nil && x.MaxGuests != nil {
+		return *x.MaxGuests
+	}
+	return 0
+}
+
+func (x *UpdateRoomRequest) GetBalcony() bool {
+	if x != nil && x.Balcony != nil {
+		return *x.Balcony
+	}
+	return false
+}
+
+func (x *UpdateRoomRequest) GetFridge() bool {
+	if x != nil && x.Fridge != nil {
+		return *x.Fridge
+	}
+	return false
+}
+
+func (x *UpdateRoomRequest) GetIndoorPool() bool {
+	if x != nil && x.IndoorPool != nil {
+		return *x.IndoorPool
+	}
+	return false
+}
+
+func (x *UpdateRoomRequest) GetGamingConsole() bool {
+	if x != nil && x.GamingConsole != nil {
+		return *x.GamingConsole
+	}
+	return false
+}
+
+func (x *UpdateRoomRequest) GetDefaultRate() int32 {
+	if x != nil && x.DefaultRate != nil {
+		return *x.DefaultRate
+	}
+	return 0
+}
+
+type DeleteRoomRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoomId int32 `protobuf:"varint,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+}
+
+func (x *DeleteRoomRequest) Reset() {
+	*x = DeleteRoomRequest{}
+	mi := &file_room_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRoomRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRoomRequest) ProtoMessage() {}
+
+func (x *DeleteRoomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_room_service_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRoomRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRoomRequest) Descriptor() ([]byte, []int) {
+	return file_room_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DeleteRoomRequest) GetRoomId() int32 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+type GetNightRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoomId int32  `protobuf:"varint,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Date   string `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+}
+
+func (x *GetNightRequest) Reset() {
+	*x = GetNightRequest{}
+	mi := &file_room_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNightRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNightRequest) ProtoMessage() {}
+
+func (x *GetNightRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_room_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNightRequest.ProtoReflect.Descriptor instead.
+func (*GetNightRequest) Descriptor() ([]byte, []int) {
+	return file_room_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetNightRequest) GetRoomId() int32 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+func (x *GetNightRequest) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+type ListNightsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoomId int32  `protobuf:"varint,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	From   string `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"` // First night, inclusive.
+	To     string `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`     // Last night, exclusive. At most a year after from.
+}
+
+func (x *ListNightsRequest) Reset() {
+	*x = ListNightsRequest{}
+	mi := &file_room_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNightsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNightsRequest) ProtoMessage() {}
+
+func (x *ListNightsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_room_service_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNightsRequest.ProtoReflect.Descriptor instead.
+func (*ListNightsRequest) Descriptor() ([]byte, []int) {
+	return file_room_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListNightsRequest) GetRoomId() int32 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+func (x *ListNightsRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *ListNightsRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+type ListNightsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Nights []*Night `protobuf:"bytes,1,rep,name=nights,proto3" json:"nights,omitempty"`
+}
+
+func (x *ListNightsResponse) Reset() {
+	*x = ListNightsResponse{}
+	mi := &file_room_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNightsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNightsResponse) ProtoMessage() {}
+
+func (x *ListNightsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_room_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNightsResponse.ProtoReflect.Descriptor instead.
+func (*ListNightsResponse) Descriptor() ([]byte, []int) {
+	return file_room_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListNightsResponse) GetNights() []*Night {
+	if x != nil {
+		return x.Nights
+	}
+	return nil
+}
+
+type UpdateNightRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoomId      int32  `protobuf:"varint,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Date        string `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+	Version     int32  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"` // The current version of the night, as returned with it.
+	IsAvailable bool   `protobuf:"varint,4,opt,name=is_available,json=isAvailable,proto3" json:"is_available,omitempty"`
+	NightRate   int32  `protobuf:"varint,5,opt,name=night_rate,json=nightRate,proto3" json:"night_rate,omitempty"`
+}
+
+func (x *UpdateNightRequest) Reset() {
+	*x = UpdateNightRequest{}
+	mi := &file_room_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateNightRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateNightRequest) ProtoMessage() {}
+
+func (x *UpdateNightRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_room_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateNightRequest.ProtoReflect.Descriptor instead.
+func (*UpdateNightRequest) Descriptor() ([]byte, []int) {
+	return file_room_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UpdateNightRequest) GetRoomId() int32 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+func (x *UpdateNightRequest) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *UpdateNightRequest) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *UpdateNightRequest) GetIsAvailable() bool {
+	if x != nil {
+		return x.IsAvailable
+	}
+	return false
+}
+
+func (x *UpdateNightRequest) GetNightRate() int32 {
+	if x != nil {
+		return x.NightRate
+	}
+	return 0
+}
+
+// Stay is a number of consecutive nights, from the check-in date to the day before the check-out date.
+type Stay struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CheckIn  string `protobuf:"bytes,1,opt,name=check_in,json=checkIn,proto3" json:"check_in,omitempty"`
+	CheckOut string `protobuf:"bytes,2,opt,name=check_out,json=checkOut,proto3" json:"check_out,omitempty"`
+	Guests   int32  `protobuf:"varint,3,opt,name=guests,proto3" json:"guests,omitempty"` // 1 by default.
+}
+
+func (x *Stay) Reset() {
+	*x = Stay{}
+	mi := &file_room_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Stay) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stay) ProtoMessage() {}
+
+func (x *Stay) ProtoReflect() protoreflect.Message {
+	mi := &file_room_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stay.ProtoReflect.Descriptor instead.
+func (*Stay) Descriptor() ([]byte, []int) {
+	return file_room_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Stay) GetCheckIn() string {
+	if x != nil {
+		return x.CheckIn
+	}
+	return ""
+}
+
+func (x *Stay) GetCheckOut() string {
+	if x != nil {
+		return x.CheckOut
+	}
+	return ""
+}
+
+func (x *Stay) GetGuests() int32 {
+	if x != nil {
+		return x.Guests
+	}
+	return 0
+}
+
+type SearchRoomsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Stay *Stay `protobuf:"bytes,1,opt,name=stay,proto3" json:"stay,omitempty"`
+	// Amenities the rooms must have.
+	Balcony       bool  `protobuf:"varint,2,opt,name=balcony,proto3" json:"balcony,omitempty"`
+	Fridge        bool  `protobuf:"varint,3,opt,name=fridge,proto3" json:"fridge,omitempty"`
+	IndoorPool    bool  `protobuf:"varint,4,opt,name=indoor_pool,json=indoorPool,proto3" json:"indoor_pool,omitempty"`
+	GamingConsole bool  `protobuf:"varint,5,opt,name=gaming_console,json=gamingConsole,proto3" json:"gaming_console,omitempty"`
+	Limit         int32 `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"` // 50 by default, at most 500.
+}
+
+func (x *SearchRoomsRequest) Reset() {
+	*x = SearchRoomsRequest{}
+	mi := &file_room_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchRoomsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchRoomsRequest) ProtoMessage() {}
+
+func (x *SearchRoomsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_room_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchRoomsRequest.ProtoReflect.Descriptor instead.
+func (*SearchRoomsRequest) Descriptor() ([]byte, []int) {
+	return file_room_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SearchRoomsRequest) GetStay() *Stay {
+	if x != nil {
+		return x.Stay
+	}
+	return nil
+}
+
+func (x *SearchRoomsRequest) GetBalcony() bool {
+	if x != nil {
+		return x.Balcony
+	}
+	return false
+}
+
+func (x *SearchRoomsRequest) GetFridge() bool {
+	if x != nil {
+		return x.Fridge
+	}
+	return false
+}
+
+func (x *SearchRoomsRequest) GetIndoorPool() bool {
+	if x != nil {
+		return x.IndoorPool
+	}
+	return false
+}
+
+func (x *SearchRoomsRequest) GetGamingConsole() bool {
+	if x != nil {
+		return x.GamingConsole
+	}
+	return false
+}
+
+func (x *SearchRoomsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type SearchRoomsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Results []*SearchRoomsResponse_Result `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *SearchRoomsResponse) Reset() {
+	*x = SearchRoomsResponse{}
+	mi := &file_room_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchRoomsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchRoomsResponse) ProtoMessage() {}
+
+func (x *SearchRoomsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_room_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchRoomsResponse.ProtoReflect.Descriptor instead.
+func (*SearchRoomsResponse) Descriptor() ([]byte, []int) {
+	return file_room_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SearchRoomsResponse) GetResults() []*SearchRoomsResponse_Result {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type GetQuoteRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoomId int32 `protobuf:"varint,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Stay   *Stay `protobuf:"bytes,2,opt,name=stay,proto3" json:"stay,omitempty"`
+}
+
+func (x *GetQuoteRequest) Reset() {
+	*x = GetQuoteRequest{}
+	mi := &file_room_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQuoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQuoteRequest) ProtoMessage() {}
+
+func (x *GetQuoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_room_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetQuoteRequest.ProtoReflect.Descriptor instead.
+func (*GetQuoteRequest) Descriptor() ([]byte, []int) {
+	return file_room_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetQuoteRequest) GetRoomId() int32 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+func (x *GetQuoteRequest) GetStay() *Stay {
+	if x != nil {
+		return x.Stay
+	}
+	return nil
+}
+
+type Quote struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoomId      int32          `protobuf:"varint,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	CheckIn     string         `protobuf:"bytes,2,opt,name=check_in,json=checkIn,proto3" json:"check_in,omitempty"`
+	CheckOut    string         `protobuf:"bytes,3,opt,name=check_out,json=checkOut,proto3" json:"check_out,omitempty"`
+	Guests      int32          `protobuf:"varint,4,opt,name=guests,proto3" json:"guests,omitempty"`
+	Nights      []*Quote_Night `protobuf:"bytes,5,rep,name=nights,proto3" json:"nights,omitempty"`
+	Total       int64          `protobuf:"varint,6,opt,name=total,proto3" json:"total,omitempty"`
+	AverageRate float64        `protobuf:"fixed64,7,opt,name=average_rate,json=averageRate,proto3" json:"average_rate,omitempty"`
+}
+
+func (x *Quote) Reset() {
+	*x = Quote{}
+	mi := &file_room_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Quote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Quote) ProtoMessage() {}
+
+func (x *Quote) ProtoReflect() protoreflect.Message {
+	mi := &file_room_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Quote.ProtoReflect.Descriptor instead.
+func (*Quote) Descriptor() ([]byte, []int) {
+	return file_room_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Quote) GetRoomId() int32 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+func (x *Quote) GetCheckIn() string {
+	if x != nil {
+		return x.CheckIn
+	}
+	return ""
+}
+
+func (x *Quote) GetCheckOut() string {
+	if x != nil {
+		return x.CheckOut
+	}
+	return ""
+}
+
+func (x *Quote) GetGuests() int32 {
+	if x != nil {
+		return x.Guests
+	}
+	return 0
+}
+
+func (x *Quote) GetNights() []*Quote_Night {
+	if x != nil {
+		return x.Nights
+	}
+	return nil
+}
+
+func (x *Quote) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *Quote) GetAverageRate() float64 {
+	if x != nil {
+		return x.AverageRate
+	}
+	return 0
+}
+
+type RoomMetrics_NightRate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Date        string `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	IsAvailable bool   `protobuf:"varint,2,opt,name=is_available,json=isAvailable,proto3" json:"is_available,omitempty"`
+	NightRate   int32  `protobuf:"varint,3,opt,name=night_rate,json=nightRate,proto3" json:"night_rate,omitempty"`
+}
+
+func (x *RoomMetrics_NightRate) Reset() {
+	*x = RoomMetrics_NightRate{}
+	mi := &file_room_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomMetrics_NightRate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomMetrics_NightRate) ProtoMessage() {}
+
+func (x *RoomMetrics_NightRate) ProtoReflect() protoreflect.Message {
+	mi := &file_room_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomMetrics_NightRate.ProtoReflect.Descriptor instead.
+func (*RoomMetrics_NightRate) Descriptor() ([]byte, []int) {
+	return file_room_service_proto_rawDescGZIP(), []int{3, 0}
+}
+
+func (x *RoomMetrics_NightRate) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *RoomMetrics_NightRate) GetIsAvailable() bool {
+	if x != nil {
+		return x.IsAvailable
+	}
+	return false
+}
+
+func (x *RoomMetrics_NightRate) GetNightRate() int32 {
+	if x != nil {
+		return x.NightRate
+	}
+	return 0
+}
+
+type RoomMetrics_MonthAvailability struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Year                   int32   `protobuf:"varint,1,opt,name=year,proto3" json:"year,omitempty"`
+	Month                  int32   `protobuf:"varint,2,opt,name=month,proto3" json:"month,omitempty"`
+	AvailabilityPercentage float64 `protobuf:"fixed64,3,opt,name=availability_percentage,json=availabilityPercentage,proto3" json:"availability_percentage,omitempty"`
+}
+
+func (x *RoomMetrics_MonthAvailability) Reset() {
+	*x = RoomMetrics_MonthAvailability{}
+	mi := &file_room_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomMetrics_MonthAvailability) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomMetrics_MonthAvailability) ProtoMessage() {}
+
+func (x *RoomMetrics_MonthAvailability) ProtoReflect() protoreflect.Message {
+	mi := &file_room_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomMetrics_MonthAvailability.ProtoReflect.Descriptor instead.
+func (*RoomMetrics_MonthAvailability) Descriptor() ([]byte, []int) {
+	return file_room_service_proto_rawDescGZIP(), []int{3, 1}
+}
+
+func (x *RoomMetrics_MonthAvailability) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *RoomMetrics_MonthAvailability) GetMonth() int32 {
+	if x != nil {
+		return x.Month
+	}
+	return 0
+}
+
+func (x *RoomMetrics_MonthAvailability) GetAvailabilityPercentage() float64 {
+	if x != nil {
+		return x.AvailabilityPercentage
+	}
+	return 0
+}
+
+type RoomMetrics_MonthStats struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Month               string  `protobuf:"bytes,1,opt,name=month,proto3" json:"month,omitempty"`
+	Nights              int32   `protobuf:"varint,2,opt,name=nights,proto3" json:"nights,omitempty"`
+	BookedNights        int32   `protobuf:"varint,3,opt,name=booked_nights,json=bookedNights,proto3" json:"booked_nights,omitempty"`
+	OccupancyPercentage float64 `protobuf:"fixed64,4,opt,name=occupancy_percentage,json=occupancyPercentage,proto3" json:"occupancy_percentage,omitempty"`
+	Revenue             int64   `protobuf:"varint,5,opt,name=revenue,proto3" json:"revenue,omitempty"`
+	AverageRate         float64 `protobuf:"fixed64,6,opt,name=average_rate,json=averageRate,proto3" json:"average_rate,omitempty"`
+	LowestRate          int32   `protobuf:"varint,7,opt,name=lowest_rate,json=lowestRate,proto3" json:"lowest_rate,omitempty"`
+	HighestRate         int32   `protobuf:"varint,8,opt,name=highest_rate,json=highestRate,proto3" json:"highest_rate,omitempty"`
+}
+
+func (x *RoomMetrics_MonthStats) Reset() {
+	*x = RoomMetrics_MonthStats{}
+	mi := &file_room_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomMetrics_MonthStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomMetrics_MonthStats) ProtoMessage() {}
+
+func (x *RoomMetrics_MonthStats) ProtoReflect() protoreflect.Message {
+	mi := &file_room_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomMetrics_MonthStats.ProtoReflect.Descriptor instead.
+func (*RoomMetrics_MonthStats) Descriptor() ([]byte, []int) {
+	return file_room_service_proto_rawDescGZIP(), []int{3, 2}
+}
+
+func (x *RoomMetrics_MonthStats) GetMonth() string {
+	if x != nil {
+		return x.Month
+	}
+	return ""
+}
+
+func (x *RoomMetrics_MonthStats) GetNights() int32 {
+	if x != nil {
+		return x.Nights
+	}
+	return 0
+}
+
+func (x *RoomMetrics_MonthStats) GetBookedNights() int32 {
+	if x != nil {
+		return x.BookedNights
+	}
+	return 0
+}
+
+func (x *RoomMetrics_MonthStats) GetOccupancyPercentage() float64 {
+	if x != nil {
+		return x.OccupancyPercentage
+	}
+	return 0
+}
+
+func (x *RoomMetrics_MonthStats) GetRevenue() int64 {
+	if x != nil {
+		return x.Revenue
+	}
+	return 0
+}
+
+func (x *RoomMetrics_MonthStats) GetAverageRate() float64 {
+	if x != nil {
+		return x.AverageRate
+	}
+	return 0
+}
+
+func (x *RoomMetrics_MonthStats) GetLowestRate() int32 {
+	if x != nil {
+		return x.LowestRate
+	}
+	return 0
+}
+
+func (x *RoomMetrics_MonthStats) GetHighestRate() int32 {
+	if x != nil {
+		return x.HighestRate
+	}
+	return 0
+}
+
+type SearchRoomsResponse_Result struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Room  *Room `protobuf:"bytes,1,opt,name=room,proto3" json:"room,omitempty"`
+	Total int64 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"` // The sum of the rates of the nights of the stay.
+}
+
+func (x *SearchRoomsResponse_Result) Reset() {
+	*x = SearchRoomsResponse_Result{}
+	mi := &file_room_service_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchRoomsResponse_Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchRoomsResponse_Result) ProtoMessage() {}
+
+func (x *SearchRoomsResponse_Result) ProtoReflect() protoreflect.Message {
+	mi := &file_room_service_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchRoomsResponse_Result.ProtoReflect.Descriptor instead.
+func (*SearchRoomsResponse_Result) Descriptor() ([]byte, []int) {
+	return file_room_service_proto_rawDescGZIP(), []int{16, 0}
+}
+
+func (x *SearchRoomsResponse_Result) GetRoom() *Room {
+	if x != nil {
+		return x.Room
+	}
+	return nil
+}
+
+func (x *SearchRoomsResponse_Result) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type Quote_Night struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Date      string `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	NightRate int32  `protobuf:"varint,2,opt,name=night_rate,json=nightRate,proto3" json:"night_rate,omitempty"`
+}
+
+func (x *Quote_Night) Reset() {
+	*x = Quote_Night{}
+	mi := &file_room_service_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Quote_Night) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Quote_Night) ProtoMessage() {}
+
+func (x *Quote_Night) ProtoReflect() protoreflect.Message {
+	mi := &file_room_service_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Quote_Night.ProtoReflect.Descriptor instead.
+func (*Quote_Night) Descriptor() ([]byte, []int) {
+	return file_room_service_proto_rawDescGZIP(), []int{18, 0}
+}
+
+func (x *Quote_Night) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *Quote_Night) GetNightRate() int32 {
+	if x != nil {
+		return x.NightRate
+	}
+	return 0
+}
+
+var File_room_service_proto protoreflect.FileDescriptor
+
+var file_room_service_proto_rawDesc = []byte{
+	0x0a, 0x12, 0x72, 0x6f, 0x6f, 0x6d, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09, 0x61, 0x69, 0x72, 0x62, 0x6e, 0x62, 0x2e, 0x76, 0x31, 0x1a,
+	0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xb0, 0x02,
+	0x0a, 0x04, 0x52, 0x6f, 0x6f, 0x6d, 0x12, 0x17, 0x0a, 0x07, 0x72, 0x6f, 0x6f, 0x6d, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x72, 0x6f, 0x6f, 0x6d, 0x49, 0x64, 0x12,
+	0x1d, 0x0a, 0x0a, 0x6d, 0x61, 0x78, 0x5f, 0x67, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x09, 0x6d, 0x61, 0x78, 0x47, 0x75, 0x65, 0x73, 0x74, 0x73, 0x12, 0x18,
+	0x0a, 0x07, 0x62, 0x61, 0x6c, 0x63, 0x6f, 0x6e, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x07, 0x62, 0x61, 0x6c, 0x63, 0x6f, 0x6e, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x72, 0x69, 0x64,
+	0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x66, 0x72, 0x69, 0x64, 0x67, 0x65,
+	0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x64, 0x6f, 0x6f, 0x72, 0x5f, 0x70, 0x6f, 0x6f, 0x6c, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x69, 0x6e, 0x64, 0x6f, 0x6f, 0x72, 0x50, 0x6f, 0x6f,
+	0x6c, 0x12, 0x25, 0x0a, 0x0e, 0x67, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x6f, 0x6e, 0x73,
+	0x6f, 0x6c, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x67, 0x61, 0x6d, 0x69, 0x6e,
+	0x67, 0x43, 0x6f, 0x6e, 0x73, 0x6f, 0x6c, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x66, 0x61,
+	0x75, 0x6c, 0x74, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b,
+	0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x52, 0x61, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x39, 0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64,
+	0x5f, 0x61, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74,
+	0x22, 0x84, 0x02, 0x0a, 0x05, 0x4e, 0x69, 0x67, 0x68, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x72, 0x6f,
+	0x6f, 0x6d, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x72, 0x6f, 0x6f,
+	0x6d, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x64, 0x61, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x73, 0x5f, 0x61, 0x76,
+	0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x69,
+	0x73, 0x41, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x69,
+	0x67, 0x68, 0x74, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09,
+	0x6e, 0x69, 0x67, 0x68, 0x74, 0x52, 0x61, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x39, 0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61,
+	0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x37,
+	0x0a, 0x09, 0x62, 0x6f, 0x6f, 0x6b, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x62,
+	0x6f, 0x6f, 0x6b, 0x65, 0x64, 0x41, 0x74, 0x22, 0x30, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x52, 0x6f,
+	0x6f, 0x6d, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x17, 0x0a, 0x07, 0x72, 0x6f, 0x6f, 0x6d, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x06, 0x72, 0x6f, 0x6f, 0x6d, 0x49, 0x64, 0x22, 0xad, 0x08, 0x0a, 0x0b, 0x52, 0x6f,
+	0x6f, 0x6d, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x72, 0x6f, 0x6f,
+	0x6d, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x72, 0x6f, 0x6f, 0x6d,
+	0x49, 0x64, 0x12, 0x46, 0x0a, 0x0e, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x6e,
+	0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x61, 0x69, 0x72,
+	0x62, 0x6e, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x6f, 0x6f, 0x6d, 0x4d, 0x65, 0x74, 0x72, 0x69,
+	0x63, 0x73, 0x2e, 0x4e, 0x69, 0x67, 0x68, 0x74, 0x52, 0x61, 0x74, 0x65, 0x52, 0x0c, 0x72, 0x61,
+	0x74, 0x65, 0x50, 0x65, 0x72, 0x4e, 0x69, 0x67, 0x68, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x61,
+	0x78, 0x5f, 0x67, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09,
+	0x6d, 0x61, 0x78, 0x47, 0x75, 0x65, 0x73, 0x74, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x61, 0x76, 0x61,
+	0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x0e, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x44, 0x61, 0x74,
+	0x65, 0x73, 0x12, 0x5b, 0x0a, 0x14, 0x6f, 0x63, 0x63, 0x75, 0x70, 0x61, 0x6e, 0x63, 0x79, 0x5f,
+	0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x28, 0x2e, 0x61, 0x69, 0x72, 0x62, 0x6e, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x6f, 0x6f,
+	0x6d, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x4d, 0x6f, 0x6e, 0x74, 0x68, 0x41, 0x76,
+	0x61, 0x69, 0x6c, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x13, 0x6f, 0x63, 0x63, 0x75,
+	0x70, 0x61, 0x6e, 0x63, 0x79, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x12,
+	0x46, 0x0a, 0x0d, 0x6d, 0x6f, 0x6e, 0x74, 0x68, 0x6c, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x73,
+	0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x61, 0x69, 0x72, 0x62, 0x6e, 0x62, 0x2e,
+	0x76, 0x31, 0x2e, 0x52, 0x6f, 0x6f, 0x6d, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x4d,
+	0x6f, 0x6e, 0x74, 0x68, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x0c, 0x6d, 0x6f, 0x6e, 0x74, 0x68,
+	0x6c, 0x79, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x76, 0x65, 0x72, 0x61,
+	0x67, 0x65, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x61,
+	0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x52, 0x61, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x68, 0x69,
+	0x67, 0x68, 0x65, 0x73, 0x74, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0b, 0x68, 0x69, 0x67, 0x68, 0x65, 0x73, 0x74, 0x52, 0x61, 0x74, 0x65, 0x12, 0x1f, 0x0a,
+	0x0b, 0x6c, 0x6f, 0x77, 0x65, 0x73, 0x74, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0a, 0x6c, 0x6f, 0x77, 0x65, 0x73, 0x74, 0x52, 0x61, 0x74, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x62, 0x61, 0x6c, 0x63, 0x6f, 0x6e, 0x79, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x07, 0x62, 0x61, 0x6c, 0x63, 0x6f, 0x6e, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x72, 0x69, 0x64,
+	0x67, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x66, 0x72, 0x69, 0x64, 0x67, 0x65,
+	0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x64, 0x6f, 0x6f, 0x72, 0x5f, 0x70, 0x6f, 0x6f, 0x6c, 0x18,
+	0x0c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x69, 0x6e, 0x64, 0x6f, 0x6f, 0x72, 0x50, 0x6f, 0x6f,
+	0x6c, 0x12, 0x25, 0x0a, 0x0e, 0x67, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x6f, 0x6e, 0x73,
+	0x6f, 0x6c, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x67, 0x61, 0x6d, 0x69, 0x6e,
+	0x67, 0x43, 0x6f, 0x6e, 0x73, 0x6f, 0x6c, 0x65, 0x1a, 0x61, 0x0a, 0x09, 0x4e, 0x69, 0x67, 0x68,
+	0x74, 0x52, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x61, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x73, 0x5f,
+	0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0b, 0x69, 0x73, 0x41, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x1d, 0x0a, 0x0a,
+	0x6e, 0x69, 0x67, 0x68, 0x74, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x09, 0x6e, 0x69, 0x67, 0x68, 0x74, 0x52, 0x61, 0x74, 0x65, 0x1a, 0x76, 0x0a, 0x11, 0x4d,
+	0x6f, 0x6e, 0x74, 0x68, 0x41, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79,
+	0x12, 0x12, 0x0a, 0x04, 0x79, 0x65, 0x61, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04,
+	0x79, 0x65, 0x61, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x6e, 0x74, 0x68, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x05, 0x6d, 0x6f, 0x6e, 0x74, 0x68, 0x12, 0x37, 0x0a, 0x17, 0x61, 0x76,
+	0x61, 0x69, 0x6c, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65,
+	0x6e, 0x74, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x16, 0x61, 0x76, 0x61,
+	0x69, 0x6c, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74,
+	0x61, 0x67, 0x65, 0x1a, 0x93, 0x02, 0x0a, 0x0a, 0x4d, 0x6f, 0x6e, 0x74, 0x68, 0x53, 0x74, 0x61,
+	0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x6e, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x6d, 0x6f, 0x6e, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x69, 0x67, 0x68,
+	0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x69, 0x67, 0x68, 0x74, 0x73,
+	0x12, 0x23, 0x0a, 0x0d, 0x62, 0x6f, 0x6f, 0x6b, 0x65, 0x64, 0x5f, 0x6e, 0x69, 0x67, 0x68, 0x74,
+	0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x62, 0x6f, 0x6f, 0x6b, 0x65, 0x64, 0x4e,
+	0x69, 0x67, 0x68, 0x74, 0x73, 0x12, 0x31, 0x0a, 0x14, 0x6f, 0x63, 0x63, 0x75, 0x70, 0x61, 0x6e,
+	0x63, 0x79, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x13, 0x6f, 0x63, 0x63, 0x75, 0x70, 0x61, 0x6e, 0x63, 0x79, 0x50, 0x65,
+	0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x76, 0x65,
+	0x6e, 0x75, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x72, 0x65, 0x76, 0x65, 0x6e,
+	0x75, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x5f, 0x72, 0x61,
+	0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67,
+	0x65, 0x52, 0x61, 0x74, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6c, 0x6f, 0x77, 0x65, 0x73, 0x74, 0x5f,
+	0x72, 0x61, 0x74, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x6c, 0x6f, 0x77, 0x65,
+	0x73, 0x74, 0x52, 0x61, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x68, 0x69, 0x67, 0x68, 0x65, 0x73,
+	0x74, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x68, 0x69,
+	0x67, 0x68, 0x65, 0x73, 0x74, 0x52, 0x61, 0x74, 0x65, 0x22, 0xe8, 0x01, 0x0a, 0x11, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x17, 0x0a, 0x07, 0x72, 0x6f, 0x6f, 0x6d, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x06, 0x72, 0x6f, 0x6f, 0x6d, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x61, 0x78, 0x5f,
+	0x67, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6d, 0x61,
+	0x78, 0x47, 0x75, 0x65, 0x73, 0x74, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x61, 0x6c, 0x63, 0x6f,
+	0x6e, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x62, 0x61, 0x6c, 0x63, 0x6f, 0x6e,
+	0x79, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x72, 0x69, 0x64, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x06, 0x66, 0x72, 0x69, 0x64, 0x67, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x64,
+	0x6f, 0x6f, 0x72, 0x5f, 0x70, 0x6f, 0x6f, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a,
+	0x69, 0x6e, 0x64, 0x6f, 0x6f, 0x72, 0x50, 0x6f, 0x6f, 0x6c, 0x12, 0x25, 0x0a, 0x0e, 0x67, 0x61,
+	0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x6f, 0x6e, 0x73, 0x6f, 0x6c, 0x65, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0d, 0x67, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x73, 0x6f, 0x6c,
+	0x65, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x72, 0x61, 0x74,
+	0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74,
+	0x52, 0x61, 0x74, 0x65, 0x22, 0x29, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x52, 0x6f, 0x6f, 0x6d, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x72, 0x6f, 0x6f, 0x6d, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x72, 0x6f, 0x6f, 0x6d, 0x49, 0x64, 0x22,
+	0x40, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x6f, 0x6f, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66,
+	0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65,
+	0x74, 0x22, 0x3a, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x6f, 0x6f, 0x6d, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x25, 0x0a, 0x05, 0x72, 0x6f, 0x6f, 0x6d, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x69, 0x72, 0x62, 0x6e, 0x62, 0x2e, 0x76,
+	0x31, 0x2e, 0x52, 0x6f, 0x6f, 0x6d, 0x52, 0x05, 0x72, 0x6f, 0x6f, 0x6d, 0x73, 0x22, 0xfa, 0x02,
+	0x0a, 0x11, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x6d, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x72, 0x6f, 0x6f, 0x6d, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x72, 0x6f, 0x6f, 0x6d, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x0a, 0x6d, 0x61, 0x78, 0x5f, 0x67, 0x75,
+	0x65, 0x73, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x09, 0x6d, 0x61,
+	0x78, 0x47, 0x75, 0x65, 0x73, 0x74, 0x73, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x07, 0x62, 0x61,
+	0x6c, 0x63, 0x6f, 0x6e, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x48, 0x01, 0x52, 0x07, 0x62,
+	0x61, 0x6c, 0x63, 0x6f, 0x6e, 0x79, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x66, 0x72, 0x69,
+	0x64, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x48, 0x02, 0x52, 0x06, 0x66, 0x72, 0x69,
+	0x64, 0x67, 0x65, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0b, 0x69, 0x6e, 0x64, 0x6f, 0x6f, 0x72,
+	0x5f, 0x70, 0x6f, 0x6f, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x48, 0x03, 0x52, 0x0a, 0x69,
+	0x6e, 0x64, 0x6f, 0x6f, 0x72, 0x50, 0x6f, 0x6f, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x2a, 0x0a, 0x0e,
+	0x67, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x6f, 0x6e, 0x73, 0x6f, 0x6c, 0x65, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x08, 0x48, 0x04, 0x52, 0x0d, 0x67, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x43, 0x6f,
+	0x6e, 0x73, 0x6f, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c, 0x64, 0x65, 0x66, 0x61,
+	0x75, 0x6c, 0x74, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x48, 0x05,
+	0x52, 0x0b, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x52, 0x61, 0x74, 0x65, 0x88, 0x01, 0x01,
+	0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x67, 0x75, 0x65, 0x73, 0x74, 0x73, 0x42,
+	0x0a, 0x0a, 0x08, 0x5f, 0x62, 0x61, 0x6c, 0x63, 0x6f, 0x6e, 0x79, 0x42, 0x09, 0x0a, 0x07, 0x5f,
+	0x66, 0x72, 0x69, 0x64, 0x67, 0x65, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x69, 0x6e, 0x64, 0x6f, 0x6f,
+	0x72, 0x5f, 0x70, 0x6f, 0x6f, 0x6c, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x67, 0x61, 0x6d, 0x69, 0x6e,
+	0x67, 0x5f, 0x63, 0x6f, 0x6e, 0x73, 0x6f, 0x6c, 0x65, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x64, 0x65,
+	0x66, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x22, 0x2c, 0x0a, 0x11, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x17, 0x0a, 0x07, 0x72, 0x6f, 0x6f, 0x6d, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x06, 0x72, 0x6f, 0x6f, 0x6d, 0x49, 0x64, 0x22, 0x3e, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x4e,
+	0x69, 0x67, 0x68, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x72,
+	0x6f, 0x6f, 0x6d, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x72, 0x6f,
+	0x6f, 0x6d, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x64, 0x61, 0x74, 0x65, 0x22, 0x50, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74,
+	0x4e, 0x69, 0x67, 0x68, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a,
+	0x07, 0x72, 0x6f, 0x6f, 0x6d, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06,
+	0x72, 0x6f, 0x6f, 0x6d, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x74, 0x6f, 0x22, 0x3e, 0x0a, 0x12, 0x4c, 0x69,
+	0x73, 0x74, 0x4e, 0x69, 0x67, 0x68, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x28, 0x0a, 0x06, 0x6e, 0x69, 0x67, 0x68, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x10, 0x2e, 0x61, 0x69, 0x72, 0x62, 0x6e, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x69, 0x67,
+	0x68, 0x74, 0x52, 0x06, 0x6e, 0x69, 0x67, 0x68, 0x74, 0x73, 0x22, 0x9d, 0x01, 0x0a, 0x12, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x4e, 0x69, 0x67, 0x68, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x17, 0x0a, 0x07, 0x72, 0x6f, 0x6f, 0x6d, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x06, 0x72, 0x6f, 0x6f, 0x6d, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61,
+	0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x61, 0x74, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x73, 0x5f, 0x61,
+	0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b,
+	0x69, 0x73, 0x41, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6e,
+	0x69, 0x67, 0x68, 0x74, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x09, 0x6e, 0x69, 0x67, 0x68, 0x74, 0x52, 0x61, 0x74, 0x65, 0x22, 0x56, 0x0a, 0x04, 0x53, 0x74,
+	0x61, 0x79, 0x12, 0x19, 0x0a, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x5f, 0x69, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x49, 0x6e, 0x12, 0x1b, 0x0a,
+	0x09, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x5f, 0x6f, 0x75, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x4f, 0x75, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x67, 0x75,
+	0x65, 0x73, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x67, 0x75, 0x65, 0x73,
+	0x74, 0x73, 0x22, 0xc9, 0x01, 0x0a, 0x12, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x6f, 0x6f,
+	0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x04, 0x73, 0x74, 0x61,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x69, 0x72, 0x62, 0x6e, 0x62,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x79, 0x52, 0x04, 0x73, 0x74, 0x61, 0x79, 0x12, 0x18,
+	0x0a, 0x07, 0x62, 0x61, 0x6c, 0x63, 0x6f, 0x6e, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x07, 0x62, 0x61, 0x6c, 0x63, 0x6f, 0x6e, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x72, 0x69, 0x64,
+	0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x66, 0x72, 0x69, 0x64, 0x67, 0x65,
+	0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x64, 0x6f, 0x6f, 0x72, 0x5f, 0x70, 0x6f, 0x6f, 0x6c, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x69, 0x6e, 0x64, 0x6f, 0x6f, 0x72, 0x50, 0x6f, 0x6f,
+	0x6c, 0x12, 0x25, 0x0a, 0x0e, 0x67, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x6f, 0x6e, 0x73,
+	0x6f, 0x6c, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x67, 0x61, 0x6d, 0x69, 0x6e,
+	0x67, 0x43, 0x6f, 0x6e, 0x73, 0x6f, 0x6c, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69,
+	0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x9b,
+	0x01, 0x0a, 0x13, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x6f, 0x6f, 0x6d, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x61, 0x69, 0x72, 0x62, 0x6e, 0x62,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x6f, 0x6f, 0x6d, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x1a, 0x43, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x12, 0x23, 0x0a, 0x04, 0x72, 0x6f, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0f, 0x2e, 0x61, 0x69, 0x72, 0x62, 0x6e, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x6f, 0x6f, 0x6d,
+	0x52, 0x04, 0x72, 0x6f, 0x6f, 0x6d, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x22, 0x4f, 0x0a, 0x0f,
+	0x47, 0x65, 0x74, 0x51, 0x75, 0x6f, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x17, 0x0a, 0x07, 0x72, 0x6f, 0x6f, 0x6d, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x06, 0x72, 0x6f, 0x6f, 0x6d, 0x49, 0x64, 0x12, 0x23, 0x0a, 0x04, 0x73, 0x74, 0x61, 0x79,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x69, 0x72, 0x62, 0x6e, 0x62, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x79, 0x52, 0x04, 0x73, 0x74, 0x61, 0x79, 0x22, 0x95, 0x02,
+	0x0a, 0x05, 0x51, 0x75, 0x6f, 0x74, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x72, 0x6f, 0x6f, 0x6d, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x72, 0x6f, 0x6f, 0x6d, 0x49, 0x64,
+	0x12, 0x19, 0x0a, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x5f, 0x69, 0x6e, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x49, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x63,
+	0x68, 0x65, 0x63, 0x6b, 0x5f, 0x6f, 0x75, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x63, 0x68, 0x65, 0x63, 0x6b, 0x4f, 0x75, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x67, 0x75, 0x65, 0x73,
+	0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x67, 0x75, 0x65, 0x73, 0x74, 0x73,
+	0x12, 0x2e, 0x0a, 0x06, 0x6e, 0x69, 0x67, 0x68, 0x74, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x16, 0x2e, 0x61, 0x69, 0x72, 0x62, 0x6e, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x6f,
+	0x74, 0x65, 0x2e, 0x4e, 0x69, 0x67, 0x68, 0x74, 0x52, 0x06, 0x6e, 0x69, 0x67, 0x68, 0x74, 0x73,
+	0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67,
+	0x65, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x61, 0x76,
+	0x65, 0x72, 0x61, 0x67, 0x65, 0x52, 0x61, 0x74, 0x65, 0x1a, 0x3a, 0x0a, 0x05, 0x4e, 0x69, 0x67,
+	0x68, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x64, 0x61, 0x74, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x69, 0x67, 0x68, 0x74, 0x5f,
+	0x72, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6e, 0x69, 0x67, 0x68,
+	0x74, 0x52, 0x61, 0x74, 0x65, 0x32, 0xe3, 0x05, 0x0a, 0x0b, 0x52, 0x6f, 0x6f, 0x6d, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4a, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x52, 0x6f, 0x6f, 0x6d,
+	0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x20, 0x2e, 0x61, 0x69, 0x72, 0x62, 0x6e, 0x62,
+	0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x6f, 0x6f, 0x6d, 0x4d, 0x65, 0x74, 0x72, 0x69,
+	0x63, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x61, 0x69, 0x72, 0x62,
+	0x6e, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x6f, 0x6f, 0x6d, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63,
+	0x73, 0x12, 0x3b, 0x0a, 0x0a, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x6d, 0x12,
+	0x1c, 0x2e, 0x61, 0x69, 0x72, 0x62, 0x6e, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x52, 0x6f, 0x6f, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e,
+	0x61, 0x69, 0x72, 0x62, 0x6e, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x6f, 0x6f, 0x6d, 0x12, 0x35,
+	0x0a, 0x07, 0x47, 0x65, 0x74, 0x52, 0x6f, 0x6f, 0x6d, 0x12, 0x19, 0x2e, 0x61, 0x69, 0x72, 0x62,
+	0x6e, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x6f, 0x6f, 0x6d, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x61, 0x69, 0x72, 0x62, 0x6e, 0x62, 0x2e, 0x76, 0x31,
+	0x2e, 0x52, 0x6f, 0x6f, 0x6d, 0x12, 0x46, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x6f, 0x6f,
+	0x6d, 0x73, 0x12, 0x1b, 0x2e, 0x61, 0x69, 0x72, 0x62, 0x6e, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x52, 0x6f, 0x6f, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1c, 0x2e, 0x61, 0x69, 0x72, 0x62, 0x6e, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x52, 0x6f, 0x6f, 0x6d, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a,
+	0x0a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x6d, 0x12, 0x1c, 0x2e, 0x61, 0x69,
+	0x72, 0x62, 0x6e, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x6f,
+	0x6f, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x61, 0x69, 0x72, 0x62,
+	0x6e, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x6f, 0x6f, 0x6d, 0x12, 0x42, 0x0a, 0x0a, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x6d, 0x12, 0x1c, 0x2e, 0x61, 0x69, 0x72, 0x62, 0x6e,
+	0x62, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x6d, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x38,
+	0x0a, 0x08, 0x47, 0x65, 0x74, 0x4e, 0x69, 0x67, 0x68, 0x74, 0x12, 0x1a, 0x2e, 0x61, 0x69, 0x72,
+	0x62, 0x6e, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x4e, 0x69, 0x67, 0x68, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x61, 0x69, 0x72, 0x62, 0x6e, 0x62, 0x2e,
+	0x76, 0x31, 0x2e, 0x4e, 0x69, 0x67, 0x68, 0x74, 0x12, 0x49, 0x0a, 0x0a, 0x4c, 0x69, 0x73, 0x74,
+	0x4e, 0x69, 0x67, 0x68, 0x74, 0x73, 0x12, 0x1c, 0x2e, 0x61, 0x69, 0x72, 0x62, 0x6e, 0x62, 0x2e,
+	0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4e, 0x69, 0x67, 0x68, 0x74, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x61, 0x69, 0x72, 0x62, 0x6e, 0x62, 0x2e, 0x76, 0x31,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4e, 0x69, 0x67, 0x68, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x0b, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4e, 0x69, 0x67,
+	0x68, 0x74, 0x12, 0x1d, 0x2e, 0x61, 0x69, 0x72, 0x62, 0x6e, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x4e, 0x69, 0x67, 0x68, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x10, 0x2e, 0x61, 0x69, 0x72, 0x62, 0x6e, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x69,
+	0x67, 0x68, 0x74, 0x12, 0x4c, 0x0a, 0x0b, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x6f, 0x6f,
+	0x6d, 0x73, 0x12, 0x1d, 0x2e, 0x61, 0x69, 0x72, 0x62, 0x6e, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x6f, 0x6f, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1e, 0x2e, 0x61, 0x69, 0x72, 0x62, 0x6e, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65,
+	0x61, 0x72, 0x63, 0x68, 0x52, 0x6f, 0x6f, 0x6d, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x38, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x51, 0x75, 0x6f, 0x74, 0x65, 0x12, 0x1a, 0x2e,
+	0x61, 0x69, 0x72, 0x62, 0x6e, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x51, 0x75, 0x6f,
+	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x61, 0x69, 0x72, 0x62,
+	0x6e, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x6f, 0x74, 0x65, 0x42, 0x24, 0x5a, 0x22, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x76, 0x69, 0x76, 0x65, 0x6b, 0x2d,
+	0x33, 0x34, 0x34, 0x2f, 0x61, 0x69, 0x72, 0x62, 0x6e, 0x62, 0x2d, 0x61, 0x70, 0x69, 0x2f, 0x70,
+	0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_room_service_proto_rawDescOnce sync.Once
+	file_room_service_proto_rawDescData = file_room_service_proto_rawDesc
+)
+
+func file_room_service_proto_rawDescGZIP() []byte {
+	file_room_service_proto_rawDescOnce.Do(func() {
+		file_room_service_proto_rawDescData = protoimpl.X.CompressGZIP(file_room_service_proto_rawDescData)
+	})
+	return file_room_service_proto_rawDescData
+}
+
+var file_room_service_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
+var file_room_service_proto_goTypes = []any{
+	(*Room)(nil),                          // 0: airbnb.v1.Room
+	(*Night)(nil),                         // 1: airbnb.v1.Night
+	(*GetRoomMetricsRequest)(nil),         // 2: airbnb.v1.GetRoomMetricsRequest
+	(*RoomMetrics)(nil),                   // 3: airbnb.v1.RoomMetrics
+	(*CreateRoomRequest)(nil),             // 4: airbnb.v1.CreateRoomRequest
+	(*GetRoomRequest)(nil),                // 5: airbnb.v1.GetRoomRequest
+	(*ListRoomsRequest)(nil),              // 6: airbnb.v1.ListRoomsRequest
+	(*ListRoomsResponse)(nil),             // 7: airbnb.v1.ListRoomsResponse
+	(*UpdateRoomRequest)(nil),             // 8: airbnb.v1.UpdateRoomRequest
+	(*DeleteRoomRequest)(nil),             // 9: airbnb.v1.DeleteRoomRequest
+	(*GetNightRequest)(nil),               // 10: airbnb.v1.GetNightRequest
+	(*ListNightsRequest)(nil),             // 11: airbnb.v1.ListNightsRequest
+	(*ListNightsResponse)(nil),            // 12: airbnb.v1.ListNightsResponse
+	(*UpdateNightRequest)(nil),            // 13: airbnb.v1.UpdateNightRequest
+	(*Stay)(nil),                          // 14: airbnb.v1.Stay
+	(*SearchRoomsRequest)(nil),            // 15: airbnb.v1.SearchRoomsRequest
+	(*SearchRoomsResponse)(nil),           // 16: airbnb.v1.SearchRoomsResponse
+	(*GetQuoteRequest)(nil),               // 17: airbnb.v1.GetQuoteRequest
+	(*Quote)(nil),                         // 18: airbnb.v1.Quote
+	(*RoomMetrics_NightRate)(nil),         // 19: airbnb.v1.RoomMetrics.NightRate
+	(*RoomMetrics_MonthAvailability)(nil), // 20: airbnb.v1.RoomMetrics.MonthAvailability
+	(*RoomMetrics_MonthStats)(nil),        // 21: airbnb.v1.RoomMetrics.MonthStats
+	(*SearchRoomsResponse_Result)(nil),    // 22: airbnb.v1.SearchRoomsResponse.Result
+	(*Quote_Night)(nil),                   // 23: airbnb.v1.Quote.Night
+	(*timestamppb.Timestamp)(nil),         // 24: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),                 // 25: google.protobuf.Empty
+}
+var file_room_service_proto_depIdxs = []int32{
+	24, // 0: airbnb.v1.Room.updated_at:type_name -> google.protobuf.Timestamp
+	24, // 1: airbnb.v1.Night.updated_at:type_name -> google.protobuf.Timestamp
+	24, // 2: airbnb.v1.Night.booked_at:type_name -> google.protobuf.Timestamp
+	19, // 3: airbnb.v1.RoomMetrics.rate_per_night:type_name -> airbnb.v1.RoomMetrics.NightRate
+	20, // 4: airbnb.v1.RoomMetrics.occupancy_percentage:type_name -> airbnb.v1.RoomMetrics.MonthAvailability
+	21, // 5: airbnb.v1.RoomMetrics.monthly_stats:type_name -> airbnb.v1.RoomMetrics.MonthStats
+	0,  // 6: airbnb.v1.ListRoomsResponse.rooms:type_name -> airbnb.v1.Room
+	1,  // 7: airbnb.v1.ListNightsResponse.nights:type_name -> airbnb.v1.Night
+	14, // 8: airbnb.v1.SearchRoomsRequest.stay:type_name -> airbnb.v1.Stay
+	22, // 9: airbnb.v1.SearchRoomsResponse.results:type_name -> airbnb.v1.SearchRoomsResponse.Result
+	14, // 10: airbnb.v1.GetQuoteRequest.stay:type_name -> airbnb.v1.Stay
+	23, // 11: airbnb.v1.Quote.nights:type_name -> airbnb.v1.Quote.Night
+	0,  // 12: airbnb.v1.SearchRoomsResponse.Result.room:type_name -> airbnb.v1.Room
+	2,  // 13: airbnb.v1.RoomService.GetRoomMetrics:input_type -> airbnb.v1.GetRoomMetricsRequest
+	4,  // 14: airbnb.v1.RoomService.CreateRoom:input_type -> airbnb.v1.CreateRoomRequest
+	5,  // 15: airbnb.v1.RoomService.GetRoom:input_type -> airbnb.v1.GetRoomRequest
+	6,  // 16: airbnb.v1.RoomService.ListRooms:input_type -> airbnb.v1.ListRoomsRequest
+	8,  // 17: airbnb.v1.RoomService.UpdateRoom:input_type -> airbnb.v1.UpdateRoomRequest
+	9,  // 18: airbnb.v1.RoomService.DeleteRoom:input_type -> airbnb.v1.DeleteRoomRequest
+	10, // 19: airbnb.v1.RoomService.GetNight:input_type -> airbnb.v1.GetNightRequest
+	11, // 20: airbnb.v1.RoomService.ListNights:input_type -> airbnb.v1.ListNightsRequest
+	13, // 21: airbnb.v1.RoomService.UpdateNight:input_type -> airbnb.v1.UpdateNightRequest
+	15, // 22: airbnb.v1.RoomService.SearchRooms:input_type -> airbnb.v1.SearchRoomsRequest
+	17, // 23: airbnb.v1.RoomService.GetQuote:input_type -> airbnb.v1.GetQuoteRequest
+	3,  // 24: airbnb.v1.RoomService.GetRoomMetrics:output_type -> airbnb.v1.RoomMetrics
+	0,  // 25: airbnb.v1.RoomService.CreateRoom:output_type -> airbnb.v1.Room
+	0,  // 26: airbnb.v1.RoomService.GetRoom:output_type -> airbnb.v1.Room
+	7,  // 27: airbnb.v1.RoomService.ListRooms:output_type -> airbnb.v1.ListRoomsResponse
+	0,  // 28: airbnb.v1.RoomService.UpdateRoom:output_type -> airbnb.v1.Room
+	25, // 29: airbnb.v1.RoomService.DeleteRoom:output_type -> google.protobuf.Empty
+	1,  // 30: airbnb.v1.RoomService.GetNight:output_type -> airbnb.v1.Night
+	12, // 31: airbnb.v1.RoomService.ListNights:output_type -> airbnb.v1.ListNightsResponse
+	1,  // 32: airbnb.v1.RoomService.UpdateNight:output_type -> airbnb.v1.Night
+	16, // 33: airbnb.v1.RoomService.SearchRooms:output_type -> airbnb.v1.SearchRoomsResponse
+	18, // 34: airbnb.v1.RoomService.GetQuote:output_type -> airbnb.v1.Quote
+	24, // [24:35] is the sub-list for method output_type
+	13, // [13:24] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
+}
+
+func init() { file_room_service_proto_init() }
+func file_room_service_proto_init() {
+	if File_room_service_proto != nil {
+		return
+	}
+	file_room_service_proto_msgTypes[8].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_room_service_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   24,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_room_service_proto_goTypes,
+		DependencyIndexes: file_room_service_proto_depIdxs,
+		MessageInfos:      file_room_service_proto_msgTypes,
+	}.Build()
+	File_room_service_proto = out.File
+	file_room_service_proto_rawDesc = nil
+	file_room_service_proto_goTypes = nil
+	file_room_service_proto_depIdxs = nil
+}
@@ -1,14 +1,39 @@
 package util
 
 import (
+	"time"
+
 	"github.com/spf13/viper"
 )
 
 // Config stores all configuration of the application.
 // The values are used by read by viper from a config file or environment variable.
 type Config struct {
-	DBSource      string `mapstructure:"DB_SOURCE"`
-	ServerAddress string `mapstructure:"SERVER_ADDRESS"`
+	DBSource          string `mapstructure:"DB_SOURCE"`
+	ServerAddress     string `mapstructure:"SERVER_ADDRESS"`
+	GRPCServerAddress string `mapstructure:"GRPC_SERVER_ADDRESS"`
+	TracingExporter   string `mapstructure:"TRACING_EXPORTER"`
+	TracingFile       string `mapstructure:"TRACING_FILE"`
+	LogLevel          string `mapstructure:"LOG_LEVEL"`
+	LogFormat         string `mapstructure:"LOG_FORMAT"`
+	AutoMigrate       bool   `mapstructure:"AUTO_MIGRATE"`
+
+	AvailabilityHorizonDays int32         `mapstructure:"AVAILABILITY_HORIZON_DAYS"`
+	AvailabilityJobInterval time.Duration `mapstructure:"AVAILABILITY_JOB_INTERVAL"`
+
+	PartitionMonthsAhead int           `mapstructure:"PARTITION_MONTHS_AHEAD"`
+	PartitionJobInterval time.Duration `mapstructure:"PARTITION_JOB_INTERVAL"`
+
+	OutboxJobInterval time.Duration `mapstructure:"OUTBOX_JOB_INTERVAL"`
+	OutboxSinks       []string      `mapstructure:"OUTBOX_SINKS"`
+	OutboxFile        string        `mapstructure:"OUTBOX_FILE"`
+	OutboxRetention   time.Duration `mapstructure:"OUTBOX_RETENTION"`
+
+	WebhookJobInterval time.Duration `mapstructure:"WEBHOOK_JOB_INTERVAL"`
+	WebhookMaxAttempts int32         `mapstructure:"WEBHOOK_MAX_ATTEMPTS"`
+	WebhookTimeout     time.Duration `mapstructure:"WEBHOOK_TIMEOUT"`
+
+	EventPollInterval time.Duration `mapstructure:"EVENT_POLL_INTERVAL"`
 }
 
 // LoadConfig reads configuration from file or environment variables.
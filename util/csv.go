@@ -0,0 +1,87 @@
+package util
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// availabilityColumns is the header expected in availability CSV files.
+var availabilityColumns = []string{"room_id", "date", "is_available", "night_rate"}
+
+// ReadAvailabilityCSV streams the rows of an availability CSV file with the header
+// `room_id,date,is_available,night_rate`. Parsing stops at the first invalid line.
+func ReadAvailabilityCSV(r io.Reader) iter.Seq2[db.CopyRoomAvailabilityParams, error] {
+	return func(yield func(db.CopyRoomAvailabilityParams, error) bool) {
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = len(availabilityColumns)
+		reader.ReuseRecord = true
+
+		header, err := reader.Read()
+		if err != nil {
+			yield(db.CopyRoomAvailabilityParams{}, fmt.Errorf("cannot read csv header: %w", err))
+			return
+		}
+		for i, column := range availabilityColumns {
+			if strings.TrimSpace(header[i]) != column {
+				yield(db.CopyRoomAvailabilityParams{}, fmt.Errorf("csv header must be %q", strings.Join(availabilityColumns, ",")))
+				return
+			}
+		}
+
+		for line := 2; ; line++ {
+			record, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				yield(db.CopyRoomAvailabilityParams{}, err)
+				return
+			}
+
+			row, err := parseAvailabilityRecord(record)
+			if err != nil {
+				yield(db.CopyRoomAvailabilityParams{}, fmt.Errorf("line %d: %w", line, err))
+				return
+			}
+			if !yield(row, nil) {
+				return
+			}
+		}
+	}
+}
+
+// parseAvailabilityRecord converts a CSV record into availability parameters.
+func parseAvailabilityRecord(record []string) (db.CopyRoomAvailabilityParams, error) {
+	roomID, err := strconv.ParseInt(strings.TrimSpace(record[0]), 10, 32)
+	if err != nil || roomID < 1 {
+		return db.CopyRoomAvailabilityParams{}, fmt.Errorf("invalid room_id %q", record[0])
+	}
+	date, err := time.Parse("2006-01-02", strings.TrimSpace(record[1]))
+	if err != nil {
+		return db.CopyRoomAvailabilityParams{}, fmt.Errorf("invalid date %q", record[1])
+	}
+	isAvailable, err := strconv.ParseBool(strings.TrimSpace(record[2]))
+	if err != nil {
+		return db.CopyRoomAvailabilityParams{}, fmt.Errorf("invalid is_available %q", record[2])
+	}
+	nightRate, err := strconv.ParseInt(strings.TrimSpace(record[3]), 10, 32)
+	if err != nil || nightRate < 1 {
+		return db.CopyRoomAvailabilityParams{}, fmt.Errorf("invalid night_rate %q", record[3])
+	}
+
+	return db.CopyRoomAvailabilityParams{
+		RoomID:      int32(roomID),
+		Date:        pgtype.Date{Time: date, Valid: true},
+		IsAvailable: isAvailable,
+		NightRate:   int32(nightRate),
+	}, nil
+}
@@ -0,0 +1,48 @@
+package util
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+func TestReadAvailabilityCSV(t *testing.T) {
+	input := "room_id,date,is_available,night_rate\n101,2024-07-01,true,5000\n101,2024-07-02,false,5500\n"
+
+	var rows []db.CopyRoomAvailabilityParams
+	for row, err := range ReadAvailabilityCSV(strings.NewReader(input)) {
+		require.NoError(t, err)
+		rows = append(rows, row)
+	}
+
+	require.Len(t, rows, 2)
+	require.Equal(t, int32(101), rows[1].RoomID)
+	require.Equal(t, time.Date(2024, 7, 2, 0, 0, 0, 0, time.UTC), rows[1].Date.Time)
+	require.False(t, rows[1].IsAvailable)
+	require.Equal(t, int32(5500), rows[1].NightRate)
+}
+
+func TestReadAvailabilityCSVInvalid(t *testing.T) {
+	testCases := map[string]string{
+		"header":     "room,date,is_available,night_rate\n",
+		"room_id":    "room_id,date,is_available,night_rate\nabc,2024-07-01,true,5000\n",
+		"date":       "room_id,date,is_available,night_rate\n101,01/07/2024,true,5000\n",
+		"night_rate": "room_id,date,is_available,night_rate\n101,2024-07-01,true,-1\n",
+		"columns":    "room_id,date,is_available,night_rate\n101,2024-07-01,true\n",
+	}
+
+	for name, input := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var err error
+			for _, err = range ReadAvailabilityCSV(strings.NewReader(input)) {
+				if err != nil {
+					break
+				}
+			}
+			require.Error(t, err)
+		})
+	}
+}
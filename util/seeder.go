@@ -0,0 +1,272 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+// SeedOptions configures the test data generated by a Seeder.
+type SeedOptions struct {
+	Seed        int64     // Seed of the random generator; equal seeds generate equal data.
+	Rooms       int       // Number of rooms to generate.
+	FirstRoomID int32     // ID of the first room; rooms get consecutive IDs.
+	StartDate   time.Time // First night of every calendar; the data only depends on the options when it is set explicitly.
+	HorizonDays int       // Number of nights in every calendar.
+
+	MinRate int32 // Lowest base nightly rate of a room.
+	MaxRate int32 // Highest base nightly rate of a room.
+
+	Occupancy           float64      // Target fraction of booked nights outside high season.
+	HighSeasonOccupancy float64      // Target fraction of booked nights during high season.
+	HighSeasonMonths    []time.Month // Months of the high season.
+	HighSeasonMarkup    float64      // Rate increase during high season, e.g. 0.3 for +30%.
+	WeekendMarkup       float64      // Rate increase on Friday and Saturday nights.
+	WeekendBooking      float64      // Chance that a free Friday or Saturday night gets a short booking.
+	MeanStayNights      float64      // Average length of a booking.
+	LongStayChance      float64      // Chance that a booking is a long block of several weeks.
+}
+
+// DefaultSeedOptions returns the options used by the `seed` command when no flag is given.
+// Calendars start today, so the data only repeats across days with an explicit StartDate.
+func DefaultSeedOptions() SeedOptions {
+	now := time.Now().UTC()
+	return SeedOptions{
+		Seed:                1,
+		Rooms:               25,
+		FirstRoomID:         100,
+		StartDate:           time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+		HorizonDays:         150,
+		MinRate:             5000,
+		MaxRate:             10000,
+		Occupancy:           0.55,
+		HighSeasonOccupancy: 0.85,
+		HighSeasonMonths:    []time.Month{time.May, time.June, time.December},
+		HighSeasonMarkup:    0.3,
+		WeekendMarkup:       0.2,
+		WeekendBooking:      0.3,
+		MeanStayNights:      3,
+		LongStayChance:      0.05,
+	}
+}
+
+// Validate reports the first invalid option.
+func (options SeedOptions) Validate() error {
+	switch {
+	case options.Rooms < 0:
+		return errors.New("number of rooms must not be negative")
+	case options.FirstRoomID < 1:
+		return errors.New("first room ID must be positive")
+	case options.HorizonDays < 0:
+		return errors.New("horizon must not be negative")
+	case options.MinRate < 1 || options.MaxRate < options.MinRate:
+		return errors.New("rates must be positive and the minimum must not exceed the maximum")
+	case !isFraction(options.Occupancy) || !isFraction(options.HighSeasonOccupancy):
+		return errors.New("occupancy must be between 0 and 1")
+	case !isFraction(options.WeekendBooking) || !isFraction(options.LongStayChance):
+		return errors.New("probabilities must be between 0 and 1")
+	case options.HighSeasonMarkup < 0 || options.WeekendMarkup < 0:
+		return errors.New("markups must not be negative")
+	case options.MeanStayNights < 1:
+		return errors.New("mean stay must be at least one night")
+	}
+	return nil
+}
+
+// isFraction reports whether value is within [0, 1].
+func isFraction(value float64) bool {
+	return value >= 0 && value <= 1
+}
+
+// Seeder deterministically generates rooms and their availability calendars.
+// Each room has its own random stream, so changing the number of rooms doesn't change the existing ones.
+type Seeder struct {
+	options SeedOptions
+}
+
+// NewSeeder creates a Seeder generating data according to options.
+func NewSeeder(options SeedOptions) *Seeder {
+	return &Seeder{options: options}
+}
+
+// random returns the random stream of the given room.
+func (seeder *Seeder) random(roomID int32, stream uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(uint64(seeder.options.Seed), uint64(roomID)<<8|stream))
+}
+
+// Room generates the room with the given ID. Its default rate is the base rate of its calendar.
+func (seeder *Seeder) Room(roomID int32) db.UpsertRoomParams {
+	r := seeder.random(roomID, 0)
+	room := db.UpsertRoomParams{
+		RoomID:        roomID,
+		MaxGuests:     2 + r.Int32N(7),
+		Balcony:       r.Float64() < 0.5,
+		Fridge:        r.Float64() < 0.7,
+		IndoorPool:    r.Float64() < 0.15,
+		GamingConsole: r.Float64() < 0.3,
+	}
+	room.DefaultRate = int32(math.Round(seeder.baseRate(room)/50) * 50)
+	return room
+}
+
+// baseRate returns the nightly rate of a room before seasonal and weekend markups.
+// Larger rooms tend to be more expensive.
+func (seeder *Seeder) baseRate(room db.UpsertRoomParams) float64 {
+	r := seeder.random(room.RoomID, 1)
+	position := 0.6*r.Float64() + 0.4*float64(room.MaxGuests-2)/6
+	return float64(seeder.options.MinRate) + position*float64(seeder.options.MaxRate-seeder.options.MinRate)
+}
+
+// Calendar generates the availability calendar of a room over the configured horizon.
+// Bookings are blocks of consecutive nights separated by free gaps, sized to reach the target occupancy.
+func (seeder *Seeder) Calendar(room db.UpsertRoomParams) []db.CopyRoomAvailabilityParams {
+	options := seeder.options
+	r := seeder.random(room.RoomID, 2)
+	baseRate := seeder.baseRate(room)
+
+	calendar := make([]db.CopyRoomAvailabilityParams, 0, options.HorizonDays)
+	booked := r.Float64() < options.Occupancy
+	remaining := 0
+	for day := 0; day < options.HorizonDays; day++ {
+		date := options.StartDate.AddDate(0, 0, day)
+		highSeason := slices.Contains(options.HighSeasonMonths, date.Month())
+		weekend := date.Weekday() == time.Friday || date.Weekday() == time.Saturday
+
+		// Alternate between bookings and free gaps whose length depends on the season's occupancy.
+		// Gaps may be empty, in which case the next booking starts right away.
+		for remaining == 0 {
+			booked = !booked
+			if booked {
+				remaining = seeder.stayLength(r)
+			} else {
+				occupancy := options.Occupancy
+				if highSeason {
+					occupancy = options.HighSeasonOccupancy
+				}
+				remaining = gapLength(r, options.MeanStayNights, occupancy)
+			}
+		}
+		remaining--
+
+		// Free weekend nights are often taken by short stays.
+		isAvailable := !booked
+		if isAvailable && weekend && r.Float64() < options.WeekendBooking {
+			isAvailable = false
+		}
+
+		rate := baseRate
+		if highSeason {
+			rate *= 1 + options.HighSeasonMarkup
+		}
+		if weekend {
+			rate *= 1 + options.WeekendMarkup
+		}
+		rate *= 0.95 + 0.1*r.Float64()
+
+		calendar = append(calendar, db.CopyRoomAvailabilityParams{
+			RoomID:      room.RoomID,
+			Date:        pgtype.Date{Time: date, Valid: true},
+			IsAvailable: isAvailable,
+			NightRate:   int32(math.Round(rate/50) * 50),
+		})
+	}
+	return calendar
+}
+
+// stayLength draws the number of nights of a booking.
+func (seeder *Seeder) stayLength(r *rand.Rand) int {
+	if r.Float64() < seeder.options.LongStayChance {
+		return 14 + r.IntN(29)
+	}
+	return geometric(r, seeder.options.MeanStayNights)
+}
+
+// gapLength draws the number of free nights between two bookings, so that on average
+// stays make up the given fraction of all nights.
+func gapLength(r *rand.Rand, meanStay float64, occupancy float64) int {
+	switch {
+	case occupancy <= 0:
+		return math.MaxInt32
+	case occupancy >= 1:
+		return 0
+	}
+	meanGap := meanStay * (1 - occupancy) / occupancy
+	if meanGap < 1 {
+		// Gaps shorter than a night are rounded to either none or one night.
+		if r.Float64() < meanGap {
+			return 1
+		}
+		return 0
+	}
+	return geometric(r, meanGap)
+}
+
+// geometric draws a length of at least one from a geometric distribution with the given mean.
+func geometric(r *rand.Rand, mean float64) int {
+	if mean <= 1 {
+		return 1
+	}
+	return 1 + int(math.Log(1-r.Float64())/math.Log(1-1/mean))
+}
+
+// SeedStore is the subset of the store used to write generated data.
+type SeedStore interface {
+	UpsertRoom(ctx context.Context, arg db.UpsertRoomParams) (db.Room, error)
+	BulkLoadAvailability(ctx context.Context, rows iter.Seq2[db.CopyRoomAvailabilityParams, error], options db.BulkOptions) (int64, error)
+}
+
+// SeedResult summarizes the data written by Seed.
+type SeedResult struct {
+	Rooms  int
+	Nights int64
+}
+
+// Seed writes the generated rooms, then streams their calendars to the store in bulk.
+// Existing rows are overwritten, except those already holding the generated values, which are left as they are:
+// running it twice with the same options writes nothing the second time, not even audit or outbox events.
+func Seed(ctx context.Context, store SeedStore, options SeedOptions, bulk db.BulkOptions) (SeedResult, error) {
+	if err := options.Validate(); err != nil {
+		return SeedResult{}, err
+	}
+
+	seeder := NewSeeder(options)
+	rooms := make([]db.UpsertRoomParams, options.Rooms)
+	var result SeedResult
+	for i := range rooms {
+		rooms[i] = seeder.Room(options.FirstRoomID + int32(i))
+		if _, err := store.UpsertRoom(ctx, rooms[i]); err != nil {
+			return result, fmt.Errorf("cannot seed room %d: %w", rooms[i].RoomID, err)
+		}
+		result.Rooms++
+	}
+
+	// Calendars are generated lazily, so memory use doesn't grow with the number of rooms.
+	nights := func(yield func(db.CopyRoomAvailabilityParams, error) bool) {
+		for _, room := range rooms {
+			for _, night := range seeder.Calendar(room) {
+				if !yield(night, nil) {
+					return
+				}
+			}
+		}
+	}
+
+	bulk.Replace = true
+	count, err := store.BulkLoadAvailability(ctx, nights, bulk)
+	result.Nights = count
+	if err != nil {
+		return result, fmt.Errorf("cannot seed availability: %w", err)
+	}
+
+	slog.Info("seeded database", slog.Int("rooms", result.Rooms), slog.Int64("nights", result.Nights))
+	return result, nil
+}
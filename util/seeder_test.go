@@ -0,0 +1,150 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/airbnb-api/db/sqlc"
+)
+
+func testSeedOptions() SeedOptions {
+	options := DefaultSeedOptions()
+	options.StartDate = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	return options
+}
+
+func TestSeederIsDeterministic(t *testing.T) {
+	options := testSeedOptions()
+
+	first := NewSeeder(options)
+	second := NewSeeder(options)
+	require.Equal(t, first.Room(100), second.Room(100))
+	require.Equal(t, first.Calendar(first.Room(100)), second.Calendar(second.Room(100)))
+
+	options.Seed++
+	third := NewSeeder(options)
+	require.NotEqual(t, first.Calendar(first.Room(100)), third.Calendar(third.Room(100)))
+}
+
+func TestSeederOccupancy(t *testing.T) {
+	options := testSeedOptions()
+	options.HorizonDays = 3650
+	options.HighSeasonMonths = nil
+	options.WeekendBooking = 0
+	options.LongStayChance = 0
+
+	seeder := NewSeeder(options)
+	for _, occupancy := range []float64{0.2, 0.5, 0.8} {
+		options.Occupancy = occupancy
+		seeder.options = options
+
+		booked := 0
+		calendar := seeder.Calendar(seeder.Room(100))
+		for _, night := range calendar {
+			if !night.IsAvailable {
+				booked++
+			}
+		}
+		require.InDelta(t, occupancy, float64(booked)/float64(len(calendar)), 0.05)
+	}
+}
+
+func TestSeederRates(t *testing.T) {
+	options := testSeedOptions()
+	options.HorizonDays = 365
+	options.HighSeasonMonths = []time.Month{time.December}
+
+	seeder := NewSeeder(options)
+	var weekday, weekend, highSeason []int32
+	for _, night := range seeder.Calendar(seeder.Room(100)) {
+		require.Zero(t, night.NightRate%50)
+		switch {
+		case night.Date.Time.Month() == time.December:
+			highSeason = append(highSeason, night.NightRate)
+		case night.Date.Time.Weekday() == time.Friday || night.Date.Time.Weekday() == time.Saturday:
+			weekend = append(weekend, night.NightRate)
+		default:
+			weekday = append(weekday, night.NightRate)
+		}
+	}
+
+	require.Greater(t, mean(weekend), mean(weekday))
+	require.Greater(t, mean(highSeason), mean(weekday))
+}
+
+func mean(values []int32) float64 {
+	var sum float64
+	for _, value := range values {
+		sum += float64(value)
+	}
+	return sum / float64(len(values))
+}
+
+// memorySeedStore records seeded rows keyed like the database's primary and unique keys.
+type memorySeedStore struct {
+	rooms   map[int32]db.UpsertRoomParams
+	nights  map[string]db.CopyRoomAvailabilityParams
+	batches int
+}
+
+func (store *memorySeedStore) UpsertRoom(_ context.Context, arg db.UpsertRoomParams) (db.Room, error) {
+	store.rooms[arg.RoomID] = arg
+	return db.Room{RoomID: arg.RoomID, MaxGuests: arg.MaxGuests, DefaultRate: arg.DefaultRate}, nil
+}
+
+func (store *memorySeedStore) BulkLoadAvailability(_ context.Context, rows iter.Seq2[db.CopyRoomAvailabilityParams, error], options db.BulkOptions) (int64, error) {
+	var count int64
+	for row, err := range rows {
+		if err != nil {
+			return count, err
+		}
+		key := fmt.Sprintf("%d/%s", row.RoomID, row.Date.Time.Format("2006-01-02"))
+		if _, ok := store.nights[key]; ok && !options.Replace {
+			return count, fmt.Errorf("duplicate night %s", key)
+		}
+		store.nights[key] = row
+		count++
+		if count%int64(options.BatchSize) == 0 {
+			store.batches++
+		}
+	}
+	return count, nil
+}
+
+func TestSeedIsIdempotent(t *testing.T) {
+	options := testSeedOptions()
+	options.Rooms = 3
+	options.HorizonDays = 30
+
+	store := &memorySeedStore{rooms: map[int32]db.UpsertRoomParams{}, nights: map[string]db.CopyRoomAvailabilityParams{}}
+	result, err := Seed(context.Background(), store, options, db.BulkOptions{BatchSize: 30})
+	require.NoError(t, err)
+	require.Equal(t, SeedResult{Rooms: 3, Nights: 90}, result)
+	require.Equal(t, 3, store.batches)
+
+	rooms := len(store.rooms)
+	nights := maps.Clone(store.nights)
+
+	// Rerunning overwrites the same rows instead of failing on duplicates.
+	_, err = Seed(context.Background(), store, options, db.BulkOptions{BatchSize: 30})
+	require.NoError(t, err)
+	require.Len(t, store.rooms, rooms)
+	require.Equal(t, nights, store.nights)
+}
+
+func TestSeedOptionsValidate(t *testing.T) {
+	require.NoError(t, DefaultSeedOptions().Validate())
+
+	options := DefaultSeedOptions()
+	options.Occupancy = 1.5
+	require.Error(t, options.Validate())
+
+	options = DefaultSeedOptions()
+	options.MinRate = 20000
+	require.Error(t, options.Validate())
+}